@@ -10,6 +10,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/i18nhttp"
 	"golang.org/x/text/language"
 )
 
@@ -33,10 +34,9 @@ func main() {
 	// bundle.MustLoadMessageFile("active.en.toml")
 	bundle.MustLoadMessageFile("active.es.toml")
 
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		lang := r.FormValue("lang")
-		accept := r.Header.Get("Accept-Language")
-		localizer := i18n.NewLocalizer(bundle, lang, accept)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		localizer := i18nhttp.Localizer(r.Context())
 
 		name := r.FormValue("name")
 		if name == "" {
@@ -91,6 +91,9 @@ func main() {
 		}
 	})
 
+	// Negotiate the language from the "lang" query parameter and the Accept-Language header.
+	middleware := i18nhttp.NewMiddleware(bundle)
+
 	fmt.Println("Listening on http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	log.Fatal(http.ListenAndServe(":8080", middleware.Handler(mux)))
 }
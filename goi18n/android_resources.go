@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// androidFormat reads and writes Android string resources (res/values-es/strings.xml).
+// Messages with plural forms are <plurals> whose item quantities are the plural forms.
+type androidFormat struct{}
+
+// name returns id with the characters that are not allowed in resource names replaced by underscores.
+func (androidFormat) name(id string) string {
+	name := []byte(id)
+	for i, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] >= '0' && name[0] <= '9' {
+		return "_" + string(name)
+	}
+	return string(name)
+}
+
+func (androidFormat) argument(n int, count bool) string {
+	if count {
+		return fmt.Sprintf("%%%d$d", n)
+	}
+	return fmt.Sprintf("%%%d$s", n)
+}
+
+func (androidFormat) isFile(path string) bool {
+	return filepath.Ext(path) == ".xml"
+}
+
+func (f androidFormat) write(outdir string, sourceLanguageTag language.Tag, messages map[language.Tag][]*nativeMessage) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(messages))
+	for langTag, langMessages := range messages {
+		dir := "values"
+		if langTag != sourceLanguageTag {
+			dir += "-" + androidQualifier(langTag)
+		}
+		var buf bytes.Buffer
+		buf.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<resources>\n")
+		for _, m := range langMessages {
+			if m.description != "" {
+				fmt.Fprintf(&buf, "    <!-- %s -->\n", strings.ReplaceAll(m.description, "--", "- -"))
+			}
+			if !m.plural {
+				fmt.Fprintf(&buf, "    <string name=\"%s\">%s</string>\n", m.name, androidEscape(m.forms[plural.Other]))
+				continue
+			}
+			fmt.Fprintf(&buf, "    <plurals name=\"%s\">\n", m.name)
+			for _, form := range sortedForms(m.forms) {
+				fmt.Fprintf(&buf, "        <item quantity=\"%s\">%s</item>\n", form, androidEscape(m.forms[form]))
+			}
+			buf.WriteString("    </plurals>\n")
+		}
+		buf.WriteString("</resources>\n")
+		files[filepath.Join(outdir, dir, "strings.xml")] = buf.Bytes()
+	}
+	return files, nil
+}
+
+func (androidFormat) read(path string, content []byte, sourceLanguageTag language.Tag) (map[language.Tag][]*nativeMessage, error) {
+	langTag, err := androidLanguage(filepath.Base(filepath.Dir(path)), sourceLanguageTag)
+	if err != nil {
+		return nil, err
+	}
+	var messages []*nativeMessage
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || (se.Name.Local != "string" && se.Name.Local != "plurals") {
+			continue
+		}
+		if xmlAttr(se, "translatable") == "false" {
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		m := &nativeMessage{
+			name:   xmlAttr(se, "name"),
+			plural: se.Name.Local == "plurals",
+			forms:  make(map[plural.Form]string),
+		}
+		if !m.plural {
+			s, err := androidText(d)
+			if err != nil {
+				return nil, err
+			}
+			m.forms[plural.Other] = s
+			messages = append(messages, m)
+			continue
+		}
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := tok.(xml.EndElement); ok {
+				break
+			}
+			item, ok := tok.(xml.StartElement)
+			if !ok {
+				continue
+			}
+			s, err := androidText(d)
+			if err != nil {
+				return nil, err
+			}
+			if item.Name.Local == "item" {
+				m.forms[plural.Form(xmlAttr(item, "quantity"))] = s
+			}
+		}
+		messages = append(messages, m)
+	}
+	return map[language.Tag][]*nativeMessage{langTag: messages}, nil
+}
+
+// androidQualifier returns the resource qualifier of langTag (e.g. es, pt-rBR, b+zh+Hant).
+func androidQualifier(langTag language.Tag) string {
+	base, script, region := langTag.Raw()
+	if script.String() == "Zzzz" {
+		if region.String() == "ZZ" {
+			return base.String()
+		}
+		return base.String() + "-r" + region.String()
+	}
+	return "b+" + strings.ReplaceAll(langTag.String(), "-", "+")
+}
+
+// androidLanguage returns the language of the resource directory dir.
+// The default directory (values) contains the source language.
+func androidLanguage(dir string, sourceLanguageTag language.Tag) (language.Tag, error) {
+	if dir == "values" {
+		return sourceLanguageTag, nil
+	}
+	qualifier, ok := strings.CutPrefix(dir, "values-")
+	if !ok {
+		return language.Und, fmt.Errorf("%s is not a values directory", dir)
+	}
+	if tag, ok := strings.CutPrefix(qualifier, "b+"); ok {
+		qualifier = strings.ReplaceAll(tag, "+", "-")
+	} else {
+		qualifier = strings.Replace(qualifier, "-r", "-", 1)
+	}
+	langTag, err := language.Parse(qualifier)
+	if err != nil {
+		return language.Und, fmt.Errorf("directory %s: %s", dir, err)
+	}
+	return langTag, nil
+}
+
+var androidEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`'`, `\'`,
+	"\n", `\n`,
+	"\t", `\t`,
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+// androidEscape escapes s for the content of a string resource.
+func androidEscape(s string) string {
+	escaped := androidEscaper.Replace(s)
+	if strings.HasPrefix(escaped, "@") || strings.HasPrefix(escaped, "?") {
+		escaped = `\` + escaped
+	}
+	if strings.TrimSpace(s) != s || strings.Contains(s, "  ") {
+		// Whitespace is only kept in double quotes.
+		escaped = `"` + escaped + `"`
+	}
+	return escaped
+}
+
+// androidText reads the content of the current element and returns it unescaped.
+// Markup is kept, except for <xliff:g> elements, which only mark text that must not be translated.
+func androidText(d *xml.Decoder) (string, error) {
+	var buf strings.Builder
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			buf.WriteString(string(t))
+		case xml.StartElement:
+			depth++
+			if t.Name.Local != "g" {
+				buf.WriteString("<" + t.Name.Local)
+				for _, a := range t.Attr {
+					fmt.Fprintf(&buf, ` %s="%s"`, a.Name.Local, escapeXMLAttr(a.Value))
+				}
+				buf.WriteString(">")
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				return androidUnescape(buf.String()), nil
+			}
+			depth--
+			if t.Name.Local != "g" {
+				buf.WriteString("</" + t.Name.Local + ">")
+			}
+		}
+	}
+}
+
+// androidUnescape returns the text of the string resource s, like the Android resource compiler:
+// backslash escapes are replaced, unescaped double quotes are removed and whitespace outside of
+// double quotes is collapsed.
+func androidUnescape(s string) string {
+	var buf strings.Builder
+	quoted := false
+	space := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '\r') {
+			space = true
+			continue
+		}
+		if space {
+			if buf.Len() > 0 {
+				buf.WriteByte(' ')
+			}
+			space = false
+		}
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 't':
+				buf.WriteByte('\t')
+			case 'u':
+				if i+5 <= len(s) {
+					if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+						buf.WriteRune(rune(r))
+						i += 4
+						continue
+					}
+				}
+				buf.WriteByte('u')
+			default:
+				buf.WriteByte(s[i])
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+func xmlAttr(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// sortedForms returns the plural forms of forms in CLDR order.
+func sortedForms(forms map[plural.Form]string) []plural.Form {
+	set := make(map[plural.Form]struct{}, len(forms))
+	for form := range forms {
+		set[form] = struct{}{}
+	}
+	return sortedPluralFormSet(set)
+}
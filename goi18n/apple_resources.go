@@ -0,0 +1,459 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// appleArgument returns the printf conversion of the nth argument of Apple platforms,
+// which are objects (e.g. String) unless they are a plural count.
+func appleArgument(n int, count bool) string {
+	if count {
+		return fmt.Sprintf("%%%d$lld", n)
+	}
+	return fmt.Sprintf("%%%d$@", n)
+}
+
+// xcstringsFormat reads and writes an Apple String Catalog (Localizable.xcstrings),
+// which contains the messages of every language.
+// Messages with plural forms vary by plural.
+type xcstringsFormat struct{}
+
+const (
+	xcstringsFile       = "Localizable.xcstrings"
+	xcstringsTranslated = "translated"
+)
+
+type xcstringsCatalog struct {
+	SourceLanguage string                      `json:"sourceLanguage"`
+	Strings        map[string]*xcstringsString `json:"strings"`
+	Version        string                      `json:"version"`
+}
+
+type xcstringsString struct {
+	Comment         string                            `json:"comment,omitempty"`
+	ExtractionState string                            `json:"extractionState,omitempty"`
+	Localizations   map[string]*xcstringsLocalization `json:"localizations,omitempty"`
+}
+
+type xcstringsLocalization struct {
+	StringUnit    *xcstringsStringUnit       `json:"stringUnit,omitempty"`
+	Variations    map[string]json.RawMessage `json:"variations,omitempty"`
+	Substitutions json.RawMessage            `json:"substitutions,omitempty"`
+}
+
+type xcstringsStringUnit struct {
+	State string `json:"state"`
+	Value string `json:"value"`
+}
+
+func (xcstringsFormat) name(id string) string {
+	return id
+}
+
+func (xcstringsFormat) argument(n int, count bool) string {
+	return appleArgument(n, count)
+}
+
+func (xcstringsFormat) isFile(path string) bool {
+	return filepath.Ext(path) == ".xcstrings"
+}
+
+func (xcstringsFormat) write(outdir string, sourceLanguageTag language.Tag, messages map[language.Tag][]*nativeMessage) (map[string][]byte, error) {
+	catalog := &xcstringsCatalog{
+		SourceLanguage: sourceLanguageTag.String(),
+		Strings:        make(map[string]*xcstringsString),
+		Version:        "1.0",
+	}
+	for langTag, langMessages := range messages {
+		for _, m := range langMessages {
+			s := catalog.Strings[m.name]
+			if s == nil {
+				s = &xcstringsString{
+					Comment:         m.description,
+					ExtractionState: "manual",
+					Localizations:   make(map[string]*xcstringsLocalization),
+				}
+				catalog.Strings[m.name] = s
+			}
+			l := &xcstringsLocalization{}
+			if m.plural {
+				forms := make(map[string]*xcstringsLocalization, len(m.forms))
+				for form, value := range m.forms {
+					forms[string(form)] = &xcstringsLocalization{
+						StringUnit: &xcstringsStringUnit{State: xcstringsTranslated, Value: value},
+					}
+				}
+				data, err := json.Marshal(forms)
+				if err != nil {
+					return nil, err
+				}
+				l.Variations = map[string]json.RawMessage{"plural": data}
+			} else {
+				l.StringUnit = &xcstringsStringUnit{State: xcstringsTranslated, Value: m.forms[plural.Other]}
+			}
+			s.Localizations[langTag.String()] = l
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(catalog); err != nil {
+		return nil, err
+	}
+	return map[string][]byte{filepath.Join(outdir, xcstringsFile): buf.Bytes()}, nil
+}
+
+func (xcstringsFormat) read(path string, content []byte, sourceLanguageTag language.Tag) (map[language.Tag][]*nativeMessage, error) {
+	var catalog xcstringsCatalog
+	if err := json.Unmarshal(content, &catalog); err != nil {
+		return nil, err
+	}
+	messages := make(map[language.Tag][]*nativeMessage)
+	for name, s := range catalog.Strings {
+		for lang, l := range s.Localizations {
+			langTag, err := language.Parse(lang)
+			if err != nil {
+				return nil, fmt.Errorf("string %q: %s", name, err)
+			}
+			if len(l.Substitutions) > 0 {
+				return nil, fmt.Errorf("string %q: %s: substitutions are not supported", name, lang)
+			}
+			m := &nativeMessage{name: name, forms: make(map[plural.Form]string)}
+			switch {
+			case l.StringUnit != nil:
+				if l.StringUnit.State == xcstringsTranslated {
+					m.forms[plural.Other] = l.StringUnit.Value
+				}
+			case l.Variations["plural"] != nil:
+				m.plural = true
+				var forms map[string]*xcstringsLocalization
+				if err := json.Unmarshal(l.Variations["plural"], &forms); err != nil {
+					return nil, fmt.Errorf("string %q: %s: %s", name, lang, err)
+				}
+				for form, fl := range forms {
+					if fl.StringUnit != nil && fl.StringUnit.State == xcstringsTranslated {
+						m.forms[plural.Form(form)] = fl.StringUnit.Value
+					}
+				}
+			default:
+				return nil, fmt.Errorf("string %q: %s: only plural variations are supported", name, lang)
+			}
+			messages[langTag] = append(messages[langTag], m)
+		}
+	}
+	return messages, nil
+}
+
+// stringsdictFormat reads and writes Apple strings files (es.lproj/Localizable.strings) with the
+// messages without plural forms and stringsdict files (es.lproj/Localizable.stringsdict) with the
+// messages with plural forms.
+type stringsdictFormat struct{}
+
+const (
+	stringsFile       = "Localizable.strings"
+	stringsdictFile   = "Localizable.stringsdict"
+	stringsdictHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`
+	// stringsdictVariable is the variable of the plural count in the format of stringsdict messages.
+	stringsdictVariable = "count"
+)
+
+func (stringsdictFormat) name(id string) string {
+	return id
+}
+
+func (stringsdictFormat) argument(n int, count bool) string {
+	return appleArgument(n, count)
+}
+
+func (stringsdictFormat) isFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".strings" || ext == ".stringsdict"
+}
+
+func (stringsdictFormat) write(outdir string, sourceLanguageTag language.Tag, messages map[language.Tag][]*nativeMessage) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for langTag, langMessages := range messages {
+		var strs, dict bytes.Buffer
+		for _, m := range langMessages {
+			if !m.plural {
+				if m.description != "" {
+					fmt.Fprintf(&strs, "/* %s */\n", strings.ReplaceAll(m.description, "*/", "* /"))
+				}
+				fmt.Fprintf(&strs, "\"%s\" = \"%s\";\n\n", stringsEscaper.Replace(m.name), stringsEscaper.Replace(m.forms[plural.Other]))
+				continue
+			}
+			if m.description != "" {
+				fmt.Fprintf(&dict, "\t<!-- %s -->\n", strings.ReplaceAll(m.description, "--", "- -"))
+			}
+			fmt.Fprintf(&dict, "\t<key>%s</key>\n\t<dict>\n", escapeXML(m.name))
+			fmt.Fprintf(&dict, "\t\t<key>NSStringLocalizedFormatKey</key>\n\t\t<string>%%1$#@%s@</string>\n", stringsdictVariable)
+			fmt.Fprintf(&dict, "\t\t<key>%s</key>\n\t\t<dict>\n", stringsdictVariable)
+			dict.WriteString("\t\t\t<key>NSStringFormatSpecTypeKey</key>\n\t\t\t<string>NSStringPluralRuleType</string>\n")
+			dict.WriteString("\t\t\t<key>NSStringFormatValueTypeKey</key>\n\t\t\t<string>lld</string>\n")
+			for _, form := range sortedForms(m.forms) {
+				fmt.Fprintf(&dict, "\t\t\t<key>%s</key>\n\t\t\t<string>%s</string>\n", form, escapeXML(m.forms[form]))
+			}
+			dict.WriteString("\t\t</dict>\n\t</dict>\n")
+		}
+		dir := filepath.Join(outdir, langTag.String()+".lproj")
+		if strs.Len() > 0 {
+			files[filepath.Join(dir, stringsFile)] = strs.Bytes()
+		}
+		if dict.Len() > 0 {
+			files[filepath.Join(dir, stringsdictFile)] = []byte(stringsdictHeader + dict.String() + "</dict>\n</plist>\n")
+		}
+	}
+	return files, nil
+}
+
+func (stringsdictFormat) read(path string, content []byte, sourceLanguageTag language.Tag) (map[language.Tag][]*nativeMessage, error) {
+	dir := filepath.Base(filepath.Dir(path))
+	lang, ok := strings.CutSuffix(dir, ".lproj")
+	if !ok {
+		return nil, fmt.Errorf("%s is not an lproj directory", dir)
+	}
+	langTag := sourceLanguageTag
+	if lang != "Base" {
+		var err error
+		if langTag, err = language.Parse(lang); err != nil {
+			return nil, fmt.Errorf("directory %s: %s", dir, err)
+		}
+	}
+	var messages []*nativeMessage
+	var err error
+	if filepath.Ext(path) == ".strings" {
+		messages, err = readStrings(content)
+	} else {
+		messages, err = readStringsdict(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[language.Tag][]*nativeMessage{langTag: messages}, nil
+}
+
+var stringsEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\t", `\t`,
+)
+
+// readStrings returns the messages in the strings file content ("name" = "value";).
+func readStrings(content []byte) ([]*nativeMessage, error) {
+	s := string(content)
+	var messages []*nativeMessage
+	for {
+		s = skipStringsSpace(s)
+		if s == "" {
+			return messages, nil
+		}
+		name, rest, err := readStringsQuoted(s)
+		if err != nil {
+			return nil, err
+		}
+		rest = skipStringsSpace(rest)
+		if !strings.HasPrefix(rest, "=") {
+			return nil, fmt.Errorf("string %q: expected =", name)
+		}
+		value, rest, err := readStringsQuoted(skipStringsSpace(rest[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("string %q: %s", name, err)
+		}
+		rest = skipStringsSpace(rest)
+		if !strings.HasPrefix(rest, ";") {
+			return nil, fmt.Errorf("string %q: expected ;", name)
+		}
+		s = rest[1:]
+		messages = append(messages, &nativeMessage{name: name, forms: map[plural.Form]string{plural.Other: value}})
+	}
+}
+
+// skipStringsSpace returns s without leading whitespace and comments.
+func skipStringsSpace(s string) string {
+	for {
+		s = strings.TrimLeft(s, " \t\r\n")
+		switch {
+		case strings.HasPrefix(s, "/*"):
+			end := strings.Index(s, "*/")
+			if end < 0 {
+				return ""
+			}
+			s = s[end+2:]
+		case strings.HasPrefix(s, "//"):
+			end := strings.IndexByte(s, '\n')
+			if end < 0 {
+				return ""
+			}
+			s = s[end+1:]
+		default:
+			return s
+		}
+	}
+}
+
+// readStringsQuoted returns the unescaped quoted string at the start of s and the rest of s.
+func readStringsQuoted(s string) (value, rest string, err error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", fmt.Errorf("expected quoted string")
+	}
+	var buf strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			return buf.String(), s[i+1:], nil
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 't':
+				buf.WriteByte('\t')
+			case 'r':
+				buf.WriteByte('\r')
+			case 'U', 'u':
+				if i+5 <= len(s) {
+					if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+						buf.WriteRune(rune(r))
+						i += 4
+						continue
+					}
+				}
+				buf.WriteByte(s[i])
+			default:
+				buf.WriteByte(s[i])
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted string")
+}
+
+var stringsdictVariableRegexp = regexp.MustCompile(`%(?:([1-9][0-9]*)\$)?#@([^@]+)@`)
+
+// readStringsdict returns the messages in the stringsdict file content.
+// The format of each message may contain text and arguments around one plural variable.
+// Arguments without a position in the plural forms are the plural count, which is the
+// argument of the variable (the first argument unless the variable has a position).
+func readStringsdict(content []byte) ([]*nativeMessage, error) {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	var root map[string]interface{}
+	for root == nil {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "dict" {
+			if root, err = readPlistDict(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var messages []*nativeMessage
+	for name, v := range root {
+		dict, _ := v.(map[string]interface{})
+		format, ok := dict["NSStringLocalizedFormatKey"].(string)
+		if !ok {
+			return nil, fmt.Errorf("string %q: missing NSStringLocalizedFormatKey", name)
+		}
+		m := &nativeMessage{name: name, forms: make(map[plural.Form]string)}
+		variables := stringsdictVariableRegexp.FindAllStringSubmatchIndex(format, -1)
+		switch len(variables) {
+		case 0:
+			m.forms[plural.Other] = format
+		case 1:
+			m.plural = true
+			loc := variables[0]
+			position := "1"
+			if loc[2] >= 0 {
+				position = format[loc[2]:loc[3]]
+			}
+			variableName := format[loc[4]:loc[5]]
+			variable, _ := dict[variableName].(map[string]interface{})
+			if variable["NSStringFormatSpecTypeKey"] != "NSStringPluralRuleType" {
+				return nil, fmt.Errorf("string %q: variable %s is not a plural rule", name, variableName)
+			}
+			for _, form := range plural.Forms {
+				if value, ok := variable[string(form)].(string); ok {
+					m.forms[form] = format[:loc[0]] + positionArguments(value, position) + format[loc[1]:]
+				}
+			}
+		default:
+			return nil, fmt.Errorf("string %q: more than one variable is not supported", name)
+		}
+		messages = append(messages, m)
+	}
+	return messages, nil
+}
+
+// unpositionedArgumentRegexp matches escaped percent signs and printf conversions without a position (e.g. "%d").
+var unpositionedArgumentRegexp = regexp.MustCompile(`%(?:%|` + printfConversion + `)`)
+
+// positionArguments returns s with the position of the arguments without a position set to position.
+func positionArguments(s, position string) string {
+	return unpositionedArgumentRegexp.ReplaceAllStringFunc(s, func(arg string) string {
+		if arg == "%%" {
+			return arg
+		}
+		return "%" + position + "$" + arg[1:]
+	})
+}
+
+// readPlistDict reads the content of a plist <dict> element.
+// Values are strings or dictionaries; other values are ignored.
+func readPlistDict(d *xml.Decoder) (map[string]interface{}, error) {
+	dict := make(map[string]interface{})
+	var key string
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return dict, nil
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "key":
+				if err := d.DecodeElement(&key, &t); err != nil {
+					return nil, err
+				}
+			case "string":
+				var s string
+				if err := d.DecodeElement(&s, &t); err != nil {
+					return nil, err
+				}
+				dict[key] = s
+			case "dict":
+				if dict[key], err = readPlistDict(d); err != nil {
+					return nil, err
+				}
+			default:
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func usageCompile() {
+	fmt.Fprintf(os.Stderr, `usage: goi18n compile [options] [message files]
+
+Compile reads the messages in the message files and writes them to a binary file
+that programs load faster than message files.
+
+	messages.i18n
+		This file contains the messages of every language.
+
+Load the compiled messages into your bundle, for example from an embedded file.
+
+	//go:embed messages.i18n
+	var compiledMessages []byte
+
+	bundle.MustLoadCompiled(bytes.NewReader(compiledMessages))
+
+Compiled messages only contain what is needed to localize messages:
+descriptions and hashes are not compiled.
+
+Flags:
+
+	-outdir directory
+		Write the compiled messages to this directory.
+		Default: .
+`)
+}
+
+type compileCommand struct {
+	messageFiles []string
+	outdir       string
+}
+
+func (cc *compileCommand) name() string {
+	return "compile"
+}
+
+func (cc *compileCommand) parse(args []string) error {
+	flags := flag.NewFlagSet("compile", flag.ExitOnError)
+	flags.Usage = usageCompile
+
+	flags.StringVar(&cc.outdir, "outdir", ".", "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cc.messageFiles = flags.Args()
+	return nil
+}
+
+func (cc *compileCommand) execute() error {
+	if len(cc.messageFiles) < 1 {
+		return fmt.Errorf("need at least one message file to compile")
+	}
+	inFiles := make(map[string][]byte)
+	for _, path := range cc.messageFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		inFiles[path] = content
+	}
+	content, err := compile(inFiles)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(cc.outdir, "messages.i18n"), content, 0666)
+}
+
+// compile returns the compiled messages of the message files.
+func compile(messageFiles map[string][]byte) ([]byte, error) {
+	paths := make([]string, 0, len(messageFiles))
+	for path := range messageFiles {
+		paths = append(paths, path)
+	}
+	// Languages are compiled in the order of the paths of their message files.
+	sort.Strings(paths)
+	// Compiled messages do not include the default language of the bundle.
+	bundle := i18n.NewBundle(language.Und)
+	for format, unmarshalFunc := range unmarshalFuncs {
+		bundle.RegisterUnmarshalFunc(format, unmarshalFunc)
+	}
+	for _, path := range paths {
+		if _, err := bundle.ParseMessageFileBytes(messageFiles[path], path); err != nil {
+			return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := bundle.WriteCompiled(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func TestCompile(t *testing.T) {
+	messageFiles := map[string][]byte{
+		"active.en.toml": []byte(`
+Hello = "Hello {{.Name}}"
+
+[PersonCats]
+description = "The number of cats a person has"
+one = "{{.Name}} has {{.Count}} cat."
+other = "{{.Name}} has {{.Count}} cats."
+`),
+		"active.es.json": []byte(`{
+	"Hello": {"hash": "v2-sha256-1", "other": "Hola {{.Name}}"},
+	"PersonCats": {"one": "{{.Name}} tiene {{.Count}} gato.", "other": "{{.Name}} tiene {{.Count}} gatos."}
+}`),
+	}
+	compiled, err := compile(messageFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := compile(messageFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(compiled, again) {
+		t.Errorf("expected compiled messages to be deterministic")
+	}
+
+	bundle := i18n.NewBundle(language.English)
+	if err := bundle.LoadCompiled(bytes.NewReader(compiled)); err != nil {
+		t.Fatal(err)
+	}
+	localizer := i18n.NewLocalizer(bundle, "es")
+	actual := localizer.MustLocalize(&i18n.LocalizeConfig{
+		MessageID:    "PersonCats",
+		PluralCount:  2,
+		TemplateData: map[string]interface{}{"Name": "Ana", "Count": 2},
+	})
+	if expected := "Ana tiene 2 gatos."; actual != expected {
+		t.Errorf("expected %q; got %q", expected, actual)
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	_, err := compile(map[string][]byte{"active.en.toml": []byte(`Hello = `)})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
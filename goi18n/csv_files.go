@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// csvFormat is the format of spreadsheets with one row per message id and plural form
+// and one column per language.
+const csvFormat = "csv"
+
+// csvColumns are the columns of CSV files before the column of each language.
+var csvColumns = []string{"id", "form", "description", "hash"}
+
+// utf8BOM is written by spreadsheet programs at the start of UTF-8 CSV files.
+const utf8BOM = "\ufeff"
+
+func isCSVFile(path string) bool {
+	return filepath.Ext(path) == ".csv"
+}
+
+// exportCSV returns the CSV file with the source messages and their translations in every language.
+// Each message has a row for the plural forms of the source message and of every language
+// that translates a message with plural forms. Cells of translations that are missing or that
+// use a plural form that is not used by their language are empty.
+func exportCSV(outdir string, sourceLanguageTag language.Tag, merged *mergedMessageTemplates) (*fileSystemOp, error) {
+	pluralRules := plural.DefaultRules()
+	langTags := []language.Tag{sourceLanguageTag}
+	for langTag := range merged.all {
+		if langTag != sourceLanguageTag {
+			langTags = append(langTags, langTag)
+		}
+	}
+	sort.Slice(langTags[1:], func(i, j int) bool {
+		return langTags[i+1].String() < langTags[j+1].String()
+	})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := append([]string{}, csvColumns...)
+	for _, langTag := range langTags {
+		header = append(header, langTag.String())
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, id := range sortedIDs(merged.source) {
+		src := merged.source[id]
+		forms := make(map[plural.Form]struct{}, len(src.PluralTemplates))
+		for form := range src.PluralTemplates {
+			forms[form] = struct{}{}
+		}
+		langForms := make(map[language.Tag]map[plural.Form]struct{}, len(langTags))
+		for _, langTag := range langTags[1:] {
+			langForms[langTag] = map[plural.Form]struct{}{plural.Other: {}}
+			if len(src.PluralTemplates) > 1 {
+				langForms[langTag] = pluralRules.Rule(langTag).PluralForms
+				for form := range langForms[langTag] {
+					forms[form] = struct{}{}
+				}
+			}
+		}
+		for _, form := range sortedPluralFormSet(forms) {
+			srcTemplate := src.PluralTemplates[form]
+			if srcTemplate == nil {
+				srcTemplate = src.PluralTemplates[plural.Other]
+			}
+			record := []string{id, string(form), src.Description, src.Hash, srcTemplate.Src}
+			for _, langTag := range langTags[1:] {
+				cell := ""
+				if _, ok := langForms[langTag][form]; ok {
+					if t := merged.all[langTag][id]; t != nil && t.PluralTemplates[form] != nil {
+						cell = t.PluralTemplates[form].Src
+					}
+				}
+				record = append(record, cell)
+			}
+			if err := w.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return &fileSystemOp{writeFiles: map[string][]byte{
+		filepath.Join(outdir, "messages.csv"): buf.Bytes(),
+	}}, nil
+}
+
+// importCSV returns the message values by language of the translations in the CSV files.
+// Translations are checked like merge checks translated message files: translations of
+// different source content (whose hash does not match the source message) are skipped
+// and translations whose placeholders differ from the source message are reported.
+func importCSV(files map[string][]byte, sourceLanguageTag language.Tag, source map[string]*i18n.MessageTemplate) (map[language.Tag]map[string]interface{}, []string, error) {
+	values := make(map[language.Tag]map[string]interface{})
+	hashes := make(map[language.Tag]map[string]string)
+	var warnings []string
+	for path, content := range files {
+		records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte(utf8BOM)))).ReadAll()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %s", path, err)
+		}
+		langTags, err := csvLanguages(records)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %s", path, err)
+		}
+		for _, record := range records[1:] {
+			id, form, h := record[0], plural.Form(record[1]), record[3]
+			if !containsPluralForm(plural.Forms, form) {
+				return nil, nil, fmt.Errorf("failed to read %s: %s: invalid plural form %q", path, id, form)
+			}
+			for i, langTag := range langTags {
+				cell := record[len(csvColumns)+i]
+				if langTag == sourceLanguageTag || cell == "" {
+					continue
+				}
+				if source[id] == nil {
+					warnings = append(warnings, fmt.Sprintf("%s: %s: no source message", langTag, id))
+					continue
+				}
+				if values[langTag] == nil {
+					values[langTag] = make(map[string]interface{})
+					hashes[langTag] = make(map[string]string)
+				}
+				v, ok := values[langTag][id].(map[string]interface{})
+				if !ok {
+					v = map[string]interface{}{"hash": h}
+					if src := source[id]; src.LeftDelim != "" || src.RightDelim != "" {
+						v["leftDelim"], v["rightDelim"] = src.LeftDelim, src.RightDelim
+					}
+					values[langTag][id] = v
+					hashes[langTag][id] = h
+				}
+				if hashes[langTag][id] != h {
+					return nil, nil, fmt.Errorf("failed to read %s: %s: different hashes", path, id)
+				}
+				v[string(form)] = cell
+			}
+		}
+	}
+	for langTag, messages := range values {
+		for id, value := range messages {
+			m, err := i18n.NewMessage(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s: %s", langTag, id, err)
+			}
+			m.ID = id
+			src := source[id]
+			if !translatedFrom(m.Hash, src) {
+				warnings = append(warnings, fmt.Sprintf("%s: %s: translated from different source content", langTag, id))
+				delete(messages, id)
+				continue
+			}
+			if _, warning := checkPlaceholders(src, i18n.NewMessageTemplate(m), langTag, placeholdersWarn); warning != "" {
+				warnings = append(warnings, warning)
+			}
+		}
+		if len(messages) == 0 {
+			delete(values, langTag)
+		}
+	}
+	return values, warnings, nil
+}
+
+// csvLanguages returns the language of each language column of the CSV file with records.
+func csvLanguages(records [][]string) ([]language.Tag, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no header")
+	}
+	header := records[0]
+	if len(header) < len(csvColumns) {
+		return nil, fmt.Errorf("expected columns %q", csvColumns)
+	}
+	for i, column := range csvColumns {
+		if header[i] != column {
+			return nil, fmt.Errorf("expected column %q; got %q", column, header[i])
+		}
+	}
+	langTags := make([]language.Tag, 0, len(header)-len(csvColumns))
+	for _, column := range header[len(csvColumns):] {
+		langTag, err := language.Parse(column)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %s", column, err)
+		}
+		langTags = append(langTags, langTag)
+	}
+	return langTags, nil
+}
+
+func containsPluralForm(forms []plural.Form, form plural.Form) bool {
+	for _, f := range forms {
+		if f == form {
+			return true
+		}
+	}
+	return false
+}
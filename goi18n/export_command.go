@@ -0,0 +1,185 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+func usageExport() {
+	fmt.Fprintf(os.Stderr, `usage: goi18n export [options] [message files]
+
+Export writes the messages in the message files as the string resources of mobile platforms
+or the message files of other i18n libraries, so that Go programs and apps use the same messages.
+Only complete translations are exported, except to CSV files.
+
+	goi18n export -format android -outdir app/src/main/res active.*.toml
+
+For string resources, template fields (e.g. {{.Name}}) are converted to printf arguments (e.g. %%1$s)
+in the order of their first use in the source message. The first argument of messages with plural forms
+is the plural count, which is printed by the field PluralCount or Count.
+
+For ARB files, template fields are ICU arguments (e.g. {Name}) and plural forms are the options of
+a plural argument whose variable is the field PluralCount or Count. For i18next files, template fields
+are interpolations with the same name (e.g. {{Name}}) and plural forms are key suffixes (e.g. Cats_one).
+
+Messages with other template actions are not exported.
+
+CSV files are spreadsheets for translators (messages.csv) with a row for each message id and plural form
+and the columns id, form, description, hash and one column per language, starting with the source language.
+They contain every message and all translations, including incomplete ones. Cells of missing translations
+are empty, as are the cells of plural forms that a language does not use.
+
+Flags:
+
+	-format format
+		Write files in this format.
+		Supported formats:
+			android      values/strings.xml (source language) and values-es/strings.xml
+			             with <plurals> for messages with plural forms
+			xcstrings    Localizable.xcstrings (Apple String Catalog) with every language
+			stringsdict  es.lproj/Localizable.strings and es.lproj/Localizable.stringsdict
+			             for messages with plural forms
+			arb          app_es.arb (Flutter)
+			i18next      es/translation.json (i18next JSON v4)
+			csv          messages.csv with every language
+
+	-sourceLanguage tag
+		The language of the source messages (e.g. en, en-US, zh-Hant-CN).
+		Default: en
+
+	-outdir directory
+		Write files to this directory.
+		Default: .
+`)
+}
+
+// supportedExportFormat returns true if goi18n export and import support format.
+func supportedExportFormat(format string) bool {
+	_, native := nativeFormats[format]
+	_, library := libraryFormats[format]
+	return native || library || format == csvFormat
+}
+
+type exportCommand struct {
+	messageFiles   []string
+	format         string
+	sourceLanguage languageTag
+	outdir         string
+}
+
+func (ec *exportCommand) name() string {
+	return "export"
+}
+
+func (ec *exportCommand) parse(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.Usage = usageExport
+
+	flags.StringVar(&ec.format, "format", "", "")
+	flags.Var(&ec.sourceLanguage, "sourceLanguage", "en")
+	flags.StringVar(&ec.outdir, "outdir", ".", "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if !supportedExportFormat(ec.format) {
+		return fmt.Errorf("unsupported format: %q", ec.format)
+	}
+	ec.messageFiles = flags.Args()
+	return nil
+}
+
+func (ec *exportCommand) execute() error {
+	if len(ec.messageFiles) < 1 {
+		return fmt.Errorf("need at least one message file to export")
+	}
+	inFiles := make(map[string][]byte)
+	for _, path := range ec.messageFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		inFiles[path] = content
+	}
+	ops, err := export(inFiles, ec.sourceLanguage.Tag(), ec.outdir, ec.format)
+	if err != nil {
+		return err
+	}
+	for _, warning := range ops.warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	for path, content := range ops.writeFiles {
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// export returns the files of format with the messages in messageFiles.
+func export(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdir, format string) (*fileSystemOp, error) {
+	merged, err := mergeMessageTemplates(messageFiles, sourceLanguageTag, placeholdersIgnore)
+	if err != nil {
+		return nil, err
+	}
+	if format == csvFormat {
+		return exportCSV(outdir, sourceLanguageTag, merged)
+	}
+	complete := completeTranslations(merged, sourceLanguageTag)
+	var ops *fileSystemOp
+	if f, ok := libraryFormats[format]; ok {
+		if ops, err = exportLibrary(f, outdir, sourceLanguageTag, merged.source, complete); err != nil {
+			return nil, err
+		}
+	} else {
+		f := nativeFormats[format]
+		messages, warnings, err := exportNative(f, merged.source, complete)
+		if err != nil {
+			return nil, err
+		}
+		writeFiles, err := f.write(outdir, sourceLanguageTag, messages)
+		if err != nil {
+			return nil, err
+		}
+		ops = &fileSystemOp{writeFiles: writeFiles, warnings: warnings}
+	}
+	sort.Strings(ops.warnings)
+	return ops, nil
+}
+
+// completeTranslations returns the source messages and the complete translations of each language.
+func completeTranslations(merged *mergedMessageTemplates, sourceLanguageTag language.Tag) map[language.Tag]map[string]*i18n.MessageTemplate {
+	pluralRules := plural.DefaultRules()
+	complete := make(map[language.Tag]map[string]*i18n.MessageTemplate, len(merged.all))
+	for langTag, templates := range merged.all {
+		if langTag == sourceLanguageTag {
+			complete[langTag] = templates
+			continue
+		}
+		pluralRule := pluralRules.Rule(langTag)
+		if pluralRule == nil {
+			continue
+		}
+		for id, t := range templates {
+			active, translate := activeDst(merged.source[id], t, pluralRule)
+			if translate != nil {
+				continue
+			}
+			if complete[langTag] == nil {
+				complete[langTag] = make(map[string]*i18n.MessageTemplate)
+			}
+			complete[langTag][id] = active
+		}
+	}
+	return complete
+}
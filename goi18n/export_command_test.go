@@ -0,0 +1,335 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+var exportMessageFiles = map[string][]byte{
+	"active.en.toml": []byte(`
+Hello = "Hello {{.Name}}, it's 100% {{.Adjective}}!"
+Spaces = "  Indented"
+Unsupported = "{{if .Name}}Hello{{end}}"
+
+[Delims]
+leftDelim = "<<"
+rightDelim = ">>"
+other = "<<.Name>> says hi"
+
+[PersonCats]
+description = "The number of cats a person has"
+one = "{{.Name}} has {{.Count}} cat."
+other = "{{.Name}} has {{.Count}} cats."
+`),
+	"active.es.toml": []byte(`
+[Hello]
+other = "¡Hola {{.Name}}, es 100% {{.Adjective}}!"
+
+[PersonCats]
+one = "{{.Name}} tiene {{.Count}} gato."
+many = "{{.Name}} tiene {{.Count}} de gatos."
+other = "{{.Name}} tiene {{.Count}} gatos."
+`),
+	"active.ja.toml": []byte(`
+[PersonCats]
+other = "{{.Name}}は猫を{{.Count}}匹飼っています。"
+`),
+	"active.ru.toml": []byte(`
+[PersonCats]
+one = "У {{.Name}} {{.Count}} кошка."
+other = "У {{.Name}} {{.Count}} кошки."
+`),
+}
+
+func TestExport(t *testing.T) {
+	printfWarnings := []string{"Unsupported: template action {{if .Name}}Hello{{end}} can not be converted"}
+	libraryWarnings := []string{`en: message "Unsupported": template action {{if .Name}}Hello{{end}} can not be converted`}
+	tests := []struct {
+		format   string
+		expected map[string]string
+		warnings []string
+	}{
+		{
+			format:   "android",
+			warnings: printfWarnings,
+			expected: map[string]string{
+				"res/values/strings.xml": `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="Delims">%1$s says hi</string>
+    <string name="Hello">Hello %1$s, it\'s 100%% %2$s!</string>
+    <!-- The number of cats a person has -->
+    <plurals name="PersonCats">
+        <item quantity="one">%2$s has %1$d cat.</item>
+        <item quantity="other">%2$s has %1$d cats.</item>
+    </plurals>
+    <string name="Spaces">"  Indented"</string>
+</resources>
+`,
+				"res/values-es/strings.xml": `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="Hello">¡Hola %1$s, es 100%% %2$s!</string>
+    <!-- The number of cats a person has -->
+    <plurals name="PersonCats">
+        <item quantity="one">%2$s tiene %1$d gato.</item>
+        <item quantity="many">%2$s tiene %1$d de gatos.</item>
+        <item quantity="other">%2$s tiene %1$d gatos.</item>
+    </plurals>
+</resources>
+`,
+				"res/values-ja/strings.xml": `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <!-- The number of cats a person has -->
+    <plurals name="PersonCats">
+        <item quantity="other">%2$sは猫を%1$d匹飼っています。</item>
+    </plurals>
+</resources>
+`,
+			},
+		},
+		{
+			format:   "stringsdict",
+			warnings: printfWarnings,
+			expected: map[string]string{
+				"res/en.lproj/Localizable.strings": `"Delims" = "%1$@ says hi";
+
+"Hello" = "Hello %1$@, it's 100%% %2$@!";
+
+"Spaces" = "  Indented";
+
+`,
+				"res/en.lproj/Localizable.stringsdict": `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<!-- The number of cats a person has -->
+	<key>PersonCats</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%1$#@count@</string>
+		<key>count</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>lld</string>
+			<key>one</key>
+			<string>%2$@ has %1$lld cat.</string>
+			<key>other</key>
+			<string>%2$@ has %1$lld cats.</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`,
+				"res/es.lproj/Localizable.strings": `"Hello" = "¡Hola %1$@, es 100%% %2$@!";
+
+`,
+				"res/es.lproj/Localizable.stringsdict": `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<!-- The number of cats a person has -->
+	<key>PersonCats</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%1$#@count@</string>
+		<key>count</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>lld</string>
+			<key>one</key>
+			<string>%2$@ tiene %1$lld gato.</string>
+			<key>many</key>
+			<string>%2$@ tiene %1$lld de gatos.</string>
+			<key>other</key>
+			<string>%2$@ tiene %1$lld gatos.</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`,
+				"res/ja.lproj/Localizable.stringsdict": `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<!-- The number of cats a person has -->
+	<key>PersonCats</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%1$#@count@</string>
+		<key>count</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>lld</string>
+			<key>other</key>
+			<string>%2$@は猫を%1$lld匹飼っています。</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`,
+			},
+		},
+		{
+			format:   "arb",
+			warnings: libraryWarnings,
+			expected: map[string]string{
+				"res/app_en.arb": `{
+  "@@locale": "en",
+  "Delims": "{Name} says hi",
+  "@Delims": {
+    "placeholders": {
+      "Name": {}
+    }
+  },
+  "Hello": "Hello {Name}, it's 100% {Adjective}!",
+  "@Hello": {
+    "placeholders": {
+      "Adjective": {},
+      "Name": {}
+    }
+  },
+  "PersonCats": "{Count, plural, one{{Name} has {Count} cat.} other{{Name} has {Count} cats.}}",
+  "@PersonCats": {
+    "description": "The number of cats a person has",
+    "placeholders": {
+      "Count": {
+        "type": "int"
+      },
+      "Name": {}
+    }
+  },
+  "Spaces": "  Indented"
+}
+`,
+				"res/app_es.arb": `{
+  "@@locale": "es",
+  "Hello": "¡Hola {Name}, es 100% {Adjective}!",
+  "@Hello": {
+    "placeholders": {
+      "Adjective": {},
+      "Name": {}
+    }
+  },
+  "PersonCats": "{Count, plural, one{{Name} tiene {Count} gato.} many{{Name} tiene {Count} de gatos.} other{{Name} tiene {Count} gatos.}}",
+  "@PersonCats": {
+    "description": "The number of cats a person has",
+    "placeholders": {
+      "Count": {
+        "type": "int"
+      },
+      "Name": {}
+    }
+  }
+}
+`,
+				"res/app_ja.arb": `{
+  "@@locale": "ja",
+  "PersonCats": "{Count, plural, other{{Name}は猫を{Count}匹飼っています。}}",
+  "@PersonCats": {
+    "description": "The number of cats a person has",
+    "placeholders": {
+      "Count": {
+        "type": "int"
+      },
+      "Name": {}
+    }
+  }
+}
+`,
+			},
+		},
+		{
+			format:   "i18next",
+			warnings: libraryWarnings,
+			expected: map[string]string{
+				"res/en/translation.json": `{
+  "Delims": "{{Name}} says hi",
+  "Hello": "Hello {{Name}}, it's 100% {{Adjective}}!",
+  "PersonCats_one": "{{Name}} has {{Count}} cat.",
+  "PersonCats_other": "{{Name}} has {{Count}} cats.",
+  "Spaces": "  Indented"
+}
+`,
+				"res/es/translation.json": `{
+  "Hello": "¡Hola {{Name}}, es 100% {{Adjective}}!",
+  "PersonCats_many": "{{Name}} tiene {{Count}} de gatos.",
+  "PersonCats_one": "{{Name}} tiene {{Count}} gato.",
+  "PersonCats_other": "{{Name}} tiene {{Count}} gatos."
+}
+`,
+				"res/ja/translation.json": `{
+  "PersonCats_other": "{{Name}}は猫を{{Count}}匹飼っています。"
+}
+`,
+			},
+		},
+		{
+			format: "csv",
+			expected: map[string]string{
+				"res/messages.csv": `id,form,description,hash,en,es,ja,ru
+Delims,other,,v2-sha256-f14b559a495bd6687d3abe86964e34f1fe5496908ff99cade56a0b119de57644,<<.Name>> says hi,,,
+Hello,other,,v2-sha256-6ba08d32d07ef97c4e8b56f2e4a2859b25abbbafdb47a751b1c6b2b00bab9fc6,"Hello {{.Name}}, it's 100% {{.Adjective}}!","¡Hola {{.Name}}, es 100% {{.Adjective}}!",,
+PersonCats,one,The number of cats a person has,v2-sha256-c9edc20cf187049d3a40f8ce81c8b613f941518421d2b01f9a6429d1e3c3f738,{{.Name}} has {{.Count}} cat.,{{.Name}} tiene {{.Count}} gato.,,У {{.Name}} {{.Count}} кошка.
+PersonCats,few,The number of cats a person has,v2-sha256-c9edc20cf187049d3a40f8ce81c8b613f941518421d2b01f9a6429d1e3c3f738,{{.Name}} has {{.Count}} cats.,,,
+PersonCats,many,The number of cats a person has,v2-sha256-c9edc20cf187049d3a40f8ce81c8b613f941518421d2b01f9a6429d1e3c3f738,{{.Name}} has {{.Count}} cats.,{{.Name}} tiene {{.Count}} de gatos.,,
+PersonCats,other,The number of cats a person has,v2-sha256-c9edc20cf187049d3a40f8ce81c8b613f941518421d2b01f9a6429d1e3c3f738,{{.Name}} has {{.Count}} cats.,{{.Name}} tiene {{.Count}} gatos.,{{.Name}}は猫を{{.Count}}匹飼っています。,У {{.Name}} {{.Count}} кошки.
+Spaces,other,,v2-sha256-71e4fe439769a936f43e51faeca1e642e9dcd0a5a33c7cee06e55d31bec020aa,"  Indented",,,
+Unsupported,other,,v2-sha256-7d0b46ae3ce981c91687488544c832e6d81156bbf180ada19ab3b311084aacbe,{{if .Name}}Hello{{end}},,,
+`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			ops, err := export(exportMessageFiles, language.English, "res", test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := make(map[string]string, len(ops.writeFiles))
+			for path, content := range ops.writeFiles {
+				actual[path] = string(content)
+			}
+			for path, content := range test.expected {
+				if actual[path] != content {
+					t.Errorf("%s: expected\n%s\ngot\n%s", path, content, actual[path])
+				}
+			}
+			if len(actual) != len(test.expected) {
+				t.Errorf("expected files %v; got %v", test.expected, actual)
+			}
+			// Messages with other template actions and incomplete translations (ru) are only exported to CSV files.
+			if !reflect.DeepEqual(ops.warnings, test.warnings) {
+				t.Errorf("expected warnings %q; got %q", test.warnings, ops.warnings)
+			}
+		})
+	}
+}
+
+func TestExportNameCollision(t *testing.T) {
+	_, err := export(map[string][]byte{
+		"active.en.toml": []byte("\"home.title\" = \"Home\"\nhome_title = \"Home\"\n"),
+	}, language.English, ".", "android")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestAndroidQualifier(t *testing.T) {
+	for _, langTag := range []language.Tag{language.Spanish, language.BrazilianPortuguese, language.TraditionalChinese, language.MustParse("zh-Hant-TW")} {
+		qualifier := androidQualifier(langTag)
+		actual, err := androidLanguage("values-"+qualifier, language.English)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != langTag {
+			t.Errorf("%s: expected %s; got %s (values-%s)", langTag, langTag, actual, qualifier)
+		}
+	}
+}
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -21,6 +25,14 @@ func usageExtract() {
 Extract walks the files and directories in paths and extracts all messages to a single file.
 If no files or paths are provided, it walks the current working directory.
 
+Messages are extracted from Go files and, if -templateExts is set, from calls of
+a localization function in Go template files (see -templateFunc). Each message records the files and lines
+where it is defined. A comment directly above an i18n.Message literal becomes the
+description of the message if it does not have one.
+
+Message ids that are passed to Localizer methods like LocalizeMessageID
+but are not defined by any message are reported (see -referenced).
+
 	xx-yy.active.format
 		This file contains messages that should be loaded at runtime.
 
@@ -38,6 +50,55 @@ Flags:
 		Output message files in this format.
 		Supported formats: json, toml, yaml
 		Default: toml
+
+	-templateExts extensions
+		A comma separated list of extensions of Go template files to extract messages from
+		(e.g. .tmpl,.html). Files with these extensions must be valid Go templates.
+		Default: none
+
+	-templateDelims delimiters
+		The left and right action delimiters of the template files separated by a space
+		(e.g. "[[ ]]").
+		Default: "{{ }}"
+
+	-templateFunc name
+		The name of the template function that localizes messages (e.g. {{T "Welcome"}}).
+		Default: T
+
+	-templateArgs roles
+		A comma separated list of the message fields that the string arguments of the
+		template function set, in order. Supported fields: id, description, zero, one,
+		two, few, many, other. Use _ to ignore an argument.
+		Default: id,other
+
+	-func name:roles
+		A Go function that wraps the i18n package and takes message fields as string
+		arguments (e.g. ui.T:id,other,_ for func T(id, defaultText string, data any)).
+		The roles are the same as for -templateArgs. The name is the function as it is
+		called in Go files (e.g. ui.T) or, with -packages, its import path and name
+		(e.g. example.com/app/ui.T). Calls that only set an id are treated as references.
+		Messages passed as i18n.Message literals (e.g. errs.New(&i18n.Message{...}))
+		are always extracted. This flag can be repeated.
+
+	-references
+		Record the file:line references of each message so that translators have context.
+		Use -references=false to omit them.
+		Default: true
+
+	-referenced file
+		Write the message ids that are passed to Localizer methods and funcs to this file,
+		one per line, so that goi18n prune keeps messages that are only used by id.
+
+	-packages
+		Treat paths as Go package patterns (e.g. ./...) and type-check the packages.
+		Message types and constant message fields are resolved across packages
+		regardless of how the i18n package is imported. Packages in vendor directories
+		and test files are skipped. Template files are extracted from the package directories.
+		Default: false (paths default to ./... when set)
+
+	-tags tags
+		A comma separated list of build tags to consider satisfied when loading packages.
+		Only used with -packages.
 `)
 }
 
@@ -46,6 +107,15 @@ type extractCommand struct {
 	sourceLanguage languageTag
 	outdir         string
 	format         string
+	templateExts   string
+	templateDelims string
+	templateFunc   string
+	templateArgs   string
+	funcs          funcSpecs
+	references     bool
+	referenced     string
+	packages       bool
+	tags           string
 }
 
 func (ec *extractCommand) name() string {
@@ -59,6 +129,16 @@ func (ec *extractCommand) parse(args []string) error {
 	flags.Var(&ec.sourceLanguage, "sourceLanguage", "en")
 	flags.StringVar(&ec.outdir, "outdir", ".", "")
 	flags.StringVar(&ec.format, "format", "toml", "")
+	flags.StringVar(&ec.templateExts, "templateExts", "", "")
+	flags.StringVar(&ec.templateDelims, "templateDelims", "", "")
+	flags.StringVar(&ec.templateFunc, "templateFunc", "T", "")
+	flags.StringVar(&ec.templateArgs, "templateArgs", "id,other", "")
+	ec.funcs = funcSpecs{}
+	flags.Var(ec.funcs, "func", "")
+	flags.BoolVar(&ec.references, "references", true, "")
+	flags.StringVar(&ec.referenced, "referenced", "", "")
+	flags.BoolVar(&ec.packages, "packages", false, "")
+	flags.StringVar(&ec.tags, "tags", "", "")
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
@@ -70,48 +150,133 @@ func (ec *extractCommand) parse(args []string) error {
 func (ec *extractCommand) execute() error {
 	if len(ec.paths) == 0 {
 		ec.paths = []string{"."}
+		if ec.packages {
+			ec.paths = []string{"./..."}
+		}
+	}
+	templateArgs, err := parseArgRoles(ec.templateArgs)
+	if err != nil {
+		return err
+	}
+	leftDelim, rightDelim, err := parseDelims(ec.templateDelims)
+	if err != nil {
+		return err
+	}
+	te := &templateExtractor{funcName: ec.templateFunc, args: templateArgs, leftDelim: leftDelim, rightDelim: rightDelim}
+	templateExts := map[string]struct{}{}
+	for _, ext := range strings.Split(ec.templateExts, ",") {
+		if ext = strings.TrimSpace(ext); ext != "" {
+			templateExts[ext] = struct{}{}
+		}
 	}
 	messages := []*i18n.Message{}
-	for _, path := range ec.paths {
-		if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+	references := []string{}
+	// sourcePath returns the path that extracted messages record in their references.
+	sourcePath := func(path string) string {
+		if !ec.references {
+			return ""
+		}
+		return referencePath(path)
+	}
+	extractTemplateFile := func(path string) error {
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		msgs, refs, err := te.extractMessages(buf, sourcePath(path))
+		if err != nil {
+			return fmt.Errorf("failed to extract messages from %s: %s", path, err)
+		}
+		messages = append(messages, msgs...)
+		references = append(references, refs...)
+		return nil
+	}
+	if ec.packages {
+		pkgs, err := loadPackages(ec.paths, ec.tags)
+		if err != nil {
+			return err
+		}
+		for _, pkg := range pkgs {
+			msgs, refs, err := extractPackageMessages(pkg, ec.funcs, ec.references)
 			if err != nil {
 				return err
 			}
-			if info.IsDir() {
-				return nil
+			messages = append(messages, msgs...)
+			references = append(references, refs...)
+		}
+		for _, dir := range packageDirs(pkgs) {
+			files, err := templateFiles(dir, templateExts)
+			if err != nil {
+				return err
 			}
-			if filepath.Ext(path) != ".go" {
-				return nil
+			for _, file := range files {
+				if err := extractTemplateFile(file); err != nil {
+					return err
+				}
 			}
+		}
+	} else {
+		for _, path := range ec.paths {
+			if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if info.IsDir() {
+					return nil
+				}
+				if _, ok := templateExts[filepath.Ext(path)]; ok {
+					return extractTemplateFile(path)
+				}
+				if filepath.Ext(path) != ".go" {
+					return nil
+				}
 
-			// Don't extract from test files.
-			if strings.HasSuffix(path, "_test.go") {
-				return nil
-			}
+				// Don't extract from test files.
+				if strings.HasSuffix(path, "_test.go") {
+					return nil
+				}
 
-			buf, err := os.ReadFile(path)
-			if err != nil {
-				return err
-			}
-			msgs, err := extractMessages(buf)
-			if err != nil {
+				buf, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				msgs, refs, err := extractMessages(buf, sourcePath(path), ec.funcs)
+				if err != nil {
+					return err
+				}
+				messages = append(messages, msgs...)
+				references = append(references, refs...)
+				return nil
+			}); err != nil {
 				return err
 			}
-			messages = append(messages, msgs...)
-			return nil
-		}); err != nil {
-			return err
 		}
 	}
 	messageTemplates := map[string]*i18n.MessageTemplate{}
 	for _, m := range messages {
 		if mt := i18n.NewMessageTemplate(m); mt != nil {
-			if duplicateMessage, ok := messageTemplates[m.ID]; ok && !reflect.DeepEqual(mt, duplicateMessage) {
-				return &duplicateMessageIDErr{messageID: m.ID}
+			if duplicateMessage, ok := messageTemplates[m.ID]; ok {
+				if !sameMessage(m, duplicateMessage.Message) {
+					return &duplicateMessageIDErr{messageID: m.ID}
+				}
+				duplicateMessage.References = append(duplicateMessage.References, m.References...)
+				continue
 			}
 			messageTemplates[m.ID] = mt
 		}
 	}
+	for _, id := range undefinedReferences(references, messageTemplates) {
+		fmt.Fprintf(os.Stderr, "message id %q is referenced but not defined\n", id)
+	}
+	if ec.referenced != "" {
+		var buf bytes.Buffer
+		for _, id := range sortedUnique(references) {
+			buf.WriteString(id + "\n")
+		}
+		if err := os.WriteFile(ec.referenced, buf.Bytes(), 0666); err != nil {
+			return err
+		}
+	}
 	path, content, err := writeFile(ec.outdir, "active", ec.sourceLanguage.Tag(), ec.format, messageTemplates, true)
 	if err != nil {
 		return err
@@ -127,37 +292,156 @@ func (e *duplicateMessageIDErr) Error() string {
 	return fmt.Sprintf("duplicate message ID: %s", e.messageID)
 }
 
-// extractMessages extracts messages from the bytes of a Go source file.
-func extractMessages(buf []byte) ([]*i18n.Message, error) {
+// sameMessage returns true if a and b only differ by their references.
+func sameMessage(a, b *i18n.Message) bool {
+	ac, bc := *a, *b
+	ac.References, bc.References = nil, nil
+	return reflect.DeepEqual(ac, bc)
+}
+
+// sortedUnique returns the sorted strings of ss without duplicates.
+func sortedUnique(ss []string) []string {
+	unique := slices.Clone(ss)
+	sort.Strings(unique)
+	return slices.Compact(unique)
+}
+
+// undefinedReferences returns the sorted and deduplicated message ids in references
+// that do not have a message template.
+func undefinedReferences(references []string, messageTemplates map[string]*i18n.MessageTemplate) []string {
+	undefined := []string{}
+	for _, id := range references {
+		if messageTemplates[id] == nil && !slices.Contains(undefined, id) {
+			undefined = append(undefined, id)
+		}
+	}
+	sort.Strings(undefined)
+	return undefined
+}
+
+// extractMessages extracts messages from the bytes of the Go source file at path.
+// It also returns the message ids that are passed to Localizer methods
+// (e.g. LocalizeMessageID) and must be defined by a message elsewhere.
+// If path is empty, the messages have no references.
+func extractMessages(buf []byte, path string, funcs funcSpecs) ([]*i18n.Message, []string, error) {
 	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "", buf, parser.AllErrors)
+	file, err := parser.ParseFile(fset, path, buf, parser.AllErrors|parser.ParseComments)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	extractor := newExtractor(file)
+	extractor := newExtractor(fset, file, buf, path, funcs)
 	ast.Walk(extractor, file)
-	return extractor.messages, nil
+	return extractor.messages, extractor.references, nil
 }
 
-func newExtractor(file *ast.File) *extractor {
-	return &extractor{i18nPackageName: i18nPackageName(file)}
+func newExtractor(fset *token.FileSet, file *ast.File, src []byte, path string, funcs funcSpecs) *extractor {
+	return &extractor{
+		i18nPackageName: i18nPackageName(file),
+		fset:            fset,
+		path:            path,
+		comments:        lineComments(fset, file, src),
+		funcs:           funcs,
+	}
+}
+
+// lineComments returns the text of the comments that are on lines of their own,
+// keyed by the line that directly follows each comment.
+func lineComments(fset *token.FileSet, file *ast.File, src []byte) map[int]string {
+	comments := map[int]string{}
+	tf := fset.File(file.Pos())
+	if tf == nil {
+		return comments
+	}
+	for _, cg := range file.Comments {
+		start := tf.Offset(cg.Pos())
+		lineStart := tf.Offset(tf.LineStart(tf.Line(cg.Pos())))
+		if start > len(src) || len(bytes.TrimSpace(src[lineStart:start])) > 0 {
+			// The comment follows code on the same line.
+			continue
+		}
+		if text := strings.TrimSpace(cg.Text()); text != "" {
+			comments[tf.Line(cg.End())+1] = text
+		}
+	}
+	return comments
 }
 
 type extractor struct {
 	i18nPackageName string
-	messages        []*i18n.Message
+
+	fset *token.FileSet
+
+	// path is the path of the file that is recorded in the references of messages.
+	// If it is empty, messages have no references.
+	path string
+
+	// comments are the comments of the file keyed by the line that follows them.
+	comments map[int]string
+
+	// info is the type information of the file, or nil if the file is not type-checked.
+	// With type information, message types and constant string values are resolved
+	// across packages instead of by name.
+	info *types.Info
+
+	// funcs are the functions whose string arguments are message fields.
+	funcs funcSpecs
+
+	messages []*i18n.Message
+
+	// references are the message ids that are passed to Localizer methods and funcs.
+	references []string
 }
 
 func (e *extractor) Visit(node ast.Node) ast.Visitor {
 	e.extractMessages(node)
+	e.extractReference(node)
+	e.extractFuncCall(node)
 	return e
 }
 
+// messageIDMethods are the names of the Localizer methods whose first argument is a message id.
+var messageIDMethods = map[string]struct{}{
+	"LocalizeMessageID":     {},
+	"MustLocalizeMessageID": {},
+}
+
+// extractReference extracts the message id from calls of Localizer methods that take a message id.
+// Without type information any method with a matching name is assumed to be a Localizer method.
+func (e *extractor) extractReference(node ast.Node) {
+	call, ok := node.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return
+	}
+	se, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	if _, ok := messageIDMethods[se.Sel.Name]; !ok {
+		return
+	}
+	if e.info != nil {
+		sel := e.info.Selections[se]
+		if sel == nil || sel.Kind() != types.MethodVal || !isI18nType(sel.Recv(), "Localizer") {
+			return
+		}
+	}
+	if id, ok := e.stringValue(call.Args[0]); ok && id != "" {
+		e.references = append(e.references, id)
+	}
+}
+
 func (e *extractor) extractMessages(node ast.Node) {
 	cl, ok := node.(*ast.CompositeLit)
 	if !ok {
 		return
 	}
+	if e.info != nil {
+		// Elements of slices and maps are composite literals with their own type.
+		if isI18nType(e.info.TypeOf(cl), "Message", "LocalizeConfig") {
+			e.extractMessage(cl)
+		}
+		return
+	}
 	switch t := cl.Type.(type) {
 	case *ast.SelectorExpr:
 		if !e.isMessageType(t) {
@@ -231,7 +515,7 @@ func (e *extractor) extractMessage(cl *ast.CompositeLit) {
 		if !ok {
 			continue
 		}
-		v, ok := extractStringLiteral(kve.Value)
+		v, ok := e.stringValue(kve.Value)
 		if !ok {
 			continue
 		}
@@ -243,7 +527,42 @@ func (e *extractor) extractMessage(cl *ast.CompositeLit) {
 	if messageID := data["MessageID"]; messageID != "" {
 		data["ID"] = messageID
 	}
-	e.messages = append(e.messages, i18n.MustNewMessage(data))
+	m := i18n.MustNewMessage(data)
+	if m.Description == "" {
+		// The comment directly above the literal describes the message.
+		m.Description = e.comments[e.fset.Position(cl.Pos()).Line]
+	}
+	m.References = e.sourceReferences(cl.Pos())
+	e.messages = append(e.messages, m)
+}
+
+// sourceReferences returns the source reference of pos (e.g. "main.go:12"), if any.
+func (e *extractor) sourceReferences(pos token.Pos) []string {
+	if e.path == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s:%d", e.path, e.fset.Position(pos).Line)}
+}
+
+// referencePath returns the path of a file that is recorded in references.
+// It is relative to the current working directory if possible.
+func referencePath(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// stringValue returns the string value of expr.
+func (e *extractor) stringValue(expr ast.Expr) (string, bool) {
+	if e.info != nil {
+		return constantString(e.info, expr)
+	}
+	return extractStringLiteral(expr)
 }
 
 func extractStringLiteral(expr ast.Expr) (string, bool) {
@@ -295,7 +614,7 @@ func extractStringLiteral(expr ast.Expr) (string, bool) {
 
 func i18nPackageName(file *ast.File) string {
 	for _, i := range file.Imports {
-		if i.Path.Kind == token.STRING && i.Path.Value == `"github.com/nicksnyder/go-i18n/v2/i18n"` {
+		if i.Path.Kind == token.STRING && i.Path.Value == strconv.Quote(i18nImportPath) {
 			if i.Name == nil {
 				return "i18n"
 			}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 )
 
 func TestExtract(t *testing.T) {
@@ -12,6 +15,7 @@ func TestExtract(t *testing.T) {
 		name             string
 		fileName         string
 		file             string
+		args             []string
 		activeFile       []byte
 		expectedExitCode int
 		expectedErr      error
@@ -285,6 +289,55 @@ zero = "Zero translation"
 			activeFile: []byte(`id = "my const"
 `),
 		},
+		{
+			name:     "template file",
+			fileName: "file.tmpl",
+			args:     []string{"-templateExts", ".tmpl,.html"},
+			file: `<h1>{{T "Welcome" "Welcome to our website"}}</h1>
+			{{range .Items}}<p>{{printf "%s: %s" (T "ItemLabel" "Item") .Name}}</p>{{end}}
+			{{define "footer"}}{{T "Footer" "Goodbye {{.Name}}"}}{{end}}
+			{{T "Reference"}} {{T .Dynamic "Ignored"}} {{Other "NotAMessage" "Ignored"}}`,
+			activeFile: []byte(`Footer = "Goodbye {{.Name}}"
+ItemLabel = "Item"
+Welcome = "Welcome to our website"
+`),
+		},
+		{
+			name:     "html template file",
+			fileName: "file.html",
+			file:     `<p>{{T "Hello" "Hello"}}</p>`,
+			args:     []string{"-templateExts", ".tmpl,.html"},
+			activeFile: []byte(`Hello = "Hello"
+`),
+		},
+		{
+			name:       "html file without template extensions",
+			fileName:   "file.html",
+			file:       `{{#if user}}<p>{{user.name}}</p>{{/if}}`,
+			activeFile: []byte(``),
+		},
+		{
+			name:     "template delimiters",
+			fileName: "file.tmpl",
+			file:     `<p>[[T "Hello" "Hello {{.Name}}"]]</p>`,
+			args:     []string{"-templateExts", ".tmpl", "-templateDelims", "[[ ]]"},
+			activeFile: []byte(`Hello = "Hello {{.Name}}"
+`),
+		},
+		{
+			name:             "invalid template file",
+			fileName:         "file.tmpl",
+			file:             `<p>{{T "Hello" "Hello"</p>`,
+			args:             []string{"-templateExts", ".tmpl"},
+			expectedExitCode: 1,
+		},
+		{
+			name:       "non template file",
+			fileName:   "file.txt",
+			file:       `{{T "Hello" "Hello"}}`,
+			args:       []string{"-templateExts", ".tmpl"},
+			activeFile: []byte(``),
+		},
 	}
 
 	for _, test := range tests {
@@ -299,7 +352,8 @@ zero = "Zero translation"
 				t.Fatal(err)
 			}
 
-			code := testableMain([]string{"extract", "-outdir", outdir, indir})
+			args := append([]string{"extract", "-references=false", "-outdir", outdir}, test.args...)
+			code := testableMain(append(args, indir))
 			if code != test.expectedExitCode {
 				t.Fatalf("expected exit code %d; got %d\n", test.expectedExitCode, code)
 			}
@@ -350,19 +404,318 @@ func TestExtractCommand(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	expected := []byte(`HelloPerson = "Hello {{.Name}}"
+	expected := []byte(`[HelloPerson]
+other = "Hello {{.Name}}"
+references = ["../example/main.go:49"]
 
 [MyUnreadEmails]
 description = "The number of unread emails I have"
 one = "I have {{.PluralCount}} unread email."
 other = "I have {{.PluralCount}} unread emails."
+references = ["../example/main.go:59"]
 
 [PersonUnreadEmails]
 description = "The number of unread emails a person has"
 one = "{{.Name}} has {{.UnreadEmailCount}} unread email."
 other = "{{.Name}} has {{.UnreadEmailCount}} unread emails."
+references = ["../example/main.go:69"]
 `)
 	if !bytes.Equal(actual, expected) {
 		t.Fatalf("files not equal\nactual:\n%s\nexpected:\n%s", actual, expected)
 	}
 }
+
+func TestExtractPackages(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []byte
+	}{
+		{
+			name: "without build tags",
+			args: []string{"./testdata/packages/..."},
+			expected: []byte(`[Emails]
+one = "{{.PluralCount}} email"
+other = "{{.PluralCount}} emails"
+references = ["testdata/packages/app/dot.go:6"]
+
+[Greeting]
+description = "Greets the user on the home page."
+other = "Hello {{.Name}}!"
+references = ["testdata/packages/app/app.go:13"]
+
+[Title]
+other = "Welcome"
+references = ["testdata/packages/app/app.tmpl:1"]
+`),
+		},
+		{
+			name: "with wrapper function",
+			args: []string{"-references=false", "-func", "github.com/nicksnyder/go-i18n/v2/goi18n/testdata/packages/ui.T:id,other,_", "./testdata/packages/..."},
+			expected: []byte(`Save = "Save changes"
+Title = "Welcome"
+
+[Emails]
+one = "{{.PluralCount}} email"
+other = "{{.PluralCount}} emails"
+
+[Greeting]
+description = "Greets the user on the home page."
+other = "Hello {{.Name}}!"
+`),
+		},
+		{
+			name: "with build tags",
+			args: []string{"-references=false", "-tags", "tagged", "./testdata/packages/..."},
+			expected: []byte(`Tagged = "Only built with the tagged build tag"
+Title = "Welcome"
+
+[Emails]
+one = "{{.PluralCount}} email"
+other = "{{.PluralCount}} emails"
+
+[Greeting]
+description = "Greets the user on the home page."
+other = "Hello {{.Name}}!"
+`),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outdir := mustTempDir("TestExtractPackages")
+			defer mustRemoveAll(t, outdir)
+			args := append([]string{"extract", "-packages", "-templateExts", ".tmpl", "-outdir", outdir}, test.args...)
+			if code := testableMain(args); code != 0 {
+				t.Fatalf("expected exit code 0; got %d", code)
+			}
+			actual, err := os.ReadFile(filepath.Join(outdir, "active.en.toml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(actual, test.expected) {
+				t.Fatalf("files not equal\nactual:\n%s\nexpected:\n%s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestExtractPackageReferences(t *testing.T) {
+	pkgs, err := loadPackages([]string{"./testdata/packages/app"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("expected 1 package; got %d", len(pkgs))
+	}
+	_, references, err := extractPackageMessages(pkgs[0], nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Farewell"}; !reflect.DeepEqual(references, expected) {
+		t.Fatalf("expected references %#v; got %#v", expected, references)
+	}
+}
+
+func TestLoadPackagesError(t *testing.T) {
+	for _, pattern := range []string{"./testdata/broken", "./testdata/missing"} {
+		if _, err := loadPackages([]string{pattern}, ""); err == nil {
+			t.Errorf("%s: expected error", pattern)
+		}
+	}
+}
+
+func TestExtractReferences(t *testing.T) {
+	file := `package main
+
+	import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	const goodbye = "Goodbye"
+
+	var m = &i18n.Message{
+		ID:    "Hello",
+		Other: "Hello",
+	}
+
+	func f(localizer *i18n.Localizer, id string) {
+		localizer.LocalizeMessageID("Hello")
+		localizer.MustLocalizeMessageID(goodbye)
+		localizer.MustLocalizeMessageID("Goodbye")
+		localizer.LocalizeMessageID(id)
+		localizer.Localize("NotAReference")
+	}
+	`
+	messages, references, err := extractMessages([]byte(file), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Hello", "Goodbye", "Goodbye"}; !reflect.DeepEqual(references, expected) {
+		t.Fatalf("expected references %#v; got %#v", expected, references)
+	}
+	messageTemplates := map[string]*i18n.MessageTemplate{}
+	for _, m := range messages {
+		messageTemplates[m.ID] = i18n.NewMessageTemplate(m)
+	}
+	if expected, actual := []string{"Goodbye"}, undefinedReferences(references, messageTemplates); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected undefined references %#v; got %#v", expected, actual)
+	}
+}
+
+func TestExtractSourceReferences(t *testing.T) {
+	file := `package main
+
+	import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	// Greets a person by name.
+	var hello = &i18n.Message{
+		ID:    "Hello",
+		Other: "Hello {{.Name}}",
+	}
+
+	var messages = []*i18n.Message{
+		{
+			ID:    "Described",
+			Description: "An explicit description",
+			Other: "Described",
+		}, // Not a comment of the next message.
+		{
+			ID:    "Goodbye",
+			Other: "Goodbye",
+		},
+	}
+	`
+	messages, _, err := extractMessages([]byte(file), "cmd/main.go", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*i18n.Message{
+		{ID: "Hello", Description: "Greets a person by name.", Other: "Hello {{.Name}}", References: []string{"cmd/main.go:6"}},
+		{ID: "Described", Description: "An explicit description", Other: "Described", References: []string{"cmd/main.go:12"}},
+		{ID: "Goodbye", Other: "Goodbye", References: []string{"cmd/main.go:17"}},
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Fatalf("expected messages %#v; got %#v", expected, messages)
+	}
+}
+
+func TestExtractFuncs(t *testing.T) {
+	file := `package main
+
+	import "example.com/ui"
+
+	const saveID = "Save"
+
+	func f(name string) {
+		ui.T(saveID, "Save changes", nil)
+		ui.Plural("Cats", "The number of cats", "{{.PluralCount}} cat", "{{.PluralCount}} cats")
+		ui.T("Cancel", name, nil)
+		T("Delete", "Delete", nil)
+		other.T("NotExtracted", "Not extracted", nil)
+	}
+	`
+	funcs := funcSpecs{}
+	for _, spec := range []string{"ui.T:id,other,_", "ui.Plural:id,description,one,other"} {
+		if err := funcs.Set(spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	messages, references, err := extractMessages([]byte(file), "", funcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*i18n.Message{
+		{ID: "Save", Other: "Save changes"},
+		{ID: "Cats", Description: "The number of cats", One: "{{.PluralCount}} cat", Other: "{{.PluralCount}} cats"},
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Fatalf("expected messages %#v; got %#v", expected, messages)
+	}
+	if expected := []string{"Cancel"}; !reflect.DeepEqual(references, expected) {
+		t.Fatalf("expected references %#v; got %#v", expected, references)
+	}
+}
+
+func TestFuncSpecsSet(t *testing.T) {
+	for _, spec := range []string{"ui.T", ":id", "ui.T:other"} {
+		if err := (funcSpecs{}).Set(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestTemplateExtractor(t *testing.T) {
+	tests := []struct {
+		name       string
+		funcName   string
+		args       string
+		file       string
+		messages   []*i18n.Message
+		references []string
+	}{
+		{
+			name:     "custom function and arguments",
+			funcName: "translate",
+			args:     "description,id,_,one,other",
+			file:     `{{translate "Number of cats" "Cats" .Count "{{.PluralCount}} cat" "{{.PluralCount}} cats"}}`,
+			messages: []*i18n.Message{
+				{ID: "Cats", Description: "Number of cats", One: "{{.PluralCount}} cat", Other: "{{.PluralCount}} cats"},
+			},
+		},
+		{
+			name:       "id only",
+			funcName:   "T",
+			args:       "id",
+			file:       `{{T "Welcome" "Ignored"}}`,
+			references: []string{"Welcome"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args, err := parseArgRoles(test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			te := &templateExtractor{funcName: test.funcName, args: args}
+			messages, references, err := te.extractMessages([]byte(test.file), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(messages, test.messages) {
+				t.Errorf("expected messages %#v; got %#v", test.messages, messages)
+			}
+			if !reflect.DeepEqual(references, test.references) {
+				t.Errorf("expected references %#v; got %#v", test.references, references)
+			}
+		})
+	}
+}
+
+func TestParseArgRoles(t *testing.T) {
+	if _, err := parseArgRoles("other"); err == nil {
+		t.Error("expected error for arguments without id")
+	}
+	if _, err := parseArgRoles("id,name"); err == nil {
+		t.Error("expected error for unsupported argument")
+	}
+	args, err := parseArgRoles("ID, Other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"id", "other"}; !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %#v; got %#v", expected, args)
+	}
+}
+
+func TestParseDelims(t *testing.T) {
+	for _, s := range []string{"[[", "[[ ]] ]]"} {
+		if _, _, err := parseDelims(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+	left, right, err := parseDelims(" [[  ]] ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left != "[[" || right != "]]" {
+		t.Errorf("expected [[ and ]]; got %q and %q", left, right)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// funcSpecs are the Go functions that wrap the i18n package and take message fields as arguments.
+// It is a flag.Value that can be set multiple times.
+type funcSpecs map[string][]string
+
+func (fs funcSpecs) String() string {
+	specs := make([]string, 0, len(fs))
+	for name, args := range fs {
+		specs = append(specs, name+":"+strings.Join(args, ","))
+	}
+	return strings.Join(specs, " ")
+}
+
+// Set adds a function from a spec of the form name:roles (e.g. "ui.T:id,other,_").
+// The name is the function as it is called (e.g. "ui.T" or "T") or, when packages
+// are type-checked, the import path of its package and its name (e.g. "example.com/app/ui.T").
+// The roles are parsed by parseArgRoles.
+func (fs funcSpecs) Set(s string) error {
+	name, roles, ok := strings.Cut(s, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return fmt.Errorf("function %q is not of the form name:roles", s)
+	}
+	args, err := parseArgRoles(roles)
+	if err != nil {
+		return fmt.Errorf("function %s: %w", name, err)
+	}
+	fs[name] = args
+	return nil
+}
+
+// extractFuncCall extracts a message from a call of one of the functions in e.funcs.
+// Calls that only set a message id are extracted as references.
+func (e *extractor) extractFuncCall(node ast.Node) {
+	call, ok := node.(*ast.CallExpr)
+	if !ok || len(e.funcs) == 0 {
+		return
+	}
+	args := e.funcArgs(call.Fun)
+	if args == nil {
+		return
+	}
+	data := make(map[string]string)
+	for i, arg := range call.Args {
+		if i >= len(args) {
+			break
+		}
+		if args[i] == "_" {
+			continue
+		}
+		if v, ok := e.stringValue(arg); ok {
+			data[args[i]] = v
+		}
+	}
+	if data["id"] == "" {
+		return
+	}
+	m := i18n.MustNewMessage(data)
+	if i18n.NewMessageTemplate(m) == nil {
+		e.references = append(e.references, m.ID)
+		return
+	}
+	m.References = e.sourceReferences(call.Pos())
+	e.messages = append(e.messages, m)
+}
+
+// funcArgs returns the argument roles of the function called by fun, or nil if it is not in e.funcs.
+func (e *extractor) funcArgs(fun ast.Expr) []string {
+	var ident *ast.Ident
+	var name string
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident, name = f, f.Name
+	case *ast.SelectorExpr:
+		x, ok := f.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		ident, name = f.Sel, x.Name+"."+f.Sel.Name
+	default:
+		return nil
+	}
+	if args, ok := e.funcs[name]; ok {
+		return args
+	}
+	if e.info == nil {
+		return nil
+	}
+	fn, ok := e.info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Signature().Recv() != nil {
+		return nil
+	}
+	return e.funcs[fn.Pkg().Path()+"."+fn.Name()]
+}
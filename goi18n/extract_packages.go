@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// i18nImportPath is the import path of the i18n package.
+const i18nImportPath = "github.com/nicksnyder/go-i18n/v2/i18n"
+
+// goPackage is a type-checked Go package.
+type goPackage struct {
+	PkgPath   string
+	GoFiles   []string
+	Fset      *token.FileSet
+	Syntax    []*ast.File
+	TypesInfo *types.Info
+}
+
+// listedPackage is a package as it is printed by go list -json.
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	CgoFiles   []string
+	Export     string
+	DepOnly    bool
+	ImportMap  map[string]string
+	Error      *struct {
+		Err string
+	}
+}
+
+// loadPackages loads the Go packages that match patterns with type information.
+// Files are selected with the build tags in tags (e.g. "linux,prod") and
+// packages in vendor directories are skipped.
+//
+// Packages are listed with the go command, which also compiles their dependencies,
+// so that only the matched packages are type-checked from source.
+func loadPackages(patterns []string, tags string) ([]*goPackage, error) {
+	args := []string{"list", "-e", "-json", "-export", "-deps"}
+	if tags != "" {
+		args = append(args, "-tags="+tags)
+	}
+	args = append(args, "--")
+	args = append(args, patterns...)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go list: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var listed []*listedPackage
+	exports := map[string]string{}
+	dec := json.NewDecoder(&stdout)
+	for {
+		lp := &listedPackage{}
+		if err := dec.Decode(lp); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		exports[lp.ImportPath] = lp.Export
+		if !lp.DepOnly {
+			listed = append(listed, lp)
+		}
+	}
+
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		export := exports[path]
+		if export == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(export)
+	})
+	var loaded []*goPackage
+	for _, lp := range listed {
+		pkg := &goPackage{PkgPath: lp.ImportPath, Fset: fset}
+		for _, name := range append(lp.GoFiles, lp.CgoFiles...) {
+			pkg.GoFiles = append(pkg.GoFiles, filepath.Join(lp.Dir, name))
+		}
+		if isVendored(pkg) {
+			continue
+		}
+		if lp.Error != nil {
+			return nil, fmt.Errorf("failed to load package %s: %s", lp.ImportPath, lp.Error.Err)
+		}
+		if err := pkg.check(imp, lp.ImportMap); err != nil {
+			return nil, fmt.Errorf("failed to load package %s: %s", lp.ImportPath, err)
+		}
+		loaded = append(loaded, pkg)
+	}
+	return loaded, nil
+}
+
+// check parses and type-checks the Go files of pkg.
+// Imports are resolved with imp after they are mapped by importMap (e.g. to vendored packages).
+func (pkg *goPackage) check(imp types.Importer, importMap map[string]string) error {
+	for _, filename := range pkg.GoFiles {
+		file, err := parser.ParseFile(pkg.Fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		pkg.Syntax = append(pkg.Syntax, file)
+	}
+	pkg.TypesInfo = &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+	var errs []error
+	cfg := &types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if mapped, ok := importMap[path]; ok {
+				path = mapped
+			}
+			return imp.Import(path)
+		}),
+		FakeImportC: true,
+		Error: func(err error) {
+			errs = append(errs, err)
+		},
+	}
+	cfg.Check(pkg.PkgPath, pkg.Fset, pkg.Syntax, pkg.TypesInfo)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+func isVendored(pkg *goPackage) bool {
+	if strings.HasPrefix(pkg.PkgPath, "vendor/") || strings.Contains(pkg.PkgPath, "/vendor/") {
+		return true
+	}
+	for _, file := range pkg.GoFiles {
+		if strings.Contains(filepath.ToSlash(file), "/vendor/") {
+			return true
+		}
+	}
+	return false
+}
+
+// extractPackageMessages extracts messages from the type-checked syntax of pkg.
+// It also returns the message ids that are passed to Localizer methods and funcs.
+// If references is true, messages record the files and lines where they are defined.
+func extractPackageMessages(pkg *goPackage, funcs funcSpecs, references bool) ([]*i18n.Message, []string, error) {
+	var messages []*i18n.Message
+	var refs []string
+	for _, file := range pkg.Syntax {
+		filename := pkg.Fset.File(file.Pos()).Name()
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+		path := ""
+		if references {
+			path = referencePath(filename)
+		}
+		e := newExtractor(pkg.Fset, file, src, path, funcs)
+		e.info = pkg.TypesInfo
+		ast.Walk(e, file)
+		messages = append(messages, e.messages...)
+		refs = append(refs, e.references...)
+	}
+	return messages, refs, nil
+}
+
+// packageDirs returns the directories that contain the Go files of pkgs.
+func packageDirs(pkgs []*goPackage) []string {
+	var dirs []string
+	seen := map[string]struct{}{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.GoFiles {
+			dir := filepath.Dir(file)
+			if _, ok := seen[dir]; ok {
+				continue
+			}
+			seen[dir] = struct{}{}
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// templateFiles returns the files in dir (but not its subdirectories) that have one of the extensions in exts.
+func templateFiles(dir string, exts map[string]struct{}) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if _, ok := exts[filepath.Ext(entry.Name())]; ok && !entry.IsDir() {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// isI18nType reports whether t is, or points to, one of the named types of the i18n package.
+func isI18nType(t types.Type, names ...string) bool {
+	if t == nil {
+		return false
+	}
+	t = types.Unalias(t)
+	if p, ok := t.(*types.Pointer); ok {
+		t = types.Unalias(p.Elem())
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != i18nImportPath {
+		return false
+	}
+	for _, name := range names {
+		if obj.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// constantString returns the value of expr if it is a constant string expression.
+func constantString(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
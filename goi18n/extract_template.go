@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template/parse"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// argRoles are the message fields that the arguments of a localization function can set.
+var argRoles = map[string]struct{}{
+	"id":          {},
+	"description": {},
+	"zero":        {},
+	"one":         {},
+	"two":         {},
+	"few":         {},
+	"many":        {},
+	"other":       {},
+}
+
+// parseDelims parses a pair of template delimiters that are separated by a space (e.g. "[[ ]]").
+func parseDelims(s string) (leftDelim, rightDelim string, err error) {
+	if s == "" {
+		return "", "", nil
+	}
+	delims := strings.Fields(s)
+	if len(delims) != 2 {
+		return "", "", fmt.Errorf("expected a left and a right delimiter separated by a space but got %q", s)
+	}
+	return delims[0], delims[1], nil
+}
+
+// parseArgRoles parses a comma separated list of argument roles (e.g. "id,other").
+func parseArgRoles(s string) ([]string, error) {
+	args := strings.Split(s, ",")
+	hasID := false
+	for i, arg := range args {
+		arg = strings.ToLower(strings.TrimSpace(arg))
+		if _, ok := argRoles[arg]; !ok && arg != "_" {
+			return nil, fmt.Errorf("unsupported argument %q", arg)
+		}
+		if arg == "id" {
+			hasID = true
+		}
+		args[i] = arg
+	}
+	if !hasID {
+		return nil, fmt.Errorf("arguments %q do not contain id", s)
+	}
+	return args, nil
+}
+
+// templateExtractor extracts messages from calls of a localization function in Go template files
+// (e.g. {{T "Welcome" "Welcome to our website"}}).
+type templateExtractor struct {
+	// funcName is the name of the localization function.
+	funcName string
+
+	// args are the roles of the function arguments (e.g. "id", "other").
+	// Arguments with the role "_" are ignored.
+	args []string
+
+	// leftDelim and rightDelim are the action delimiters of the templates.
+	// Empty delimiters are "{{" and "}}".
+	leftDelim, rightDelim string
+}
+
+// extractMessages extracts messages from the bytes of the template file at path.
+// Calls that only set a message id are returned as references.
+// If path is empty, the messages have no source references.
+func (te *templateExtractor) extractMessages(buf []byte, path string) ([]*i18n.Message, []string, error) {
+	tree := parse.New("")
+	// Functions are provided at execution time so they are unknown here.
+	tree.Mode = parse.SkipFuncCheck
+	trees := map[string]*parse.Tree{}
+	if _, err := tree.Parse(string(buf), te.leftDelim, te.rightDelim, trees); err != nil {
+		return nil, nil, err
+	}
+	var messages []*i18n.Message
+	var references []string
+	for _, t := range trees {
+		if t.Root == nil {
+			continue
+		}
+		walkTemplate(t.Root, func(cmd *parse.CommandNode) {
+			m := te.extractMessage(cmd)
+			if m == nil {
+				return
+			}
+			if i18n.NewMessageTemplate(m) == nil {
+				references = append(references, m.ID)
+				return
+			}
+			if path != "" {
+				line := 1 + bytes.Count(buf[:cmd.Position()], []byte("\n"))
+				m.References = []string{fmt.Sprintf("%s:%d", path, line)}
+			}
+			messages = append(messages, m)
+		})
+	}
+	return messages, references, nil
+}
+
+func (te *templateExtractor) extractMessage(cmd *parse.CommandNode) *i18n.Message {
+	if len(cmd.Args) == 0 {
+		return nil
+	}
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok || ident.Ident != te.funcName {
+		return nil
+	}
+	data := make(map[string]string)
+	for i, arg := range cmd.Args[1:] {
+		if i >= len(te.args) {
+			break
+		}
+		s, ok := arg.(*parse.StringNode)
+		if !ok || te.args[i] == "_" {
+			continue
+		}
+		data[te.args[i]] = s.Text
+	}
+	if data["id"] == "" {
+		return nil
+	}
+	return i18n.MustNewMessage(data)
+}
+
+// walkTemplate calls f for every command in the tree rooted at node.
+func walkTemplate(node parse.Node, f func(*parse.CommandNode)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplate(child, f)
+		}
+	case *parse.ActionNode:
+		walkTemplate(n.Pipe, f)
+	case *parse.IfNode:
+		walkTemplate(n.Pipe, f)
+		walkTemplate(n.List, f)
+		walkTemplate(n.ElseList, f)
+	case *parse.RangeNode:
+		walkTemplate(n.Pipe, f)
+		walkTemplate(n.List, f)
+		walkTemplate(n.ElseList, f)
+	case *parse.WithNode:
+		walkTemplate(n.Pipe, f)
+		walkTemplate(n.List, f)
+		walkTemplate(n.ElseList, f)
+	case *parse.TemplateNode:
+		walkTemplate(n.Pipe, f)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkTemplate(cmd, f)
+		}
+	case *parse.CommandNode:
+		f(n)
+		for _, arg := range n.Args {
+			walkTemplate(arg, f)
+		}
+	case *parse.ChainNode:
+		walkTemplate(n.Node, f)
+	}
+}
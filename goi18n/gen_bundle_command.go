@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+func usageGenBundle() {
+	fmt.Fprintf(os.Stderr, `usage: goi18n gen-bundle [options] [message files]
+
+Gen-bundle reads the messages in the message files and writes a Go file with a function
+that returns a bundle with the messages of every language, so that programs do not need
+to read or unmarshal message files.
+
+	bundle.go
+		This file contains the NewBundle function.
+
+Message files are checked when the Go file is generated: gen-bundle fails if a message
+file can not be parsed, a template is invalid or a language has no plural rule.
+Descriptions and hashes are not generated.
+
+Flags:
+
+	-sourceLanguage tag
+		The default language of the bundle (e.g. en, en-US, zh-Hant-CN).
+		Default: en
+
+	-package name
+		The package name of the generated file.
+		Default: messages
+
+	-outdir directory
+		Write the Go file to this directory.
+		Default: .
+`)
+}
+
+type genBundleCommand struct {
+	messageFiles   []string
+	sourceLanguage languageTag
+	packageName    string
+	outdir         string
+}
+
+func (gc *genBundleCommand) name() string {
+	return "gen-bundle"
+}
+
+func (gc *genBundleCommand) parse(args []string) error {
+	flags := flag.NewFlagSet("gen-bundle", flag.ExitOnError)
+	flags.Usage = usageGenBundle
+
+	flags.Var(&gc.sourceLanguage, "sourceLanguage", "en")
+	flags.StringVar(&gc.packageName, "package", "messages", "")
+	flags.StringVar(&gc.outdir, "outdir", ".", "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	gc.messageFiles = flags.Args()
+	return nil
+}
+
+func (gc *genBundleCommand) execute() error {
+	if len(gc.messageFiles) < 1 {
+		return fmt.Errorf("need at least one message file to generate a bundle from")
+	}
+	inFiles := make(map[string][]byte)
+	for _, path := range gc.messageFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		inFiles[path] = content
+	}
+	src, err := genBundle(inFiles, gc.sourceLanguage.Tag(), gc.packageName)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(gc.outdir, "bundle.go"), src, 0666)
+}
+
+type generatedLanguage struct {
+	Tag      string
+	Messages []*i18n.Message
+}
+
+// genBundle returns the Go source of a package with a function that returns a bundle with the messages in the message files.
+// Messages of a language in later message files (sorted by path) replace messages with the same id in earlier ones.
+func genBundle(messageFiles map[string][]byte, sourceLanguageTag language.Tag, packageName string) ([]byte, error) {
+	if !token.IsIdentifier(packageName) {
+		return nil, fmt.Errorf("invalid package name %q", packageName)
+	}
+	paths := make([]string, 0, len(messageFiles))
+	for path := range messageFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	pluralRules := plural.DefaultRules()
+	messages := make(map[language.Tag]map[string]*i18n.Message)
+	for _, path := range paths {
+		mf, err := i18n.ParseMessageFileBytes(messageFiles[path], path, unmarshalFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+		}
+		if len(mf.Messages) == 0 {
+			continue
+		}
+		if pluralRules.Rule(mf.Tag) == nil {
+			return nil, fmt.Errorf("failed to load message file %s: no plural rule registered for %s", path, mf.Tag)
+		}
+		if messages[mf.Tag] == nil {
+			messages[mf.Tag] = make(map[string]*i18n.Message)
+		}
+		for _, m := range mf.Messages {
+			mt := i18n.NewMessageTemplate(m)
+			if mt == nil {
+				continue
+			}
+			for pluralForm, t := range mt.PluralTemplates {
+				if _, err := internal.TemplateFields(t.Src, t.LeftDelim, t.RightDelim); err != nil {
+					return nil, fmt.Errorf("message %q in %s has invalid %s template: %s", m.ID, path, pluralForm, err)
+				}
+			}
+			messages[mf.Tag][m.ID] = &i18n.Message{
+				ID:         m.ID,
+				LeftDelim:  m.LeftDelim,
+				RightDelim: m.RightDelim,
+				Zero:       m.Zero,
+				One:        m.One,
+				Two:        m.Two,
+				Few:        m.Few,
+				Many:       m.Many,
+				Other:      m.Other,
+			}
+		}
+	}
+
+	langTags := make([]language.Tag, 0, len(messages))
+	for langTag := range messages {
+		langTags = append(langTags, langTag)
+	}
+	// The source language is added first like bundles add their default language.
+	sort.Slice(langTags, func(i, j int) bool {
+		if (langTags[i] == sourceLanguageTag) != (langTags[j] == sourceLanguageTag) {
+			return langTags[i] == sourceLanguageTag
+		}
+		return langTags[i].String() < langTags[j].String()
+	})
+	languages := make([]*generatedLanguage, 0, len(langTags))
+	for _, langTag := range langTags {
+		l := &generatedLanguage{Tag: langTag.String()}
+		ids := make([]string, 0, len(messages[langTag]))
+		for id := range messages[langTag] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			l.Messages = append(l.Messages, messages[langTag][id])
+		}
+		languages = append(languages, l)
+	}
+
+	var buf bytes.Buffer
+	if err := genBundleTemplate.Execute(&buf, map[string]interface{}{
+		"Package":        packageName,
+		"SourceLanguage": sourceLanguageTag.String(),
+		"Languages":      languages,
+	}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var genBundleTemplate = newCodeTemplate("gen-bundle", `// Code generated by goi18n gen-bundle; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// NewBundle returns a bundle whose default language is {{.SourceLanguage}} with the messages of every language.
+func NewBundle() *i18n.Bundle {
+	bundle := i18n.NewBundle(language.MustParse({{printf "%q" .SourceLanguage}}))
+	{{- range .Languages}}
+	bundle.MustAddMessages(language.MustParse({{printf "%q" .Tag}}),
+		{{- range .Messages}}
+		{{template "message" .}},
+		{{- end}}
+	)
+	{{- end}}
+	return bundle
+}
+`)
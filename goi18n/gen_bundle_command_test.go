@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestGenBundle(t *testing.T) {
+	tests := []struct {
+		name         string
+		messageFiles map[string]string
+		packageName  string
+		expected     string
+		expectedErr  string
+	}{
+		{
+			name: "languages",
+			messageFiles: map[string]string{
+				"active.es.toml": `
+[Hello]
+hash = "v2-sha256-1"
+other = "Hola {{.Name}}"
+`,
+				"active.en.toml": `
+Hello = "Hello {{.Name}}"
+
+[PersonCats]
+description = "The number of cats a person has"
+leftDelim = "<<"
+rightDelim = ">>"
+one = "<<.Name>> has <<.Count>> cat."
+other = "<<.Name>> has <<.Count>> cats."
+`,
+				"active.de.toml": ``,
+			},
+			packageName: "messages",
+			expected: `// Code generated by goi18n gen-bundle; DO NOT EDIT.
+
+package messages
+
+import (
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// NewBundle returns a bundle whose default language is en with the messages of every language.
+func NewBundle() *i18n.Bundle {
+	bundle := i18n.NewBundle(language.MustParse("en"))
+	bundle.MustAddMessages(language.MustParse("en"),
+		&i18n.Message{
+			ID:    "Hello",
+			Other: "Hello {{.Name}}",
+		},
+		&i18n.Message{
+			ID:         "PersonCats",
+			LeftDelim:  "<<",
+			RightDelim: ">>",
+			One:        "<<.Name>> has <<.Count>> cat.",
+			Other:      "<<.Name>> has <<.Count>> cats.",
+		},
+	)
+	bundle.MustAddMessages(language.MustParse("es"),
+		&i18n.Message{
+			ID:    "Hello",
+			Other: "Hola {{.Name}}",
+		},
+	)
+	return bundle
+}
+`,
+		},
+		{
+			name: "invalid template",
+			messageFiles: map[string]string{
+				"active.en.toml": `Hello = "Hello {{.Name"`,
+			},
+			packageName: "messages",
+			expectedErr: `message "Hello" in active.en.toml has invalid other template: template: :1: unclosed action`,
+		},
+		{
+			name: "no plural rule",
+			messageFiles: map[string]string{
+				"active.en.toml":  `Hello = "Hello"`,
+				"active.tlh.toml": `Hello = "nuqneH"`,
+			},
+			packageName: "messages",
+			expectedErr: "failed to load message file active.tlh.toml: no plural rule registered for tlh",
+		},
+		{
+			name: "invalid package name",
+			messageFiles: map[string]string{
+				"active.en.toml": `Hello = "Hello"`,
+			},
+			packageName: "my-messages",
+			expectedErr: `invalid package name "my-messages"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			messageFiles := make(map[string][]byte, len(test.messageFiles))
+			for path, content := range test.messageFiles {
+				messageFiles[path] = []byte(content)
+			}
+			actual, err := genBundle(messageFiles, language.English, test.packageName)
+			if test.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q; got nil", test.expectedErr)
+				}
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q; got %q", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != test.expected {
+				t.Fatalf("\nexpected:\n%s\n\ngot:\n%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGenBundleCommand(t *testing.T) {
+	outdir := mustTempDir("TestGenBundleCommand")
+	defer mustRemoveAll(t, outdir)
+
+	if code := testableMain([]string{"gen-bundle", "-package", "messages", "-outdir", outdir, "../example/active.en.toml"}); code != 0 {
+		t.Fatalf("expected exit code 0; got %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(outdir, "bundle.go")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+)
+
+func usageGenerate() {
+	fmt.Fprintf(os.Stderr, `usage: goi18n generate [options] message file
+
+Generate reads the messages in a source language message file and writes a Go file
+that contains one function per message.
+
+Each function takes a *i18n.Localizer, an int count parameter if the message has plural forms,
+and one parameter for each field that the message's templates reference. Fields that are only
+printed (e.g. {{.Name}}) are string parameters and fields that are used in other ways
+(e.g. {{.User.Name}}, {{if .Admin}} or {{.Price | printf "%%.2f"}}) are interface{} parameters.
+Use i18n.LocalizeConfig directly to localize messages with decimal plural counts.
+
+	name.go
+		This file contains the generated functions, where name is the package name
+		(e.g. messages.go).
+
+Flags:
+
+	-package name
+		The package name of the generated file.
+		Default: messages
+
+	-outdir directory
+		Write the Go file to this directory.
+		Default: .
+`)
+}
+
+type generateCommand struct {
+	messageFile string
+	packageName string
+	outdir      string
+}
+
+func (gc *generateCommand) name() string {
+	return "generate"
+}
+
+func (gc *generateCommand) parse(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	flags.Usage = usageGenerate
+
+	flags.StringVar(&gc.packageName, "package", "messages", "")
+	flags.StringVar(&gc.outdir, "outdir", ".", "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("need exactly one message file to generate from")
+	}
+	gc.messageFile = flags.Arg(0)
+	return nil
+}
+
+func (gc *generateCommand) execute() error {
+	content, err := os.ReadFile(gc.messageFile)
+	if err != nil {
+		return err
+	}
+	src, err := generate(content, gc.messageFile, gc.packageName)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(gc.outdir, gc.packageName+".go"), src, 0666)
+}
+
+type generatedFunc struct {
+	Name        string
+	Message     *i18n.Message
+	Count       bool
+	Params      []generatedParam
+	Description []string
+}
+
+type generatedParam struct {
+	Field string
+	Name  string
+	Type  string
+}
+
+// generate returns the Go source of a package that contains a function for each message in the message file.
+func generate(content []byte, path, packageName string) ([]byte, error) {
+	if !token.IsIdentifier(packageName) {
+		return nil, fmt.Errorf("invalid package name %q", packageName)
+	}
+	mf, err := i18n.ParseMessageFileBytes(content, path, unmarshalFuncs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+	}
+	sort.Slice(mf.Messages, func(i, j int) bool {
+		return mf.Messages[i].ID < mf.Messages[j].ID
+	})
+	funcs := make([]*generatedFunc, 0, len(mf.Messages))
+	funcNames := map[string]string{}
+	for _, m := range mf.Messages {
+		mt := i18n.NewMessageTemplate(m)
+		if mt == nil {
+			continue
+		}
+		f, err := newGeneratedFunc(mt)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := funcNames[f.Name]; ok {
+			return nil, fmt.Errorf("message ids %q and %q both generate function %s", id, m.ID, f.Name)
+		}
+		funcNames[f.Name] = m.ID
+		funcs = append(funcs, f)
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		return funcs[i].Name < funcs[j].Name
+	})
+
+	var buf bytes.Buffer
+	if err := generateTemplate.Execute(&buf, map[string]interface{}{
+		"Package": packageName,
+		"Funcs":   funcs,
+	}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func newGeneratedFunc(mt *i18n.MessageTemplate) (*generatedFunc, error) {
+	f := &generatedFunc{
+		Name:    goIdentifier(mt.ID),
+		Message: mt.Message,
+		Count:   len(mt.PluralTemplates) > 1 || mt.PluralTemplates[plural.Other] == nil,
+	}
+	if f.Name == "" {
+		return nil, fmt.Errorf("message id %q can not be converted to a Go identifier", mt.ID)
+	}
+	if mt.Description != "" {
+		f.Description = strings.Split(mt.Description, "\n")
+	}
+	// fields are true if every template that references them only prints them.
+	fields := map[string]bool{}
+	for pluralForm, t := range mt.PluralTemplates {
+		names, err := internal.TemplateFields(t.Src, t.LeftDelim, t.RightDelim)
+		if err != nil {
+			return nil, fmt.Errorf("message %q has invalid %s template: %s", mt.ID, pluralForm, err)
+		}
+		plain, err := internal.TemplatePlainFields(t.Src, t.LeftDelim, t.RightDelim)
+		if err != nil {
+			return nil, fmt.Errorf("message %q has invalid %s template: %s", mt.ID, pluralForm, err)
+		}
+		for _, name := range names {
+			printed, ok := fields[name]
+			fields[name] = (printed || !ok) && containsString(plain, name)
+		}
+	}
+	// PluralCount is provided by the count parameter if there is one.
+	if f.Count {
+		delete(fields, "PluralCount")
+	}
+	used := map[string]struct{}{"l": {}, "count": {}}
+	for field, printed := range fields {
+		name := paramName(field)
+		for {
+			if _, ok := used[name]; !ok {
+				break
+			}
+			name += "_"
+		}
+		used[name] = struct{}{}
+		typ := "interface{}"
+		if printed {
+			typ = "string"
+		}
+		f.Params = append(f.Params, generatedParam{Field: field, Name: name, Type: typ})
+	}
+	sort.Slice(f.Params, func(i, j int) bool {
+		return f.Params[i].Field < f.Params[j].Field
+	})
+	return f, nil
+}
+
+// containsString returns true if the sorted strings contain s.
+func containsString(sorted []string, s string) bool {
+	i := sort.SearchStrings(sorted, s)
+	return i < len(sorted) && sorted[i] == s
+}
+
+// goIdentifier converts a message id (e.g. "person.unread-emails") to an exported Go identifier (e.g. "PersonUnreadEmails").
+func goIdentifier(id string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" {
+		return ""
+	}
+	if !unicode.IsUpper([]rune(s)[0]) {
+		// Identifiers that start with a digit or a letter without case must be prefixed to be exported.
+		s = "M" + s
+	}
+	return s
+}
+
+// paramName converts a template field name (e.g. "UnreadEmailCount") to a parameter name (e.g. "unreadEmailCount").
+func paramName(field string) string {
+	r := []rune(field)
+	r[0] = unicode.ToLower(r[0])
+	name := string(r)
+	if token.IsKeyword(name) {
+		name += "_"
+	}
+	return name
+}
+
+// messageTemplate defines the template "message", which writes an *i18n.Message literal
+// with the fields of the message that are not empty.
+const messageTemplate = `{{define "message"}}&i18n.Message{
+	ID: {{printf "%q" .ID}},
+	{{- with .Description}}
+	Description: {{printf "%q" .}},
+	{{- end}}
+	{{- with .LeftDelim}}
+	LeftDelim: {{printf "%q" .}},
+	{{- end}}
+	{{- with .RightDelim}}
+	RightDelim: {{printf "%q" .}},
+	{{- end}}
+	{{- with .Zero}}
+	Zero: {{printf "%q" .}},
+	{{- end}}
+	{{- with .One}}
+	One: {{printf "%q" .}},
+	{{- end}}
+	{{- with .Two}}
+	Two: {{printf "%q" .}},
+	{{- end}}
+	{{- with .Few}}
+	Few: {{printf "%q" .}},
+	{{- end}}
+	{{- with .Many}}
+	Many: {{printf "%q" .}},
+	{{- end}}
+	{{- with .Other}}
+	Other: {{printf "%q" .}},
+	{{- end}}
+}{{end}}`
+
+// newCodeTemplate returns the template of generated Go code with text, which can use the template "message".
+func newCodeTemplate(name, text string) *template.Template {
+	return template.Must(template.Must(template.New(name).Parse(messageTemplate)).Parse(text))
+}
+
+var generateTemplate = newCodeTemplate("generate", `// Code generated by goi18n generate; DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/nicksnyder/go-i18n/v2/i18n"
+{{range .Funcs}}
+// {{.Name}} localizes the message {{printf "%q" .Message.ID}}.
+{{- if .Description}}
+//
+{{- range .Description}}
+// {{.}}
+{{- end}}
+{{- end}}
+func {{.Name}}(l *i18n.Localizer{{if .Count}}, count int{{end}}{{range .Params}}, {{.Name}} {{.Type}}{{end}}) (string, error) {
+	return l.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: {{template "message" .Message}},
+		{{- if .Params}}
+		TemplateData: map[string]interface{}{
+			{{- range .Params}}
+			{{printf "%q" .Field}}: {{.Name}},
+			{{- end}}
+			{{- if .Count}}
+			"PluralCount": count,
+			{{- end}}
+		},
+		{{- end}}
+		{{- if .Count}}
+		PluralCount: count,
+		{{- end}}
+	})
+}
+{{end}}`)
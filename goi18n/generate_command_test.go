@@ -0,0 +1,207 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		content     string
+		packageName string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:        "simple message",
+			path:        "active.en.toml",
+			packageName: "messages",
+			content: `
+HelloWorld = "Hello World!"
+`,
+			expected: `// Code generated by goi18n generate; DO NOT EDIT.
+
+package messages
+
+import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+// HelloWorld localizes the message "HelloWorld".
+func HelloWorld(l *i18n.Localizer) (string, error) {
+	return l.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "HelloWorld",
+			Other: "Hello World!",
+		},
+	})
+}
+`,
+		},
+		{
+			name:        "template fields and plural count",
+			path:        "active.en.toml",
+			packageName: "msg",
+			content: `
+[person-cats]
+description = "The number of cats a person has"
+one = "{{.Name}} has {{.PluralCount}} cat."
+other = "{{.Name}} has {{.PluralCount}} cats{{with .Type}} of type {{.Name}}{{end}}."
+`,
+			expected: `// Code generated by goi18n generate; DO NOT EDIT.
+
+package msg
+
+import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+// PersonCats localizes the message "person-cats".
+//
+// The number of cats a person has
+func PersonCats(l *i18n.Localizer, count int, name string, type_ interface{}) (string, error) {
+	return l.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:          "person-cats",
+			Description: "The number of cats a person has",
+			One:         "{{.Name}} has {{.PluralCount}} cat.",
+			Other:       "{{.Name}} has {{.PluralCount}} cats{{with .Type}} of type {{.Name}}{{end}}.",
+		},
+		TemplateData: map[string]interface{}{
+			"Name":        name,
+			"Type":        type_,
+			"PluralCount": count,
+		},
+		PluralCount: count,
+	})
+}
+`,
+		},
+		{
+			name:        "custom delimiters",
+			path:        "active.en.toml",
+			packageName: "messages",
+			content: `
+[Delims]
+leftDelim = "<<"
+rightDelim = ">>"
+other = "Hello <<.Name>> {{.NotAField}}"
+`,
+			expected: `// Code generated by goi18n generate; DO NOT EDIT.
+
+package messages
+
+import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+// Delims localizes the message "Delims".
+func Delims(l *i18n.Localizer, name string) (string, error) {
+	return l.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:         "Delims",
+			LeftDelim:  "<<",
+			RightDelim: ">>",
+			Other:      "Hello <<.Name>> {{.NotAField}}",
+		},
+		TemplateData: map[string]interface{}{
+			"Name": name,
+		},
+	})
+}
+`,
+		},
+		{
+			name:        "parameter types",
+			path:        "active.en.toml",
+			packageName: "messages",
+			content: `
+[Order]
+one = "{{.Customer}} ordered {{.PluralCount}} item"
+other = "{{.Customer}} ordered {{range .Items}}{{.}} in {{$.Currency}}, {{end}}for {{.Total | printf \"%.2f\"}} {{.Customer | upper}}"
+`,
+			expected: `// Code generated by goi18n generate; DO NOT EDIT.
+
+package messages
+
+import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+// Order localizes the message "Order".
+func Order(l *i18n.Localizer, count int, currency string, customer interface{}, items interface{}, total interface{}) (string, error) {
+	return l.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{
+			ID:    "Order",
+			One:   "{{.Customer}} ordered {{.PluralCount}} item",
+			Other: "{{.Customer}} ordered {{range .Items}}{{.}} in {{$.Currency}}, {{end}}for {{.Total | printf \"%.2f\"}} {{.Customer | upper}}",
+		},
+		TemplateData: map[string]interface{}{
+			"Currency":    currency,
+			"Customer":    customer,
+			"Items":       items,
+			"Total":       total,
+			"PluralCount": count,
+		},
+		PluralCount: count,
+	})
+}
+`,
+		},
+		{
+			name:        "function name collision",
+			path:        "active.en.toml",
+			packageName: "messages",
+			content: `
+hello_world = "Hello World!"
+HelloWorld = "Hello World!"
+`,
+			expectedErr: `message ids "HelloWorld" and "hello_world" both generate function HelloWorld`,
+		},
+		{
+			name:        "invalid template",
+			path:        "active.en.toml",
+			packageName: "messages",
+			content: `
+HelloWorld = "Hello {{.Name"
+`,
+			expectedErr: `message "HelloWorld" has invalid other template: template: :1: unclosed action`,
+		},
+		{
+			name:        "invalid package name",
+			path:        "active.en.toml",
+			packageName: "my-messages",
+			content: `
+HelloWorld = "Hello World!"
+`,
+			expectedErr: `invalid package name "my-messages"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := generate([]byte(test.content), test.path, test.packageName)
+			if test.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q; got nil", test.expectedErr)
+				}
+				if err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q; got %q", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != test.expected {
+				t.Fatalf("\nexpected:\n%s\n\ngot:\n%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGenerateCommand(t *testing.T) {
+	outdir := mustTempDir("TestGenerateCommand")
+	defer mustRemoveAll(t, outdir)
+
+	if code := testableMain([]string{"generate", "-package", "messages", "-outdir", outdir, "../example/active.en.toml"}); code != 0 {
+		t.Fatalf("expected exit code 0; got %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(outdir, "messages.go")); err != nil {
+		t.Fatal(err)
+	}
+}
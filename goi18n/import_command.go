@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+func usageImport() {
+	fmt.Fprintf(os.Stderr, `usage: goi18n import [options] [message files] [files to import]
+
+Import reads the translations in the string resources of mobile platforms or the message files of
+other i18n libraries and writes them to message files (e.g. imported.es.toml) that can be merged
+into the active message files.
+
+	goi18n import -format android active.en.toml app/src/main/res/values-*/strings.xml
+	goi18n merge active.*.toml imported.*.toml
+
+The message files must contain the source messages, which determine the message ids and the
+template fields of printf arguments (see goi18n export). Printf arguments must have a position
+(e.g. %%1$s), except for the plural count in the plural forms of stringsdict variables, and other
+percent signs are text. Files to import are recognized by their
+extension (.xml, .xcstrings, .strings, .stringsdict, .arb, .json for i18next or .csv).
+Translations of the source language and of messages that are not in the source messages are not imported.
+
+Translations in CSV files are checked like goi18n merge checks translated message files.
+Translations of different source content (whose hash does not match the source message) are not
+imported, and translations whose template fields differ from the source message are reported.
+Imported message files contain the hash of each translation.
+
+Flags:
+
+	-format format
+		Read files in this format.
+		Supported formats: android, xcstrings, stringsdict, arb, i18next, csv
+
+	-sourceLanguage tag
+		The language of the source messages (e.g. en, en-US, zh-Hant-CN).
+		Default: en
+
+	-outdir directory
+		Write message files to this directory.
+		Default: .
+
+	-messageFormat format
+		Write message files in this format.
+		Supported formats: json, toml, yaml
+		Default: toml
+`)
+}
+
+type importCommand struct {
+	files          []string
+	format         string
+	sourceLanguage languageTag
+	outdir         string
+	messageFormat  string
+}
+
+func (ic *importCommand) name() string {
+	return "import"
+}
+
+func (ic *importCommand) parse(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	flags.Usage = usageImport
+
+	flags.StringVar(&ic.format, "format", "", "")
+	flags.Var(&ic.sourceLanguage, "sourceLanguage", "en")
+	flags.StringVar(&ic.outdir, "outdir", ".", "")
+	flags.StringVar(&ic.messageFormat, "messageFormat", "toml", "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if !supportedExportFormat(ic.format) {
+		return fmt.Errorf("unsupported format: %q", ic.format)
+	}
+	ic.files = flags.Args()
+	return nil
+}
+
+func (ic *importCommand) execute() error {
+	isFile := func(path string) bool {
+		if ic.format == csvFormat {
+			return isCSVFile(path)
+		}
+		if f, ok := libraryFormats[ic.format]; ok {
+			return f.isFile(path)
+		}
+		return nativeFormats[ic.format].isFile(path)
+	}
+	messageFiles := make(map[string][]byte)
+	nativeFiles := make(map[string][]byte)
+	for _, path := range ic.files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if isFile(path) {
+			nativeFiles[path] = content
+		} else {
+			messageFiles[path] = content
+		}
+	}
+	if len(messageFiles) < 1 {
+		return fmt.Errorf("need at least one message file with the source messages")
+	}
+	if len(nativeFiles) < 1 {
+		return fmt.Errorf("need at least one %s file to import", ic.format)
+	}
+	ops, err := importFiles(messageFiles, nativeFiles, ic.sourceLanguage.Tag(), ic.outdir, ic.messageFormat, ic.format)
+	if err != nil {
+		return err
+	}
+	for _, warning := range ops.warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	for path, content := range ops.writeFiles {
+		if err := os.WriteFile(path, content, 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importFiles returns the message files with the translations in the files of format.
+func importFiles(messageFiles, files map[string][]byte, sourceLanguageTag language.Tag, outdir, outputFormat, format string) (*fileSystemOp, error) {
+	merged, err := mergeMessageTemplates(messageFiles, sourceLanguageTag, placeholdersIgnore)
+	if err != nil {
+		return nil, err
+	}
+	var values map[language.Tag]map[string]interface{}
+	var warnings []string
+	if format == csvFormat {
+		if values, warnings, err = importCSV(files, sourceLanguageTag, merged.source); err != nil {
+			return nil, err
+		}
+	} else if f, ok := libraryFormats[format]; ok {
+		if values, warnings, err = importLibrary(f, files, sourceLanguageTag, merged.source); err != nil {
+			return nil, err
+		}
+	} else {
+		f := nativeFormats[format]
+		messages := make(map[language.Tag][]*nativeMessage)
+		for path, content := range files {
+			fileMessages, err := f.read(path, content, sourceLanguageTag)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", path, err)
+			}
+			for langTag, langMessages := range fileMessages {
+				if langTag != sourceLanguageTag {
+					messages[langTag] = append(messages[langTag], langMessages...)
+				}
+			}
+		}
+		values, warnings = importNative(f, merged.source, messages)
+	}
+	writeFiles := make(map[string][]byte, len(values))
+	for langTag, v := range values {
+		path, content, err := writeValue(outdir, "imported", langTag, outputFormat, v)
+		if err != nil {
+			return nil, err
+		}
+		writeFiles[path] = content
+	}
+	sort.Strings(warnings)
+	return &fileSystemOp{writeFiles: writeFiles, warnings: warnings}, nil
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestImport(t *testing.T) {
+	sourceFiles := map[string][]byte{
+		"active.en.toml": []byte(`
+Hello = "Hello {{.Name}}!"
+Home = "Home"
+
+["home.title"]
+other = "Welcome {{.Name}}"
+
+[PersonCats]
+one = "{{.Name}} has {{.Count}} cat."
+other = "{{.Name}} has {{.Count}} cats."
+
+[OneCat]
+leftDelim = "<<"
+rightDelim = ">>"
+one = "<<.Count>> cat"
+`),
+	}
+	hashes := sourceHashes(t, sourceFiles)
+	tests := []struct {
+		name        string
+		format      string
+		nativeFiles map[string]string
+		expected    map[string]string
+		warnings    []string
+	}{
+		{
+			name:   "android",
+			format: "android",
+			nativeFiles: map[string]string{
+				"res/values/strings.xml": `<resources><string name="Hello">Hi %1$s!</string></resources>`,
+				"res/values-es/strings.xml": `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="Hello">¡Hola <xliff:g id="name">%1$s</xliff:g>!</string>
+    <string name="home_title">"Bienvenido  %1$s"</string>
+    <string name="Home">Inicio \'casa\'
+        y   más</string>
+    <string name="app_name" translatable="false">Cats</string>
+    <string name="Removed">Eliminado</string>
+    <plurals name="PersonCats">
+        <item quantity="one">%2$s tiene %1$d gato.</item>
+        <item quantity="many">%2$s tiene %1$d de gatos.</item>
+        <item quantity="other">%2$s tiene %1$d gatos.</item>
+    </plurals>
+</resources>
+`,
+			},
+			expected: map[string]string{
+				"imported.es.toml": `[Hello]
+hash = "` + hashes["Hello"] + `"
+other = "¡Hola {{.Name}}!"
+
+[Home]
+hash = "` + hashes["Home"] + `"
+other = "Inicio 'casa' y más"
+
+[PersonCats]
+hash = "` + hashes["PersonCats"] + `"
+many = "{{.Name}} tiene {{.Count}} de gatos."
+one = "{{.Name}} tiene {{.Count}} gato."
+other = "{{.Name}} tiene {{.Count}} gatos."
+
+["home.title"]
+hash = "` + hashes["home.title"] + `"
+other = "Bienvenido  {{.Name}}"
+`,
+			},
+			warnings: []string{"es: Removed: no source message"},
+		},
+		{
+			name:   "stringsdict",
+			format: "stringsdict",
+			nativeFiles: map[string]string{
+				"fr.lproj/Localizable.strings": `/* Greeting */
+"Hello" = "Bonjour %1$@ !";
+// Title
+"home.title" = "Bienvenue \"%1$@\"";
+`,
+				"fr.lproj/Localizable.stringsdict": `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>PersonCats</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%2$@ a %#@cats@</string>
+		<key>cats</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d chat.</string>
+			<key>other</key>
+			<string>%d chats.</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`,
+			},
+			expected: map[string]string{
+				"imported.fr.toml": `[Hello]
+hash = "` + hashes["Hello"] + `"
+other = "Bonjour {{.Name}} !"
+
+[PersonCats]
+hash = "` + hashes["PersonCats"] + `"
+one = "{{.Name}} a {{.Count}} chat."
+other = "{{.Name}} a {{.Count}} chats."
+
+["home.title"]
+hash = "` + hashes["home.title"] + `"
+other = "Bienvenue \"{{.Name}}\""
+`,
+			},
+		},
+		{
+			name:   "xcstrings",
+			format: "xcstrings",
+			nativeFiles: map[string]string{
+				"Localizable.xcstrings": `{
+  "sourceLanguage" : "en",
+  "strings" : {
+    "Hello" : {
+      "localizations" : {
+        "de" : { "stringUnit" : { "state" : "translated", "value" : "Hallo %1$@!" } },
+        "en" : { "stringUnit" : { "state" : "translated", "value" : "Hi %1$@!" } },
+        "fr" : { "stringUnit" : { "state" : "needs_review", "value" : "Bonjour %1$@ !" } }
+      }
+    },
+    "PersonCats" : {
+      "localizations" : {
+        "de" : {
+          "variations" : {
+            "plural" : {
+              "one" : { "stringUnit" : { "state" : "translated", "value" : "%2$@ hat %1$lld Katze." } },
+              "other" : { "stringUnit" : { "state" : "translated", "value" : "%2$@ hat %1$lld Katzen." } }
+            }
+          }
+        }
+      }
+    }
+  },
+  "version" : "1.0"
+}`,
+			},
+			expected: map[string]string{
+				"imported.de.toml": `[Hello]
+hash = "` + hashes["Hello"] + `"
+other = "Hallo {{.Name}}!"
+
+[PersonCats]
+hash = "` + hashes["PersonCats"] + `"
+one = "{{.Name}} hat {{.Count}} Katze."
+other = "{{.Name}} hat {{.Count}} Katzen."
+`,
+			},
+		},
+		{
+			name:   "no other form",
+			format: "android",
+			nativeFiles: map[string]string{
+				"values-es/strings.xml": `<resources>
+    <plurals name="OneCat">
+        <item quantity="one">%1$d gato</item>
+        <item quantity="other">%1$d gatos</item>
+    </plurals>
+</resources>`,
+			},
+			expected: map[string]string{
+				"imported.es.toml": `[OneCat]
+hash = "` + hashes["OneCat"] + `"
+leftDelim = "<<"
+one = "<<.Count>> gato"
+other = "<<.Count>> gatos"
+rightDelim = ">>"
+`,
+			},
+		},
+		{
+			name:   "unknown argument",
+			format: "android",
+			nativeFiles: map[string]string{
+				"values-es/strings.xml": `<resources><string name="Hello">¡Hola %2$s!</string></resources>`,
+			},
+			expected: map[string]string{},
+			warnings: []string{"es: Hello: argument %2$s is not used by the source message"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nativeFiles := make(map[string][]byte, len(test.nativeFiles))
+			for path, content := range test.nativeFiles {
+				nativeFiles[path] = []byte(content)
+			}
+			ops, err := importFiles(sourceFiles, nativeFiles, language.English, ".", "toml", test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := make(map[string]string, len(ops.writeFiles))
+			for path, content := range ops.writeFiles {
+				actual[path] = string(content)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %q; got %q", test.expected, actual)
+			}
+			if !reflect.DeepEqual(ops.warnings, test.warnings) {
+				t.Errorf("expected warnings %q; got %q", test.warnings, ops.warnings)
+			}
+		})
+	}
+}
+
+func TestImportExported(t *testing.T) {
+	for _, format := range []string{"android", "xcstrings", "stringsdict", "arb", "i18next"} {
+		t.Run(format, func(t *testing.T) {
+			exported, err := export(exportMessageFiles, language.English, "res", format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ops, err := importFiles(exportMessageFiles, exported.writeFiles, language.English, ".", "toml", format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hashes := sourceHashes(t, exportMessageFiles)
+			expected := map[string]string{
+				"imported.es.toml": `[Hello]
+hash = "` + hashes["Hello"] + `"
+other = "¡Hola {{.Name}}, es 100% {{.Adjective}}!"
+
+[PersonCats]
+hash = "` + hashes["PersonCats"] + `"
+many = "{{.Name}} tiene {{.Count}} de gatos."
+one = "{{.Name}} tiene {{.Count}} gato."
+other = "{{.Name}} tiene {{.Count}} gatos."
+`,
+				"imported.ja.toml": `[PersonCats]
+hash = "` + hashes["PersonCats"] + `"
+other = "{{.Name}}は猫を{{.Count}}匹飼っています。"
+`,
+			}
+			actual := make(map[string]string, len(ops.writeFiles))
+			for path, content := range ops.writeFiles {
+				actual[path] = string(content)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected %q; got %q", expected, actual)
+			}
+		})
+	}
+}
+
+// sourceHashes returns the hashes of the source messages in messageFiles by id.
+func sourceHashes(t *testing.T, messageFiles map[string][]byte) map[string]string {
+	merged, err := mergeMessageTemplates(messageFiles, language.English, placeholdersIgnore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes := make(map[string]string, len(merged.source))
+	for id, template := range merged.source {
+		hashes[id] = template.Hash
+	}
+	return hashes
+}
+
+func TestImportCSV(t *testing.T) {
+	sourceFiles := map[string][]byte{
+		"active.en.toml": []byte(`
+Hello = "Hello {{.Name}}!"
+Home = "Home"
+
+[PersonCats]
+one = "{{.Name}} has {{.Count}} cat."
+other = "{{.Name}} has {{.Count}} cats."
+`),
+	}
+	merged, err := mergeMessageTemplates(sourceFiles, language.English, placeholdersIgnore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	helloHash, catsHash := merged.source["Hello"].Hash, merged.source["PersonCats"].Hash
+	// Spreadsheet programs save CSV files with a byte order mark.
+	content := "\ufeff" + `id,form,description,hash,en,es,ja
+Hello,other,,` + helloHash + `,Hello {{.Name}}!,¡Hola {{.Nombre}}!,こんにちは{{.Name}}!
+Home,other,,v2-sha256-stale,Home,Inicio,ホーム
+PersonCats,one,,` + catsHash + `,{{.Name}} has {{.Count}} cat.,"{{.Name}} tiene {{.Count}} gato.",
+PersonCats,many,,` + catsHash + `,{{.Name}} has {{.Count}} cats.,,
+PersonCats,other,,` + catsHash + `,{{.Name}} has {{.Count}} cats.,{{.Name}} tiene {{.Count}} gatos.,
+Removed,other,,,Removed,Eliminado,
+`
+	ops, err := importFiles(sourceFiles, map[string][]byte{"messages.csv": []byte(content)}, language.English, ".", "toml", "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"imported.es.toml": `[Hello]
+hash = "` + helloHash + `"
+other = "¡Hola {{.Nombre}}!"
+
+[PersonCats]
+hash = "` + catsHash + `"
+one = "{{.Name}} tiene {{.Count}} gato."
+other = "{{.Name}} tiene {{.Count}} gatos."
+`,
+		"imported.ja.toml": `[Hello]
+hash = "` + helloHash + `"
+other = "こんにちは{{.Name}}!"
+`,
+	}
+	actual := make(map[string]string, len(ops.writeFiles))
+	for path, content := range ops.writeFiles {
+		actual[path] = string(content)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %q; got %q", expected, actual)
+	}
+	expectedWarnings := []string{
+		"es: Home: translated from different source content",
+		"es: Removed: no source message",
+		`es: placeholders differ from the source language: message "Hello" plural form "other": missing field Name, extra field Nombre`,
+		"ja: Home: translated from different source content",
+	}
+	if !reflect.DeepEqual(ops.warnings, expectedWarnings) {
+		t.Errorf("expected warnings %q; got %q", expectedWarnings, ops.warnings)
+	}
+
+	// Imported files are merged like translate files.
+	messageFiles := map[string][]byte{"active.en.toml": sourceFiles["active.en.toml"]}
+	for path, content := range ops.writeFiles {
+		messageFiles[path] = content
+	}
+	mergeOps, err := merge(messageFiles, language.English, ".", "toml", "toml", placeholdersReject, &translationMemory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active := string(mergeOps.writeFiles["active.ja.toml"]); !strings.Contains(active, "こんにちは{{.Name}}!") {
+		t.Errorf("expected merged translation; got %q", active)
+	}
+	if active := string(mergeOps.writeFiles["active.es.toml"]); strings.Contains(active, "Nombre") {
+		t.Errorf("expected rejected translation; got %q", active)
+	}
+}
+
+func TestFromPrintf(t *testing.T) {
+	tests := []struct {
+		s        string
+		args     []string
+		expected string
+	}{
+		{s: "100 % de", expected: "100 % de"},
+		{s: "%1$s: 100 % de", args: []string{"Name"}, expected: "{{.Name}}: 100 % de"},
+		{s: "%1$s: 100%% de", args: []string{"Name"}, expected: "{{.Name}}: 100% de"},
+		{s: "%2$@ a %1$lld chats", args: []string{"Count", "Name"}, expected: "{{.Name}} a {{.Count}} chats"},
+	}
+	for _, test := range tests {
+		actual, err := fromPrintf(test.s, test.args, "", "")
+		if err != nil {
+			t.Errorf("fromPrintf(%q): %s", test.s, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("fromPrintf(%q) = %q; expected %q", test.s, actual, test.expected)
+		}
+	}
+}
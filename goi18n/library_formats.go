@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/i18n/arb"
+	"github.com/nicksnyder/go-i18n/v2/i18n/i18next"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// libraryFormat reads and writes the message files of another i18n library,
+// whose messages are converted by a package of go-i18n.
+type libraryFormat interface {
+	// isFile returns true if path is a message file of the library.
+	isFile(path string) bool
+
+	// write returns the path and content of the message file with the translations of the source messages
+	// into langTag and a warning for each message that can not be converted.
+	// The translations of the source language are nil.
+	write(outdir string, langTag language.Tag, source, translations []*i18n.Message) (path string, content []byte, warnings []string, err error)
+
+	// read returns the language and the messages of the message file at path.
+	read(path string, content []byte) (language.Tag, []*i18n.Message, error)
+}
+
+var libraryFormats = map[string]libraryFormat{
+	"arb":     arbFormat{},
+	"i18next": i18nextFormat{},
+}
+
+// arbFormat reads and writes the ARB files of Flutter apps (app_es.arb).
+type arbFormat struct{}
+
+func (arbFormat) isFile(path string) bool {
+	return filepath.Ext(path) == ".arb"
+}
+
+func (arbFormat) write(outdir string, langTag language.Tag, source, translations []*i18n.Message) (string, []byte, []string, error) {
+	f := &arb.File{Locale: langTag}
+	var warnings []string
+	for i, src := range source {
+		if err := f.AddMessage(src, translations[i]); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", langTag, err))
+		}
+	}
+	content, err := f.MarshalJSON()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	path := filepath.Join(outdir, fmt.Sprintf("app_%s.arb", strings.ReplaceAll(langTag.String(), "-", "_")))
+	return path, content, warnings, nil
+}
+
+// read returns the messages of the ARB file at path, whose language is its locale
+// or the suffix of its name (e.g. es for app_es.arb).
+func (arbFormat) read(path string, content []byte) (language.Tag, []*i18n.Message, error) {
+	f := &arb.File{}
+	if err := f.UnmarshalJSON(content); err != nil {
+		return language.Und, nil, err
+	}
+	if f.Locale != language.Und {
+		return f.Locale, f.Messages, nil
+	}
+	name := strings.TrimSuffix(filepath.Base(path), ".arb")
+	_, locale, ok := strings.Cut(name, "_")
+	if !ok {
+		return language.Und, nil, fmt.Errorf("no @@locale")
+	}
+	langTag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return language.Und, nil, err
+	}
+	return langTag, f.Messages, nil
+}
+
+// i18nextFormat reads and writes the JSON files of i18next (es/translation.json).
+type i18nextFormat struct{}
+
+func (i18nextFormat) isFile(path string) bool {
+	return filepath.Ext(path) == ".json"
+}
+
+func (i18nextFormat) write(outdir string, langTag language.Tag, source, translations []*i18n.Message) (string, []byte, []string, error) {
+	f := &i18next.File{}
+	var warnings []string
+	for i, src := range source {
+		if err := f.AddMessage(src, translations[i]); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", langTag, err))
+		}
+	}
+	content, err := f.MarshalJSON()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return filepath.Join(outdir, langTag.String(), "translation.json"), content, warnings, nil
+}
+
+// read returns the messages of the i18next file at path, whose language is the name
+// of its directory (es/translation.json) or its name (es.json).
+func (i18nextFormat) read(path string, content []byte) (language.Tag, []*i18n.Message, error) {
+	f := &i18next.File{}
+	if err := f.UnmarshalJSON(content); err != nil {
+		return language.Und, nil, err
+	}
+	langTag, err := language.Parse(filepath.Base(filepath.Dir(path)))
+	if err != nil {
+		if langTag, err = language.Parse(strings.TrimSuffix(filepath.Base(path), ".json")); err != nil {
+			return language.Und, nil, fmt.Errorf("no language in path")
+		}
+	}
+	return langTag, f.Messages, nil
+}
+
+// templateMessage returns the message with the content of the plural templates of t.
+func templateMessage(t *i18n.MessageTemplate) *i18n.Message {
+	m := &i18n.Message{ID: t.ID, Description: t.Description}
+	for form, pt := range t.PluralTemplates {
+		m.LeftDelim, m.RightDelim = pt.LeftDelim, pt.RightDelim
+		switch form {
+		case plural.Zero:
+			m.Zero = pt.Src
+		case plural.One:
+			m.One = pt.Src
+		case plural.Two:
+			m.Two = pt.Src
+		case plural.Few:
+			m.Few = pt.Src
+		case plural.Many:
+			m.Many = pt.Src
+		case plural.Other:
+			m.Other = pt.Src
+		}
+	}
+	return m
+}
+
+// exportLibrary returns the message files of format f with the message templates of each language.
+// Messages that can not be converted are skipped with a warning.
+func exportLibrary(f libraryFormat, outdir string, sourceLanguageTag language.Tag, source map[string]*i18n.MessageTemplate, all map[language.Tag]map[string]*i18n.MessageTemplate) (*fileSystemOp, error) {
+	writeFiles := make(map[string][]byte, len(all))
+	var warnings []string
+	for langTag, templates := range all {
+		sourceMessages := make([]*i18n.Message, 0, len(templates))
+		translations := make([]*i18n.Message, 0, len(templates))
+		for _, id := range sortedIDs(templates) {
+			sourceMessages = append(sourceMessages, templateMessage(source[id]))
+			var translation *i18n.Message
+			if langTag != sourceLanguageTag {
+				translation = templateMessage(templates[id])
+			}
+			translations = append(translations, translation)
+		}
+		path, content, fileWarnings, err := f.write(outdir, langTag, sourceMessages, translations)
+		if err != nil {
+			return nil, err
+		}
+		writeFiles[path] = content
+		warnings = append(warnings, fileWarnings...)
+	}
+	return &fileSystemOp{writeFiles: writeFiles, warnings: warnings}, nil
+}
+
+// importLibrary returns the message values by language of the translations of the source messages
+// in the message files of format f. Messages that are not in the source messages are skipped with a warning.
+func importLibrary(f libraryFormat, files map[string][]byte, sourceLanguageTag language.Tag, source map[string]*i18n.MessageTemplate) (map[language.Tag]map[string]interface{}, []string, error) {
+	values := make(map[language.Tag]map[string]interface{})
+	var warnings []string
+	for path, content := range files {
+		langTag, messages, err := f.read(path, content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %s", path, err)
+		}
+		if langTag == sourceLanguageTag {
+			continue
+		}
+		for _, m := range messages {
+			if source[m.ID] == nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s: no source message", langTag, m.ID))
+				continue
+			}
+			t := i18n.NewMessageTemplate(m)
+			if t == nil {
+				continue
+			}
+			v := make(map[string]interface{}, len(t.PluralTemplates)+1)
+			for form, pt := range t.PluralTemplates {
+				v[string(form)] = pt.Src
+			}
+			v["hash"] = source[m.ID].Hash
+			if values[langTag] == nil {
+				values[langTag] = make(map[string]interface{})
+			}
+			values[langTag][m.ID] = v
+		}
+	}
+	return values, warnings, nil
+}
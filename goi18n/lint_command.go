@@ -0,0 +1,257 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/i18n/template"
+	"github.com/nicksnyder/go-i18n/v2/internal"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+func usageLint() {
+	fmt.Fprintf(os.Stderr, `usage: goi18n lint [options] [message files]
+
+Lint reads all messages in the message files and reports problems with them.
+It exits with a non-zero exit code if any problems are found.
+
+The following problems are reported:
+
+	- templates that can not be parsed
+	- plural forms that are not used by the language of the file
+	- plural forms that are required by the language of the file but missing
+	- messages without an "other" plural form
+	- messages that set only one of leftDelim and rightDelim
+	- translations that use different delimiters, template fields or template functions than the source language
+
+Flags:
+
+	-sourceLanguage tag
+		The language tag of the source messages (e.g. en, en-US, zh-Hant-CN).
+		Default: en
+
+	-funcs names
+		A comma separated list of template function names that are available at runtime.
+`)
+}
+
+type lintCommand struct {
+	messageFiles   []string
+	sourceLanguage languageTag
+	funcs          string
+}
+
+func (lc *lintCommand) name() string {
+	return "lint"
+}
+
+func (lc *lintCommand) parse(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	flags.Usage = usageLint
+
+	flags.Var(&lc.sourceLanguage, "sourceLanguage", "en")
+	flags.StringVar(&lc.funcs, "funcs", "", "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	lc.messageFiles = flags.Args()
+	return nil
+}
+
+func (lc *lintCommand) execute() error {
+	if len(lc.messageFiles) < 1 {
+		return fmt.Errorf("need at least one message file to lint")
+	}
+	inFiles := make(map[string][]byte)
+	for _, path := range lc.messageFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		inFiles[path] = content
+	}
+	var funcs []string
+	if lc.funcs != "" {
+		funcs = strings.Split(lc.funcs, ",")
+	}
+	problems, err := lint(inFiles, lc.sourceLanguage.Tag(), funcs)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problems", len(problems))
+	}
+	return nil
+}
+
+type lintProblem struct {
+	path      string
+	messageID string
+	problem   string
+}
+
+func (p *lintProblem) String() string {
+	if p.messageID == "" {
+		return fmt.Sprintf("%s: %s", p.path, p.problem)
+	}
+	return fmt.Sprintf("%s: message %q %s", p.path, p.messageID, p.problem)
+}
+
+type lintFile struct {
+	path     string
+	tag      language.Tag
+	messages []*i18n.Message
+}
+
+// lint returns the problems found in messageFiles sorted by path and message id.
+func lint(messageFiles map[string][]byte, sourceLanguageTag language.Tag, funcs []string) ([]*lintProblem, error) {
+	files := make([]*lintFile, 0, len(messageFiles))
+	sourceMessages := make(map[string]*i18n.Message)
+	for path, content := range messageFiles {
+		mf, err := i18n.ParseMessageFileBytes(content, path, unmarshalFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+		}
+		files = append(files, &lintFile{path: path, tag: mf.Tag, messages: mf.Messages})
+		if mf.Tag == sourceLanguageTag {
+			for _, m := range mf.Messages {
+				sourceMessages[m.ID] = m
+			}
+		}
+	}
+
+	funcMap := texttemplate.FuncMap{}
+	for _, name := range funcs {
+		funcMap[strings.TrimSpace(name)] = func(...interface{}) interface{} { return nil }
+	}
+	parser := &template.TextParser{Funcs: funcMap}
+
+	pluralRules := plural.DefaultRules()
+	var problems []*lintProblem
+	for _, f := range files {
+		pluralRule := pluralRules.Rule(f.tag)
+		if pluralRule == nil {
+			problems = append(problems, &lintProblem{path: f.path, problem: fmt.Sprintf("has no plural rule for language %s", f.tag)})
+		}
+		for _, m := range f.messages {
+			report := func(format string, args ...interface{}) {
+				problems = append(problems, &lintProblem{path: f.path, messageID: m.ID, problem: fmt.Sprintf(format, args...)})
+			}
+			var src *i18n.Message
+			if f.tag != sourceLanguageTag {
+				src = sourceMessages[m.ID]
+			}
+			lintMessage(m, src, pluralRule, parser, report)
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].path != problems[j].path {
+			return problems[i].path < problems[j].path
+		}
+		return problems[i].messageID < problems[j].messageID
+	})
+	return problems, nil
+}
+
+// lintMessage reports the problems with m.
+// src is the source language message that m is a translation of, or nil if m is a source message.
+func lintMessage(m, src *i18n.Message, pluralRule *plural.Rule, parser template.Parser, report func(format string, args ...interface{})) {
+	if (m.LeftDelim == "") != (m.RightDelim == "") {
+		report("sets only one of leftDelim %q and rightDelim %q", m.LeftDelim, m.RightDelim)
+	}
+	if m.Other == "" {
+		report("has no %q plural form", plural.Other)
+	}
+
+	mt := i18n.NewMessageTemplate(m)
+	if mt == nil {
+		return
+	}
+	var srcTemplate *i18n.MessageTemplate
+	if src != nil {
+		srcTemplate = i18n.NewMessageTemplate(src)
+	}
+	forms := sortedPluralForms(mt.PluralTemplates)
+	for _, form := range forms {
+		t := mt.PluralTemplates[form]
+		if _, err := parser.Parse(t.Src, t.LeftDelim, t.RightDelim); err != nil {
+			report("has invalid %s template: %s", form, err)
+		}
+	}
+
+	if pluralRule != nil {
+		for _, form := range forms {
+			if _, ok := pluralRule.PluralForms[form]; !ok {
+				report("has plural form %q that is not used by the language", form)
+			}
+		}
+		// Messages with only an "other" form are not pluralized.
+		pluralized := len(mt.PluralTemplates) > 1 || mt.PluralTemplates[plural.Other] == nil
+		if srcTemplate != nil && len(srcTemplate.PluralTemplates) > 1 {
+			pluralized = true
+		}
+		if pluralized {
+			for _, form := range sortedPluralFormSet(pluralRule.PluralForms) {
+				if mt.PluralTemplates[form] == nil {
+					report("is missing required plural form %q", form)
+				}
+			}
+		}
+	}
+
+	if srcTemplate == nil {
+		return
+	}
+	if m.LeftDelim != src.LeftDelim || m.RightDelim != src.RightDelim {
+		report("has delimiters %q %q but the source message has %q %q", m.LeftDelim, m.RightDelim, src.LeftDelim, src.RightDelim)
+		return
+	}
+	diffs, err := i18n.ComparePlaceholders(src, m)
+	if err != nil {
+		// Invalid templates are reported above.
+		return
+	}
+	for _, d := range diffs {
+		for _, field := range d.MissingFields {
+			report("plural form %q is missing template field %q that the source message uses", d.PluralForm, field)
+		}
+		for _, field := range d.ExtraFields {
+			report("plural form %q uses template field %q that the source message does not use", d.PluralForm, field)
+		}
+		for _, fn := range d.MissingFuncs {
+			report("plural form %q is missing template function %q that the source message uses", d.PluralForm, fn)
+		}
+		for _, fn := range d.ExtraFuncs {
+			report("plural form %q uses template function %q that the source message does not use", d.PluralForm, fn)
+		}
+	}
+}
+
+func sortedPluralForms(templates map[plural.Form]*internal.Template) []plural.Form {
+	forms := make(map[plural.Form]struct{}, len(templates))
+	for form := range templates {
+		forms[form] = struct{}{}
+	}
+	return sortedPluralFormSet(forms)
+}
+
+// sortedPluralFormSet returns the plural forms in set in CLDR order.
+func sortedPluralFormSet(set map[plural.Form]struct{}) []plural.Form {
+	forms := make([]plural.Form, 0, len(set))
+	for _, form := range plural.Forms {
+		if _, ok := set[form]; ok {
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		inFiles  map[string][]byte
+		funcs    []string
+		problems []string
+	}{
+		{
+			name: "no problems",
+			inFiles: map[string][]byte{
+				"active.en.toml": []byte(`
+HelloPerson = "Hello {{.Name}}"
+
+[UnreadEmails]
+one = "{{.Count}} unread email"
+other = "{{.Count}} unread emails"
+`),
+				"active.es.toml": []byte(`
+[HelloPerson]
+other = "Hola {{.Name}}"
+
+[UnreadEmails]
+many = "{{.Count}} correos electrónicos no leídos"
+one = "{{.Count}} correo electrónico no leído"
+other = "{{.Count}} correos electrónicos no leídos"
+`),
+			},
+		},
+		{
+			name: "invalid template",
+			inFiles: map[string][]byte{
+				"active.en.toml": []byte(`
+Unclosed = "Hello {{.Name"
+UnknownFunc = "Hello {{upper .Name}}"
+`),
+			},
+			problems: []string{
+				`active.en.toml: message "Unclosed" has invalid other template: template: :1: unclosed action`,
+				`active.en.toml: message "UnknownFunc" has invalid other template: template: :1: function "upper" not defined`,
+			},
+		},
+		{
+			name: "registered func",
+			inFiles: map[string][]byte{
+				"active.en.toml": []byte(`
+UnknownFunc = "Hello {{upper .Name}}"
+`),
+			},
+			funcs: []string{"upper"},
+		},
+		{
+			name: "plural forms",
+			inFiles: map[string][]byte{
+				"active.en.toml": []byte(`
+[UnreadEmails]
+few = "{{.Count}} unread emails"
+one = "{{.Count}} unread email"
+`),
+			},
+			problems: []string{
+				`active.en.toml: message "UnreadEmails" has no "other" plural form`,
+				`active.en.toml: message "UnreadEmails" has plural form "few" that is not used by the language`,
+				`active.en.toml: message "UnreadEmails" is missing required plural form "other"`,
+			},
+		},
+		{
+			name: "translation missing plural forms",
+			inFiles: map[string][]byte{
+				"active.en.toml": []byte(`
+[UnreadEmails]
+one = "{{.Count}} unread email"
+other = "{{.Count}} unread emails"
+`),
+				"active.es.toml": []byte(`
+UnreadEmails = "{{.Count}} correos electrónicos no leídos"
+`),
+			},
+			problems: []string{
+				`active.es.toml: message "UnreadEmails" is missing required plural form "one"`,
+				`active.es.toml: message "UnreadEmails" is missing required plural form "many"`,
+			},
+		},
+		{
+			name: "delimiters",
+			inFiles: map[string][]byte{
+				"active.en.toml": []byte(`
+[Left]
+leftDelim = "<<"
+other = "Hello <<.Name}}"
+
+[Different]
+leftDelim = "<<"
+rightDelim = ">>"
+other = "Hello <<.Name>>"
+`),
+				"active.es.toml": []byte(`
+Different = "Hola {{.Name}}"
+`),
+			},
+			problems: []string{
+				`active.en.toml: message "Left" sets only one of leftDelim "<<" and rightDelim ""`,
+				`active.es.toml: message "Different" has delimiters "" "" but the source message has "<<" ">>"`,
+			},
+		},
+		{
+			name: "template fields",
+			inFiles: map[string][]byte{
+				"active.en.toml": []byte(`
+HelloPerson = "Hello {{.Name}}"
+`),
+				"active.es.toml": []byte(`
+HelloPerson = "Hola {{.Nombre}}"
+`),
+			},
+			problems: []string{
+				`active.es.toml: message "HelloPerson" plural form "other" is missing template field "Name" that the source message uses`,
+				`active.es.toml: message "HelloPerson" plural form "other" uses template field "Nombre" that the source message does not use`,
+			},
+		},
+		{
+			name: "unknown language",
+			inFiles: map[string][]byte{
+				"active.en.toml":  []byte(`HelloPerson = "Hello {{.Name}}"`),
+				"active.tlh.toml": []byte(`HelloPerson = "{{.Name}}"`),
+			},
+			problems: []string{
+				`active.tlh.toml: has no plural rule for language tlh`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problems, err := lint(test.inFiles, language.English, test.funcs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := make([]string, 0, len(problems))
+			for _, p := range problems {
+				actual = append(actual, p.String())
+			}
+			if strings.Join(actual, "\n") != strings.Join(test.problems, "\n") {
+				t.Fatalf("\nexpected:\n%s\n\ngot:\n%s", strings.Join(test.problems, "\n"), strings.Join(actual, "\n"))
+			}
+		})
+	}
+}
+
+func TestLintCommand(t *testing.T) {
+	indir := mustTempDir("TestLintCommand")
+	defer mustRemoveAll(t, indir)
+
+	valid := filepath.Join(indir, "active.en.toml")
+	if err := os.WriteFile(valid, []byte(`HelloPerson = "Hello {{.Name}}"`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if code := testableMain([]string{"lint", valid}); code != 0 {
+		t.Fatalf("expected exit code 0; got %d", code)
+	}
+
+	invalid := filepath.Join(indir, "active.es.toml")
+	if err := os.WriteFile(invalid, []byte(`HelloPerson = "Hola {{.Nombre}}"`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if code := testableMain([]string{"lint", valid, invalid}); code != 1 {
+		t.Fatalf("expected exit code 1; got %d", code)
+	}
+}
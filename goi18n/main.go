@@ -16,7 +16,7 @@
 //	# translate.es.toml
 //	[PersonCats]
 //	description = "The number of cats a person has"
-//	hash = "sha1-f937a0e05e19bfe6cd70937c980eaf1f9832f091"
+//	hash = "v2-sha256-c9edc20cf187049d3a40f8ce81c8b613f941518421d2b01f9a6429d1e3c3f738"
 //	one = "{{.Name}} has {{.Count}} cat."
 //	other = "{{.Name}} has {{.Count}} cats."
 //
@@ -25,7 +25,7 @@
 //	# active.es.toml
 //	[PersonCats]
 //	description = "The number of cats a person has"
-//	hash = "sha1-f937a0e05e19bfe6cd70937c980eaf1f9832f091"
+//	hash = "v2-sha256-c9edc20cf187049d3a40f8ce81c8b613f941518421d2b01f9a6429d1e3c3f738"
 //	one = "{{.Name}} tiene {{.Count}} gato."
 //	other = "{{.Name}} tiene {{.Count}} gatos."
 //
@@ -54,6 +54,14 @@ The commands are:
 
 	merge		merge message files
 	extract		extract messages from Go files
+	generate	generate Go functions for messages
+	lint		report problems in message files
+	stats		print translation coverage of message files
+	prune		remove messages that are no longer used
+	export		write messages as string resources or message files of other libraries
+	import		read translations from string resources or message files of other libraries
+	compile		write messages to a binary file that loads fast
+	gen-bundle	generate a Go function that returns a bundle with all messages
 
 Workflow:
 
@@ -70,7 +78,7 @@ Workflow:
 		# translate.es.toml
 		[PersonCats]
 		description = "The number of cats a person has"
-		hash = "sha1-f937a0e05e19bfe6cd70937c980eaf1f9832f091"
+		hash = "v2-sha256-c9edc20cf187049d3a40f8ce81c8b613f941518421d2b01f9a6429d1e3c3f738"
 		one = "{{.Name}} has {{.Count}} cat."
 		other = "{{.Name}} has {{.Count}} cats."
 
@@ -79,7 +87,7 @@ Workflow:
 		# active.es.toml
 		[PersonCats]
 		description = "The number of cats a person has"
-		hash = "sha1-f937a0e05e19bfe6cd70937c980eaf1f9832f091"
+		hash = "v2-sha256-c9edc20cf187049d3a40f8ce81c8b613f941518421d2b01f9a6429d1e3c3f738"
 		one = "{{.Name}} tiene {{.Count}} gato."
 		other = "{{.Name}} tiene {{.Count}} gatos."
 
@@ -116,6 +124,14 @@ func testableMain(args []string) int {
 	commands := []command{
 		&mergeCommand{},
 		&extractCommand{},
+		&generateCommand{},
+		&lintCommand{},
+		&statsCommand{},
+		&pruneCommand{},
+		&exportCommand{},
+		&importCommand{},
+		&compileCommand{},
+		&genBundleCommand{},
 	}
 	cmdName := flags.Arg(0)
 	for _, cmd := range commands {
@@ -6,6 +6,7 @@ import (
 )
 
 func TestMain(t *testing.T) {
+	outdir := t.TempDir()
 	testCases := []struct {
 		args     []string
 		exitCode int
@@ -15,13 +16,17 @@ func TestMain(t *testing.T) {
 			exitCode: 2,
 		},
 		{
-			args:     []string{"extract"},
+			args:     []string{"extract", "-outdir", outdir},
 			exitCode: 0,
 		},
 		{
 			args:     []string{"merge"},
 			exitCode: 1,
 		},
+		{
+			args:     []string{"generate"},
+			exitCode: 1,
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(strings.Join(testCase.args, " "), func(t *testing.T) {
@@ -8,13 +8,33 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/i18n/gettext"
 	"github.com/nicksnyder/go-i18n/v2/internal/plural"
 	yaml "go.yaml.in/yaml/v3"
 	"golang.org/x/text/language"
 )
 
+var unmarshalFuncs = map[string]i18n.UnmarshalFunc{
+	"json": withoutSuggestions(json.Unmarshal),
+	"po":   gettext.Unmarshal,
+	"toml": withoutSuggestions(toml.Unmarshal),
+	"xlf":  unmarshalXLIFF,
+	"yaml": withoutSuggestions(yaml.Unmarshal),
+}
+
+// suggestionUnmarshalFuncs are the unmarshal functions of the formats whose translate files contain suggestions.
+var suggestionUnmarshalFuncs = map[string]i18n.UnmarshalFunc{
+	"json": json.Unmarshal,
+	"toml": toml.Unmarshal,
+	"yaml": yaml.Unmarshal,
+}
+
 func writeFile(outdir, label string, langTag language.Tag, format string, messageTemplates map[string]*i18n.MessageTemplate, sourceLanguage bool) (path string, content []byte, err error) {
-	v := marshalValue(messageTemplates, sourceLanguage)
+	return writeValue(outdir, label, langTag, format, marshalValue(messageTemplates, sourceLanguage))
+}
+
+// writeValue returns the path and content of the message file with the value v, which is usually returned by marshalValue.
+func writeValue(outdir, label string, langTag language.Tag, format string, v map[string]interface{}) (path string, content []byte, err error) {
 	content, err = marshal(v, format)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to marshal %s strings to %s: %s", langTag, format, err)
@@ -23,17 +43,21 @@ func writeFile(outdir, label string, langTag language.Tag, format string, messag
 	return
 }
 
-func marshalValue(messageTemplates map[string]*i18n.MessageTemplate, sourceLanguage bool) interface{} {
+func marshalValue(messageTemplates map[string]*i18n.MessageTemplate, sourceLanguage bool) map[string]interface{} {
 	v := make(map[string]interface{}, len(messageTemplates))
 	for id, template := range messageTemplates {
 		if other := template.PluralTemplates[plural.Other]; sourceLanguage && len(template.PluralTemplates) == 1 &&
-			other != nil && template.Description == "" && template.LeftDelim == "" && template.RightDelim == "" {
+			other != nil && template.Description == "" && template.LeftDelim == "" && template.RightDelim == "" &&
+			len(template.References) == 0 {
 			v[id] = other.Src
 		} else {
-			m := map[string]string{}
+			m := map[string]interface{}{}
 			if template.Description != "" {
 				m["description"] = template.Description
 			}
+			if len(template.References) > 0 {
+				m["references"] = template.References
+			}
 			if !sourceLanguage {
 				m["hash"] = template.Hash
 			}
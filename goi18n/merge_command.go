@@ -2,17 +2,18 @@ package main
 
 import (
 	"crypto/sha1"
-	"encoding/json"
+	"crypto/sha256"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
-	"github.com/BurntSushi/toml"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/nicksnyder/go-i18n/v2/internal"
 	"github.com/nicksnyder/go-i18n/v2/internal/plural"
-	yaml "go.yaml.in/yaml/v3"
 	"golang.org/x/text/language"
 )
 
@@ -47,14 +48,44 @@ Flags:
 		Output message files in this format.
 		Supported formats: json, toml, yaml
 		Default: toml
+
+	-translateFormat format
+		Output translate files in this format.
+		Supported formats: json, toml, yaml, po, xliff12, xliff20
+		The po format writes gettext PO files (e.g. translate.es.po) and a POT file
+		with all source messages (translate.pot). Translated PO files can be merged.
+		The xliff12 and xliff20 formats write XLIFF 1.2 and 2.0 files (e.g. translate.es.xlf)
+		where template actions are placeholders. Translated XLIFF files can be merged.
+		Default: the value of -format
+
+	-placeholders mode
+		What to do with translations whose template fields or functions differ from the source language.
+		Supported modes: ignore, warn, reject
+		Rejected translations are written to the translate files again.
+		Default: warn
+
+	-memory file
+		Read and update a translation memory file (e.g. memory.toml) that records every
+		complete translation with the source content it was translated from.
+
+Messages that need to be translated contain a suggestion if a previous translation is known:
+the stale translation of the message, a translation of another message with the same source
+content (e.g. after the message id was renamed) or the most similar translation in the
+translation memory. Suggestions contain the previous translation, the source content it was
+translated from and the similarity of that content to the current source content from 0 to 1.
+Suggestions are ignored when translated files are merged. In PO files, suggestions are fuzzy
+translations, which are merged after a translator removes the fuzzy flag.
 `)
 }
 
 type mergeCommand struct {
-	messageFiles   []string
-	sourceLanguage languageTag
-	outdir         string
-	format         string
+	messageFiles    []string
+	sourceLanguage  languageTag
+	outdir          string
+	format          string
+	translateFormat string
+	placeholders    string
+	memory          string
 }
 
 func (mc *mergeCommand) name() string {
@@ -68,10 +99,19 @@ func (mc *mergeCommand) parse(args []string) error {
 	flags.Var(&mc.sourceLanguage, "sourceLanguage", "en")
 	flags.StringVar(&mc.outdir, "outdir", ".", "")
 	flags.StringVar(&mc.format, "format", "toml", "")
+	flags.StringVar(&mc.translateFormat, "translateFormat", "", "")
+	flags.StringVar(&mc.placeholders, "placeholders", placeholdersWarn, "")
+	flags.StringVar(&mc.memory, "memory", "", "")
 	if err := flags.Parse(args); err != nil {
 		return err
 	}
 
+	if mc.translateFormat == "" {
+		mc.translateFormat = mc.format
+	}
+	if err := checkPlaceholdersMode(mc.placeholders); err != nil {
+		return err
+	}
 	mc.messageFiles = flags.Args()
 	return nil
 }
@@ -88,10 +128,30 @@ func (mc *mergeCommand) execute() error {
 		}
 		inFiles[path] = content
 	}
-	ops, err := merge(inFiles, mc.sourceLanguage.Tag(), mc.outdir, mc.format)
+	memory := &translationMemory{}
+	if mc.memory != "" {
+		content, err := os.ReadFile(mc.memory)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if memory, err = parseTranslationMemory(content, mc.memory); err != nil {
+			return err
+		}
+	}
+	ops, err := merge(inFiles, mc.sourceLanguage.Tag(), mc.outdir, mc.format, mc.translateFormat, mc.placeholders, memory)
 	if err != nil {
 		return err
 	}
+	if mc.memory != "" {
+		content, err := memory.marshal(mc.memory)
+		if err != nil {
+			return err
+		}
+		ops.writeFiles[mc.memory] = content
+	}
+	for _, warning := range ops.warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
 	for path, content := range ops.writeFiles {
 		if err := os.WriteFile(path, content, 0666); err != nil {
 			return err
@@ -107,16 +167,43 @@ func (mc *mergeCommand) execute() error {
 type fileSystemOp struct {
 	writeFiles  map[string][]byte
 	deleteFiles []string
+	warnings    []string
 }
 
-func merge(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdir, outputFormat string) (*fileSystemOp, error) {
+// Modes for handling translations whose placeholders differ from the source language.
+const (
+	placeholdersIgnore = "ignore"
+	placeholdersWarn   = "warn"
+	placeholdersReject = "reject"
+)
+
+// mergedMessageTemplates are the message templates of all languages merged by message id.
+type mergedMessageTemplates struct {
+	// source contains the message templates of the source language by id.
+	source map[string]*i18n.MessageTemplate
+
+	// all contains the merged message templates by language and id.
+	// Translations of different source content are discarded.
+	all map[language.Tag]map[string]*i18n.MessageTemplate
+
+	// stale contains the translations of different source content by language and id.
+	stale map[language.Tag]map[string]*i18n.MessageTemplate
+
+	// unmerged contains the message templates of each message file by language.
+	unmerged map[language.Tag][]map[string]*i18n.MessageTemplate
+
+	// activeByHash contains the translations in message files that are not translate files
+	// by language and the hash of the source content, sorted by id.
+	activeByHash map[language.Tag]map[string][]*i18n.MessageTemplate
+
+	warnings []string
+}
+
+func mergeMessageTemplates(messageFiles map[string][]byte, sourceLanguageTag language.Tag, placeholders string) (*mergedMessageTemplates, error) {
+	var warnings []string
 	unmerged := make(map[language.Tag][]map[string]*i18n.MessageTemplate)
+	activeByHash := make(map[language.Tag]map[string][]*i18n.MessageTemplate)
 	sourceMessageTemplates := make(map[string]*i18n.MessageTemplate)
-	unmarshalFuncs := map[string]i18n.UnmarshalFunc{
-		"json": json.Unmarshal,
-		"toml": toml.Unmarshal,
-		"yaml": yaml.Unmarshal,
-	}
 	for path, content := range messageFiles {
 		mf, err := i18n.ParseMessageFileBytes(content, path, unmarshalFuncs)
 		if err != nil {
@@ -138,9 +225,25 @@ func merge(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdi
 				template.Hash = hash(template)
 				sourceMessageTemplates[template.ID] = template
 			}
+		} else if !isTranslateFile(path) {
+			if activeByHash[mf.Tag] == nil {
+				activeByHash[mf.Tag] = make(map[string][]*i18n.MessageTemplate)
+			}
+			for _, template := range templates {
+				if template.Hash != "" {
+					activeByHash[mf.Tag][template.Hash] = append(activeByHash[mf.Tag][template.Hash], template)
+				}
+			}
 		}
 		unmerged[mf.Tag] = append(unmerged[mf.Tag], templates)
 	}
+	for _, byHash := range activeByHash {
+		for _, templates := range byHash {
+			sort.Slice(templates, func(i, j int) bool {
+				return templates[i].ID < templates[j].ID
+			})
+		}
+	}
 
 	if len(sourceMessageTemplates) == 0 {
 		return nil, fmt.Errorf("no messages found for source locale %s", sourceLanguageTag)
@@ -149,6 +252,7 @@ func merge(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdi
 	pluralRules := plural.DefaultRules()
 	all := make(map[language.Tag]map[string]*i18n.MessageTemplate)
 	all[sourceLanguageTag] = sourceMessageTemplates
+	stale := make(map[language.Tag]map[string]*i18n.MessageTemplate)
 	for _, srcTemplate := range sourceMessageTemplates {
 		for dstLangTag, messageTemplates := range unmerged {
 			if dstLangTag == sourceLanguageTag {
@@ -182,14 +286,25 @@ func merge(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdi
 				if unmergedTemplate == nil {
 					continue
 				}
-				// Ignore empty hashes for v1 backward compatibility.
-				if unmergedTemplate.Hash != "" && unmergedTemplate.Hash != srcTemplate.Hash {
+				if !translatedFrom(unmergedTemplate.Hash, srcTemplate) {
 					// This was translated from different content so discard.
+					if stale[dstLangTag] == nil {
+						stale[dstLangTag] = make(map[string]*i18n.MessageTemplate)
+					}
+					stale[dstLangTag][srcTemplate.ID] = unmergedTemplate
 					continue
 				}
 
+				rejected, warning := checkPlaceholders(srcTemplate, unmergedTemplate, dstLangTag, placeholders)
+				if warning != "" {
+					warnings = append(warnings, warning)
+				}
+
 				// Merge in the translated messages.
 				for pluralForm := range pluralRule.PluralForms {
+					if _, ok := rejected[pluralForm]; ok {
+						continue
+					}
 					dt := unmergedTemplate.PluralTemplates[pluralForm]
 					if dt != nil && dt.Src != "" {
 						dstMessageTemplate.PluralTemplates[pluralForm] = dt
@@ -199,9 +314,30 @@ func merge(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdi
 		}
 	}
 
+	return &mergedMessageTemplates{
+		source:       sourceMessageTemplates,
+		all:          all,
+		stale:        stale,
+		unmerged:     unmerged,
+		activeByHash: activeByHash,
+		warnings:     warnings,
+	}, nil
+}
+
+// merge merges messageFiles and returns the message files to write and delete.
+// Active files are written in outputFormat and translate files in translateFormat.
+// Complete translations are added to memory, which is used to suggest previous
+// translations for messages that need to be translated.
+func merge(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdir, outputFormat, translateFormat, placeholders string, memory *translationMemory) (*fileSystemOp, error) {
+	merged, err := mergeMessageTemplates(messageFiles, sourceLanguageTag, placeholders)
+	if err != nil {
+		return nil, err
+	}
+	sourceMessageTemplates := merged.source
+	pluralRules := plural.DefaultRules()
 	translate := make(map[language.Tag]map[string]*i18n.MessageTemplate)
 	active := make(map[language.Tag]map[string]*i18n.MessageTemplate)
-	for langTag, messageTemplates := range all {
+	for langTag, messageTemplates := range merged.all {
 		active[langTag] = make(map[string]*i18n.MessageTemplate)
 		if langTag == sourceLanguageTag {
 			active[langTag] = messageTemplates
@@ -224,13 +360,29 @@ func merge(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdi
 			}
 			if activeMessageTemplate != nil {
 				active[langTag][messageTemplate.ID] = activeMessageTemplate
+				if translateMessageTemplate == nil {
+					memory.add(langTag, srcMessageTemplate, activeMessageTemplate)
+				}
 			}
 		}
 	}
 
 	writeFiles := make(map[string][]byte, len(translate)+len(active))
 	for langTag, messageTemplates := range translate {
-		path, content, err := writeFile(outdir, "translate", langTag, outputFormat, messageTemplates, false)
+		suggestions := make(map[string]*suggestion)
+		for id := range messageTemplates {
+			if s := suggest(langTag, sourceMessageTemplates[id], merged, memory); s != nil {
+				suggestions[id] = s
+			}
+		}
+		path, content, err := writeTranslateFile(outdir, sourceLanguageTag, langTag, translateFormat, sourceMessageTemplates, messageTemplates, suggestions)
+		if err != nil {
+			return nil, err
+		}
+		writeFiles[path] = content
+	}
+	if translateFormat == "po" {
+		path, content, err := writePOTFile(outdir, sourceMessageTemplates)
 		if err != nil {
 			return nil, err
 		}
@@ -248,7 +400,73 @@ func merge(messageFiles map[string][]byte, sourceLanguageTag language.Tag, outdi
 			deleteFiles = append(deleteFiles, path)
 		}
 	}
-	return &fileSystemOp{writeFiles: writeFiles, deleteFiles: deleteFiles}, nil
+	warnings := merged.warnings
+	sort.Strings(warnings)
+	return &fileSystemOp{writeFiles: writeFiles, deleteFiles: deleteFiles, warnings: warnings}, nil
+}
+
+// isTranslateFile returns true if path is a file with messages that need to be translated (e.g. translate.es.toml).
+func isTranslateFile(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), "translate.")
+}
+
+// writeTranslateFile returns the path and content of the file with the messages that need to be translated to langTag.
+func writeTranslateFile(outdir string, sourceLanguageTag, langTag language.Tag, format string, sourceMessageTemplates, messageTemplates map[string]*i18n.MessageTemplate, suggestions map[string]*suggestion) (path string, content []byte, err error) {
+	switch format {
+	case "po":
+		return writePOFile(outdir, langTag, sourceMessageTemplates, messageTemplates, suggestions)
+	case xliff12, xliff20:
+		return writeXLIFFFile(outdir, format, sourceLanguageTag, langTag, sourceMessageTemplates, messageTemplates, suggestions)
+	}
+	v := marshalValue(messageTemplates, false)
+	for id, s := range suggestions {
+		v[id].(map[string]interface{})["suggestion"] = s.value()
+	}
+	return writeValue(outdir, "translate", langTag, format, v)
+}
+
+func checkPlaceholdersMode(placeholders string) error {
+	switch placeholders {
+	case placeholdersIgnore, placeholdersWarn, placeholdersReject:
+		return nil
+	}
+	return fmt.Errorf("unsupported placeholders mode: %s", placeholders)
+}
+
+// checkPlaceholders compares the placeholders of a translation to the source message
+// and returns the plural forms that should be rejected and a warning to print, if any.
+func checkPlaceholders(src, dst *i18n.MessageTemplate, dstLangTag language.Tag, placeholders string) (rejected map[plural.Form]struct{}, warning string) {
+	if placeholders == placeholdersIgnore {
+		return nil, ""
+	}
+	diffs, err := i18n.ComparePlaceholders(src.Message, dst.Message)
+	if err != nil {
+		if placeholders == placeholdersReject {
+			rejected = make(map[plural.Form]struct{}, len(dst.PluralTemplates))
+			for pluralForm := range dst.PluralTemplates {
+				rejected[pluralForm] = struct{}{}
+			}
+		}
+		return rejected, fmt.Sprintf("%s: %s", dstLangTag, err)
+	}
+	if len(diffs) == 0 {
+		return nil, ""
+	}
+	strs := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		strs = append(strs, d.String())
+		if placeholders == placeholdersReject {
+			if rejected == nil {
+				rejected = make(map[plural.Form]struct{}, len(diffs))
+			}
+			rejected[d.PluralForm] = struct{}{}
+		}
+	}
+	action := "placeholders differ from the source language"
+	if placeholders == placeholdersReject {
+		action = "rejected translation because placeholders differ from the source language"
+	}
+	return rejected, fmt.Sprintf("%s: %s: %s", dstLangTag, action, strings.Join(strs, "; "))
 }
 
 // activeDst returns the active part of the dst and whether dst is a complete translation of src.
@@ -268,6 +486,7 @@ func activeDst(src, dst *i18n.MessageTemplate, pluralRule *plural.Rule) (active
 						ID:          src.ID,
 						Description: src.Description,
 						Hash:        src.Hash,
+						References:  src.References,
 					},
 					PluralTemplates: make(map[plural.Form]*internal.Template),
 				}
@@ -294,9 +513,52 @@ func activeDst(src, dst *i18n.MessageTemplate, pluralRule *plural.Rule) (active
 	return
 }
 
+// Hash prefixes identify the algorithm that computed the hash of a message.
+// Legacy hashes only cover the description and the "other" plural form of a message.
+const (
+	hashPrefix       = "v2-sha256-"
+	legacyHashPrefix = "sha1-"
+)
+
+// hash returns a hash of the content that t is translated from:
+// its description, delimiters and every plural form.
 func hash(t *i18n.MessageTemplate) string {
+	h := sha256.New()
+	writeField := func(name, value string) {
+		// Fields are length prefixed so that different content can not be hashed to the same input.
+		fmt.Fprintf(h, "%s:%d:%s\n", name, len(value), value)
+	}
+	writeField("description", t.Description)
+	writeField("leftDelim", t.LeftDelim)
+	writeField("rightDelim", t.RightDelim)
+	for _, pluralForm := range sortedPluralForms(t.PluralTemplates) {
+		writeField(string(pluralForm), t.PluralTemplates[pluralForm].Src)
+	}
+	return fmt.Sprintf("%s%x", hashPrefix, h.Sum(nil))
+}
+
+// legacyHash returns the hash of t that was computed by earlier versions of goi18n.
+func legacyHash(t *i18n.MessageTemplate) string {
 	h := sha1.New()
 	_, _ = io.WriteString(h, t.Description)
-	_, _ = io.WriteString(h, t.PluralTemplates[plural.Other].Src)
-	return fmt.Sprintf("sha1-%x", h.Sum(nil))
+	if other := t.PluralTemplates[plural.Other]; other != nil {
+		_, _ = io.WriteString(h, other.Src)
+	}
+	return fmt.Sprintf("%s%x", legacyHashPrefix, h.Sum(nil))
+}
+
+// translatedFrom returns true if a translation with hash h was translated from the content of src.
+// Empty hashes (from v1 message files) match any content.
+// Legacy hashes are compared to the legacy hash of src so that translations are kept
+// (and written with the current hash) when goi18n is upgraded, even though legacy
+// hashes do not detect changes to the plural forms other than "other".
+func translatedFrom(h string, src *i18n.MessageTemplate) bool {
+	switch {
+	case h == "":
+		return true
+	case strings.HasPrefix(h, legacyHashPrefix):
+		return h == legacyHash(src)
+	default:
+		return h == src.Hash
+	}
 }
@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
 )
 
@@ -183,7 +187,7 @@ other = "Hola"
 `),
 				"active.es-ES.toml": expectFile(`
 [1HelloMessage]
-hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+hash = "v2-sha256-52bebaefd4352e659dc62f1096277281c5057d74b231b2b08067177a674e2166"
 other = "Hola"
 `),
 			},
@@ -209,13 +213,55 @@ other = "Hola"
 `),
 				"active.es-ES.toml": expectFile(`
 [1HelloMessage]
+hash = "v2-sha256-52bebaefd4352e659dc62f1096277281c5057d74b231b2b08067177a674e2166"
+other = "Hola"
+`),
+				"translate.es-ES.toml": expectFile(`
+[2GoodbyeMessage]
+hash = "v2-sha256-33e9eb6eee9f2de5ed51e7731638bea2ec975742925836ebcfb25b199f3a6724"
+other = "Goodbye"
+`),
+			},
+		},
+		{
+			name:           "references",
+			sourceLanguage: language.AmericanEnglish,
+			inFiles: map[string][]byte{
+				"en-US.toml": []byte(`
+[1HelloMessage]
+other = "Hello"
+references = ["main.go:12"]
+
+[2GoodbyeMessage]
+other = "Goodbye"
+references = ["main.go:20", "ui/goodbye.go:3"]
+`),
+				"es-ES.toml": []byte(`
+[1HelloMessage]
 hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
 other = "Hola"
+`),
+			},
+			outFiles: map[string][]byte{
+				"active.en-US.toml": expectFile(`
+[1HelloMessage]
+other = "Hello"
+references = ["main.go:12"]
+
+[2GoodbyeMessage]
+other = "Goodbye"
+references = ["main.go:20", "ui/goodbye.go:3"]
+`),
+				"active.es-ES.toml": expectFile(`
+[1HelloMessage]
+hash = "v2-sha256-52bebaefd4352e659dc62f1096277281c5057d74b231b2b08067177a674e2166"
+other = "Hola"
 `),
 				"translate.es-ES.toml": expectFile(`
 [2GoodbyeMessage]
-hash = "sha1-b5b29c53e3c71cb9c6581ab053d7758fab8ca24d"
+hash = "v2-sha256-33e9eb6eee9f2de5ed51e7731638bea2ec975742925836ebcfb25b199f3a6724"
 other = "Goodbye"
+references = ["main.go:20", "ui/goodbye.go:3"]
 `),
 			},
 		},
@@ -242,7 +288,7 @@ other = "Goodbye"
 `),
 				"active.es-ES.toml": expectFile(`
 [1HelloMessage]
-hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+hash = "v2-sha256-52bebaefd4352e659dc62f1096277281c5057d74b231b2b08067177a674e2166"
 other = "Hola"
 `),
 			},
@@ -266,8 +312,11 @@ other = "Hola"
 `),
 				"translate.es-ES.toml": expectFile(`
 [1HelloMessage]
-hash = "sha1-94dd9e08c129c785f7f256e82fbe0a30e6d1ae40"
+hash = "v2-sha256-a0c0d053cb22c53d13d5f45f16014a4576dcebb076c96a68eedc247ded3451e5"
 other = "Hi"
+[1HelloMessage.suggestion]
+[1HelloMessage.suggestion.translation]
+other = "Hola"
 `),
 			},
 		},
@@ -295,7 +344,7 @@ other = "{{.Count}} unread emails"
 				"translate.es-ES.toml": expectFile(`
 [UnreadEmails]
 description = "Message that tells the user how many unread emails they have"
-hash = "sha1-5afbc91dfedb9755627655c365eb47a89e541099"
+hash = "v2-sha256-c0afc407652ec76ac2d1d439d02a0e4ed017fa1e7d5c9256f8dee132a36272a7"
 many = "{{.Count}} unread emails"
 one = "{{.Count}} unread email"
 other = "{{.Count}} unread emails"
@@ -304,7 +353,7 @@ other = "{{.Count}} unread emails"
 [UnreadEmails]
 description = "Message that tells the user how many unread emails they have"
 few = "{{.Count}} unread emails"
-hash = "sha1-5afbc91dfedb9755627655c365eb47a89e541099"
+hash = "v2-sha256-c0afc407652ec76ac2d1d439d02a0e4ed017fa1e7d5c9256f8dee132a36272a7"
 many = "{{.Count}} unread emails"
 one = "{{.Count}} unread email"
 other = "{{.Count}} unread emails"
@@ -314,7 +363,7 @@ zero = "{{.Count}} unread emails"
 				"translate.zh-CN.toml": expectFile(`
 [UnreadEmails]
 description = "Message that tells the user how many unread emails they have"
-hash = "sha1-5afbc91dfedb9755627655c365eb47a89e541099"
+hash = "v2-sha256-c0afc407652ec76ac2d1d439d02a0e4ed017fa1e7d5c9256f8dee132a36272a7"
 other = "{{.Count}} unread emails"
 `),
 			},
@@ -369,17 +418,17 @@ other = "{{.Count}} unread emails"
 `),
 				"active.es-ES.toml": expectFile(`
 [1HelloMessage]
-hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+hash = "v2-sha256-52bebaefd4352e659dc62f1096277281c5057d74b231b2b08067177a674e2166"
 other = "Hola"
 `),
 				"active.ar-AR.toml": expectFile(`
 [1HelloMessage]
-hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+hash = "v2-sha256-52bebaefd4352e659dc62f1096277281c5057d74b231b2b08067177a674e2166"
 other = "Hello"
 `),
 				"active.zh-CN.toml": expectFile(`
 [1HelloMessage]
-hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+hash = "v2-sha256-52bebaefd4352e659dc62f1096277281c5057d74b231b2b08067177a674e2166"
 other = "Hello"
 `),
 			},
@@ -434,27 +483,42 @@ other = "{{.Count}} unread emails!"
 				"translate.es-ES.toml": expectFile(`
 [UnreadEmails]
 description = "Message that tells the user how many unread emails they have"
-hash = "sha1-92a24983c5bbc0c42462cdc252dca68ebdb46501"
+hash = "v2-sha256-49d4ce165cf1333a12d035e5bcc7aaf9a6ec4ea9f542d5fa1a9794b41dacd02d"
 many = "{{.Count}} unread emails!"
 one = "{{.Count}} unread emails!"
 other = "{{.Count}} unread emails!"
+[UnreadEmails.suggestion]
+[UnreadEmails.suggestion.translation]
+one = "{{.Count}} unread emails"
+other = "{{.Count}} unread emails"
 `),
 				"translate.ar-AR.toml": expectFile(`
 [UnreadEmails]
 description = "Message that tells the user how many unread emails they have"
 few = "{{.Count}} unread emails!"
-hash = "sha1-92a24983c5bbc0c42462cdc252dca68ebdb46501"
+hash = "v2-sha256-49d4ce165cf1333a12d035e5bcc7aaf9a6ec4ea9f542d5fa1a9794b41dacd02d"
 many = "{{.Count}} unread emails!"
 one = "{{.Count}} unread emails!"
 other = "{{.Count}} unread emails!"
 two = "{{.Count}} unread emails!"
 zero = "{{.Count}} unread emails!"
+[UnreadEmails.suggestion]
+[UnreadEmails.suggestion.translation]
+few = "{{.Count}} unread emails"
+many = "{{.Count}} unread emails"
+one = "{{.Count}} unread emails"
+other = "{{.Count}} unread emails"
+two = "{{.Count}} unread emails"
+zero = "{{.Count}} unread emails"
 `),
 				"translate.zh-CN.toml": expectFile(`
 [UnreadEmails]
 description = "Message that tells the user how many unread emails they have"
-hash = "sha1-92a24983c5bbc0c42462cdc252dca68ebdb46501"
+hash = "v2-sha256-49d4ce165cf1333a12d035e5bcc7aaf9a6ec4ea9f542d5fa1a9794b41dacd02d"
 other = "{{.Count}} unread emails!"
+[UnreadEmails.suggestion]
+[UnreadEmails.suggestion.translation]
+other = "{{.Count}} unread emails"
 `),
 			},
 		},
@@ -516,7 +580,7 @@ other = "{{.Count}} unread emails"
 [UnreadEmails]
 description = "Message that tells the user how many unread emails they have"
 few = "{{.Count}} unread emails"
-hash = "sha1-5afbc91dfedb9755627655c365eb47a89e541099"
+hash = "v2-sha256-2b62ed4303c011b24c6cb01bcd9600b53a3dfcd907ecf4dadb9fe359e3203b30"
 many = "{{.Count}} unread emails"
 one = "{{.Count}} unread emails"
 other = "{{.Count}} unread emails"
@@ -594,3 +658,601 @@ zero = "{{.Count}} unread emails"
 		})
 	}
 }
+
+func TestMergePlaceholders(t *testing.T) {
+	inFiles := map[string][]byte{
+		"active.en.toml": []byte(`
+Hello = "Hello {{.Name}}"
+Bye = "Bye {{.Name}}"
+`),
+		"translate.es.toml": []byte(`
+[Hello]
+hash = "` + testHash("Hello {{.Name}}") + `"
+other = "Hola {{.Nombre}}"
+
+[Bye]
+hash = "` + testHash("Bye {{.Name}}") + `"
+other = "Adiós {{.Name}}"
+`),
+	}
+
+	tests := []struct {
+		placeholders string
+		active       string
+		translate    string
+		warnings     []string
+	}{
+		{
+			placeholders: placeholdersIgnore,
+			active: `
+[Bye]
+hash = "` + testHash("Bye {{.Name}}") + `"
+other = "Adiós {{.Name}}"
+
+[Hello]
+hash = "` + testHash("Hello {{.Name}}") + `"
+other = "Hola {{.Nombre}}"
+`,
+		},
+		{
+			placeholders: placeholdersWarn,
+			active: `
+[Bye]
+hash = "` + testHash("Bye {{.Name}}") + `"
+other = "Adiós {{.Name}}"
+
+[Hello]
+hash = "` + testHash("Hello {{.Name}}") + `"
+other = "Hola {{.Nombre}}"
+`,
+			warnings: []string{
+				`es: placeholders differ from the source language: message "Hello" plural form "other": missing field Name, extra field Nombre`,
+			},
+		},
+		{
+			placeholders: placeholdersReject,
+			active: `
+[Bye]
+hash = "` + testHash("Bye {{.Name}}") + `"
+other = "Adiós {{.Name}}"
+`,
+			translate: `
+[Hello]
+hash = "` + testHash("Hello {{.Name}}") + `"
+other = "Hello {{.Name}}"
+`,
+			warnings: []string{
+				`es: rejected translation because placeholders differ from the source language: message "Hello" plural form "other": missing field Name, extra field Nombre`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.placeholders, func(t *testing.T) {
+			ops, err := merge(inFiles, language.English, "", "toml", "toml", test.placeholders, &translationMemory{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := string(ops.writeFiles["active.es.toml"]); actual != string(expectFile(test.active)) {
+				t.Errorf("expected active file\n%s\ngot\n%s", expectFile(test.active), actual)
+			}
+			if actual := string(ops.writeFiles["translate.es.toml"]); actual != string(expectFile(test.translate)) {
+				t.Errorf("expected translate file\n%s\ngot\n%s", expectFile(test.translate), actual)
+			}
+			if strings.Join(ops.warnings, "\n") != strings.Join(test.warnings, "\n") {
+				t.Errorf("expected warnings\n%s\ngot\n%s", strings.Join(test.warnings, "\n"), strings.Join(ops.warnings, "\n"))
+			}
+		})
+	}
+}
+
+func testHash(other string) string {
+	return hash(i18n.NewMessageTemplate(&i18n.Message{Other: other}))
+}
+
+func TestHash(t *testing.T) {
+	src := &i18n.Message{ID: "Files", One: "1 file", Other: "{{.Count}} files"}
+	h := hash(i18n.NewMessageTemplate(src))
+	if !strings.HasPrefix(h, hashPrefix) {
+		t.Fatalf("expected hash %q to have prefix %q", h, hashPrefix)
+	}
+	changes := []*i18n.Message{
+		{ID: "Files", One: "1 document", Other: "{{.Count}} files"},
+		{ID: "Files", Description: "Files", One: "1 file", Other: "{{.Count}} files"},
+		{ID: "Files", LeftDelim: "<<", RightDelim: ">>", One: "1 file", Other: "{{.Count}} files"},
+		{ID: "Files", Zero: "1 file", Other: "{{.Count}} files"},
+	}
+	for _, m := range changes {
+		if hash(i18n.NewMessageTemplate(m)) == h {
+			t.Errorf("expected hash of %#v to differ from %#v", m, src)
+		}
+	}
+	if actual := hash(i18n.NewMessageTemplate(&i18n.Message{ID: "Renamed", One: "1 file", Other: "{{.Count}} files"})); actual != h {
+		t.Errorf("expected hash to not depend on the id; got %q and %q", actual, h)
+	}
+}
+
+func TestTranslatedFrom(t *testing.T) {
+	src := i18n.NewMessageTemplate(&i18n.Message{ID: "Files", One: "1 file", Other: "{{.Count}} files"})
+	src.Hash = hash(src)
+	changed := i18n.NewMessageTemplate(&i18n.Message{ID: "Files", One: "1 document", Other: "{{.Count}} documents"})
+	tests := []struct {
+		hash     string
+		expected bool
+	}{
+		{hash: "", expected: true},
+		{hash: src.Hash, expected: true},
+		{hash: hash(changed), expected: false},
+		{hash: legacyHash(src), expected: true},
+		{hash: legacyHash(changed), expected: false},
+		{hash: "unknown", expected: false},
+	}
+	for _, test := range tests {
+		if actual := translatedFrom(test.hash, src); actual != test.expected {
+			t.Errorf("translatedFrom(%q) = %t; expected %t", test.hash, actual, test.expected)
+		}
+	}
+}
+
+func TestMergeMemory(t *testing.T) {
+	dir := mustTempDir("TestMergeMemory")
+	defer mustRemoveAll(t, dir)
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(files ...string) {
+		args := []string{"merge", "-outdir", dir, "-memory", filepath.Join(dir, "memory.toml")}
+		for _, file := range files {
+			args = append(args, filepath.Join(dir, file))
+		}
+		if code := testableMain(args); code != 0 {
+			t.Fatalf("expected exit code 0; got %d", code)
+		}
+	}
+
+	write("active.en.toml", `Files = "{{.Count}} files were saved"
+`)
+	write("active.es.toml", `[Files]
+hash = "`+testHash("{{.Count}} files were saved")+`"
+other = "Se guardaron {{.Count}} archivos"
+`)
+	run("active.en.toml", "active.es.toml")
+
+	// The source content changes slightly.
+	write("active.en.toml", `Files = "{{.Count}} files were stored"
+`)
+	run("active.en.toml", "active.es.toml")
+
+	actual, err := os.ReadFile(filepath.Join(dir, "translate.es.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := expectFile(`
+[Files]
+hash = "` + testHash("{{.Count}} files were stored") + `"
+other = "{{.Count}} files were stored"
+[Files.suggestion]
+score = 0.89
+[Files.suggestion.source]
+other = "{{.Count}} files were saved"
+[Files.suggestion.translation]
+other = "Se guardaron {{.Count}} archivos"
+`)
+	if !bytes.Equal(actual, expected) {
+		t.Fatalf("expected translate file\n%s\ngot\n%s", expected, actual)
+	}
+}
+
+func TestMergeRenamedMessage(t *testing.T) {
+	inFiles := map[string][]byte{
+		"active.en.toml": []byte(`Greeting = "Hello"
+`),
+		"active.es.toml": []byte(`[Hello]
+hash = "` + testHash("Hello") + `"
+other = "Hola"
+`),
+	}
+	ops, err := merge(inFiles, language.English, "", "toml", "toml", placeholdersWarn, &translationMemory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := expectFile(`
+[Greeting]
+hash = "` + testHash("Hello") + `"
+other = "Hello"
+[Greeting.suggestion]
+id = "Hello"
+score = 1.0
+[Greeting.suggestion.translation]
+other = "Hola"
+`)
+	if actual := ops.writeFiles["translate.es.toml"]; !bytes.Equal(actual, expected) {
+		t.Fatalf("expected translate file\n%s\ngot\n%s", expected, actual)
+	}
+}
+
+func TestMergeRenamedMessageTranslateFile(t *testing.T) {
+	// Translate files contain the source content of messages that are not translated yet.
+	inFiles := map[string][]byte{
+		"active.en.toml": []byte(`Greeting = "Hello"
+`),
+		"translate.es.toml": []byte(`[Hello]
+hash = "` + testHash("Hello") + `"
+other = "Hello"
+`),
+	}
+	ops, err := merge(inFiles, language.English, "", "toml", "toml", placeholdersWarn, &translationMemory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := expectFile(`
+[Greeting]
+hash = "` + testHash("Hello") + `"
+other = "Hello"
+`)
+	if actual := ops.writeFiles["translate.es.toml"]; !bytes.Equal(actual, expected) {
+		t.Fatalf("expected translate file\n%s\ngot\n%s", expected, actual)
+	}
+}
+
+func TestMergeTranslateFileWithSuggestions(t *testing.T) {
+	inFiles := map[string][]byte{
+		"active.en.toml": []byte(`Greeting = "Hello"
+`),
+		"translate.es.toml": []byte(`[Greeting]
+hash = "` + testHash("Hello") + `"
+other = "Hola"
+[Greeting.suggestion]
+id = "Hello"
+score = 1.0
+[Greeting.suggestion.translation]
+other = "Hola"
+`),
+	}
+	ops, err := merge(inFiles, language.English, "", "toml", "toml", placeholdersWarn, &translationMemory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := expectFile(`
+[Greeting]
+hash = "` + testHash("Hello") + `"
+other = "Hola"
+`)
+	if actual := ops.writeFiles["active.es.toml"]; !bytes.Equal(actual, expected) {
+		t.Fatalf("expected active file\n%s\ngot\n%s", expected, actual)
+	}
+	if _, ok := ops.writeFiles["translate.es.toml"]; ok {
+		t.Fatalf("expected no translate file")
+	}
+}
+
+func TestParseSuggestions(t *testing.T) {
+	content := []byte(`{
+  "Greeting": {"hash": "h", "other": "Hello", "suggestion": {"translation": {"other": "Hola"}}},
+  "group": {"Bye": {"hash": "h", "other": "Bye", "suggestion": {"id": "Goodbye", "translation": {"other": "Adiós"}}}},
+  "suggestion": {"other": "Suggestion"}
+}`)
+	suggestions, err := parseSuggestions(content, "translate.es.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"Greeting":  map[string]interface{}{"translation": map[string]interface{}{"other": "Hola"}},
+		"group.Bye": map[string]interface{}{"id": "Goodbye", "translation": map[string]interface{}{"other": "Adiós"}},
+	}
+	if !reflect.DeepEqual(suggestions, expected) {
+		t.Fatalf("expected %#v; got %#v", expected, suggestions)
+	}
+
+	mf, err := i18n.ParseMessageFileBytes(content, "translate.es.json", unmarshalFuncs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, m := range mf.Messages {
+		ids = append(ids, m.ID)
+	}
+	sort.Strings(ids)
+	if expected := []string{"Greeting", "group.Bye", "suggestion"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected messages %v; got %v", expected, ids)
+	}
+}
+
+func TestTranslationMemory(t *testing.T) {
+	src := func(id, other string) *i18n.MessageTemplate {
+		mt := i18n.NewMessageTemplate(&i18n.Message{ID: id, Other: other})
+		mt.Hash = hash(mt)
+		return mt
+	}
+	translation := i18n.NewMessageTemplate(&i18n.Message{Other: "Hola"})
+	memory := &translationMemory{}
+	memory.add(language.Spanish, src("b", "Hello"), translation)
+	memory.add(language.Spanish, src("a", "Hello"), translation)
+	memory.add(language.Spanish, src("a", "Hello"), i18n.NewMessageTemplate(&i18n.Message{Other: "Buenas"}))
+	if len(memory.Entries) != 2 {
+		t.Fatalf("expected 2 entries; got %d", len(memory.Entries))
+	}
+	h := testHash("Hello")
+	if e := memory.find(language.Spanish, "b", h); e == nil || e.ID != "b" {
+		t.Fatalf("expected entry b; got %+v", e)
+	}
+	if e := memory.find(language.Spanish, "c", h); e == nil || e.ID != "a" || e.Translation["other"] != "Buenas" {
+		t.Fatalf("expected replaced entry a; got %+v", e)
+	}
+	if e := memory.find(language.French, "a", h); e != nil {
+		t.Fatalf("expected no entry; got %+v", e)
+	}
+}
+
+func TestMergeGettext(t *testing.T) {
+	source := `
+[Cats]
+description = "The number of cats"
+one = "{{.Count}} cat"
+other = "{{.Count}} cats"
+
+[Hello]
+other = "Hello {{.Name}}"
+`
+	catsHash := hash(i18n.NewMessageTemplate(&i18n.Message{Description: "The number of cats", One: "{{.Count}} cat", Other: "{{.Count}} cats"}))
+	ops, err := merge(map[string][]byte{
+		"active.en.toml": []byte(source),
+		"active.es.toml": []byte(`
+[Hello]
+hash = "` + testHash("Hi {{.Name}}") + `"
+other = "Hola {{.Name}}"
+`),
+	}, language.English, "", "toml", "po", placeholdersWarn, &translationMemory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := expectFile(`
+msgid ""
+msgstr ""
+"Language: es\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=3; plural=(n == 1 ? 0 : n != 0 && n % 1000000 == 0 ? 1 : 2);\n"
+
+#. The number of cats
+#. hash: ` + catsHash + `
+msgctxt "Cats"
+msgid "{{.Count}} cat"
+msgid_plural "{{.Count}} cats"
+msgstr[0] ""
+msgstr[1] ""
+msgstr[2] ""
+
+#. hash: ` + testHash("Hello {{.Name}}") + `
+#, fuzzy
+msgctxt "Hello"
+msgid "Hello {{.Name}}"
+msgstr "Hola {{.Name}}"
+`)
+	if actual := ops.writeFiles["translate.es.po"]; !bytes.Equal(actual, expected) {
+		t.Fatalf("expected translate file\n%s\ngot\n%s", expected, actual)
+	}
+	expected = expectFile(`
+msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+
+#. The number of cats
+#. hash: ` + catsHash + `
+msgctxt "Cats"
+msgid "{{.Count}} cat"
+msgid_plural "{{.Count}} cats"
+msgstr[0] ""
+msgstr[1] ""
+
+#. hash: ` + testHash("Hello {{.Name}}") + `
+msgctxt "Hello"
+msgid "Hello {{.Name}}"
+msgstr ""
+`)
+	if actual := ops.writeFiles["translate.pot"]; !bytes.Equal(actual, expected) {
+		t.Fatalf("expected POT file\n%s\ngot\n%s", expected, actual)
+	}
+
+	// The translator translates the plural forms and reviews the fuzzy translation.
+	translated := strings.NewReplacer(
+		`msgstr[0] ""`, `msgstr[0] "{{.Count}} gato"`,
+		`msgstr[1] ""`, `msgstr[1] "{{.Count}} de gatos"`,
+		`msgstr[2] ""`, `msgstr[2] "{{.Count}} gatos"`,
+		"#, fuzzy\n", "",
+	).Replace(string(ops.writeFiles["translate.es.po"]))
+	ops, err = merge(map[string][]byte{
+		"active.en.toml":  []byte(source),
+		"translate.es.po": []byte(translated),
+	}, language.English, "", "toml", "po", placeholdersWarn, &translationMemory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = expectFile(`
+[Cats]
+description = "The number of cats"
+hash = "` + catsHash + `"
+many = "{{.Count}} de gatos"
+one = "{{.Count}} gato"
+other = "{{.Count}} gatos"
+
+[Hello]
+hash = "` + testHash("Hello {{.Name}}") + `"
+other = "Hola {{.Name}}"
+`)
+	if actual := ops.writeFiles["active.es.toml"]; !bytes.Equal(actual, expected) {
+		t.Fatalf("expected active file\n%s\ngot\n%s", expected, actual)
+	}
+	if _, ok := ops.writeFiles["translate.es.po"]; ok {
+		t.Errorf("expected no translate file")
+	}
+}
+
+func TestMergeXLIFF(t *testing.T) {
+	source := `
+[Cats]
+description = "The number of cats"
+one = "{{.Count}} cat"
+other = "{{.Count}} cats"
+
+[Hello]
+other = "Hello {{.Name}} & welcome"
+`
+	catsHash := hash(i18n.NewMessageTemplate(&i18n.Message{Description: "The number of cats", One: "{{.Count}} cat", Other: "{{.Count}} cats"}))
+	helloHash := testHash("Hello {{.Name}} & welcome")
+	tests := []struct {
+		format     string
+		translated *strings.Replacer
+	}{
+		{
+			format: xliff12,
+			// Translation tools may replace placeholders with references to placeholders of the source.
+			translated: strings.NewReplacer(
+				`<source><ph id="1">{{.Count}}</ph> cat</source>`, `<source><ph id="1">{{.Count}}</ph> cat</source><target><x id="1"/> gato</target>`,
+				`<trans-unit id="Cats[many]" resname="many">
+          <source><ph id="1">{{.Count}}</ph> cats</source>`, `<trans-unit id="Cats[many]" resname="many">
+          <source><ph id="1">{{.Count}}</ph> cats</source><target><ph id="1">{{.Count}}</ph> de gatos</target>`,
+				`<trans-unit id="Cats[other]" resname="other">
+          <source><ph id="1">{{.Count}}</ph> cats</source>`, `<trans-unit id="Cats[other]" resname="other">
+          <source><ph id="1">{{.Count}}</ph> cats</source><target><ph id="1">{{.Count}}</ph> gatos</target>`,
+				`welcome</source>`, `welcome</source><target state="translated">Hola <g id="2"><ph id="1">{{.Name}}</ph></g> y bienvenido</target>`,
+			),
+		},
+		{
+			format: xliff20,
+			translated: strings.NewReplacer(
+				`<source><ph id="1" dataRef="d1"/> cat</source>`, `<source><ph id="1" dataRef="d1"/> cat</source><target><ph id="1" dataRef="d1"/> gato</target>`,
+				`<unit id="Cats[many]" name="many">
+        <originalData>
+          <data id="d1">{{.Count}}</data>
+        </originalData>
+        <segment id="s1">
+          <source><ph id="1" dataRef="d1"/> cats</source>`, `<unit id="Cats[many]" name="many">
+        <originalData>
+          <data id="d1">{{.Count}}</data>
+        </originalData>
+        <segment id="s1" state="translated">
+          <source><ph id="1" dataRef="d1"/> cats</source><target><ph id="1" dataRef="d1"/> de gatos</target>`,
+				`<unit id="Cats[other]" name="other">
+        <originalData>
+          <data id="d1">{{.Count}}</data>
+        </originalData>
+        <segment id="s1">
+          <source><ph id="1" dataRef="d1"/> cats</source>`, `<unit id="Cats[other]" name="other">
+        <originalData>
+          <data id="d1">{{.Count}}</data>
+        </originalData>
+        <segment id="s1">
+          <source><ph id="1" dataRef="d1"/> cats</source><target><ph id="1"/> gatos</target>`,
+				`welcome</source>`, `welcome</source><target>Hola <pc id="2"><ph id="1" dataRef="d1"/></pc> y bienvenido</target>`,
+			),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			ops, err := merge(map[string][]byte{
+				"active.en.toml": []byte(source),
+				"active.es.toml": []byte(`
+[Hello]
+hash = "` + testHash("Hi {{.Name}}") + `"
+other = "Hola {{.Name}}"
+`),
+			}, language.English, "", "toml", test.format, placeholdersWarn, &translationMemory{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			translate := string(ops.writeFiles["translate.es.xlf"])
+			if test.format == xliff12 {
+				expected := expectFile(`
+<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">
+  <file original="translate.es" source-language="en" target-language="es" datatype="plaintext">
+    <body>
+      <group id="Cats" restype="x-gettext-plurals">
+        <context-group name="go-i18n" purpose="information">
+          <context context-type="x-hash">` + catsHash + `</context>
+        </context-group>
+        <note from="description">The number of cats</note>
+        <trans-unit id="Cats[one]" resname="one">
+          <source><ph id="1">{{.Count}}</ph> cat</source>
+        </trans-unit>
+        <trans-unit id="Cats[many]" resname="many">
+          <source><ph id="1">{{.Count}}</ph> cats</source>
+        </trans-unit>
+        <trans-unit id="Cats[other]" resname="other">
+          <source><ph id="1">{{.Count}}</ph> cats</source>
+        </trans-unit>
+      </group>
+      <trans-unit id="Hello">
+        <source>Hello <ph id="1">{{.Name}}</ph> &amp; welcome</source>
+        <context-group name="go-i18n" purpose="information">
+          <context context-type="x-hash">` + helloHash + `</context>
+        </context-group>
+        <alt-trans origin="goi18n">
+          <target>Hola <ph id="1">{{.Name}}</ph></target>
+        </alt-trans>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`)
+				if translate != string(expected) {
+					t.Fatalf("expected translate file\n%s\ngot\n%s", expected, translate)
+				}
+			}
+
+			translated := test.translated.Replace(translate)
+			if translated == translate {
+				t.Fatalf("unexpected translate file\n%s", translate)
+			}
+			ops, err = merge(map[string][]byte{
+				"active.en.toml":   []byte(source),
+				"translate.es.xlf": []byte(translated),
+			}, language.English, "", "toml", test.format, placeholdersReject, &translationMemory{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := expectFile(`
+[Cats]
+description = "The number of cats"
+hash = "` + catsHash + `"
+many = "{{.Count}} de gatos"
+one = "{{.Count}} gato"
+other = "{{.Count}} gatos"
+
+[Hello]
+hash = "` + helloHash + `"
+other = "Hola {{.Name}} y bienvenido"
+`)
+			if actual := ops.writeFiles["active.es.toml"]; !bytes.Equal(actual, expected) {
+				t.Fatalf("expected active file\n%s\ngot\n%s", expected, actual)
+			}
+			if _, ok := ops.writeFiles["translate.es.xlf"]; ok {
+				t.Errorf("expected no translate file")
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"", "", 1},
+		{"abc", "abc", 1},
+		{"abc", "", 0},
+		{"kitten", "sitting", 0.57},
+		{"1 file", "1 files", 0.86},
+		{"héllo", "hello", 0.8},
+	}
+	for _, test := range tests {
+		if actual := similarity(test.a, test.b); actual != test.expected {
+			t.Errorf("similarity(%q, %q) = %v; expected %v", test.a, test.b, actual, test.expected)
+		}
+	}
+}
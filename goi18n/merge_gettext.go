@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/i18n/gettext"
+	"golang.org/x/text/language"
+)
+
+// writePOFile returns the path and content of the PO file with the messages that need to be translated to langTag.
+// Suggestions are fuzzy translations, which are not merged until a translator reviews them.
+func writePOFile(outdir string, langTag language.Tag, sourceMessageTemplates, messageTemplates map[string]*i18n.MessageTemplate, suggestions map[string]*suggestion) (path string, content []byte, err error) {
+	f := &gettext.File{Language: langTag}
+	for _, id := range sortedIDs(messageTemplates) {
+		var translation *i18n.Message
+		s := suggestions[id]
+		if s != nil {
+			if translation, err = i18n.NewMessage(s.Translation); err != nil {
+				return "", nil, err
+			}
+		}
+		e, err := f.AddMessage(sourceMessageTemplates[id].Message, translation)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal %s strings to po: %s", langTag, err)
+		}
+		if s != nil {
+			e.Flags = append(e.Flags, "fuzzy")
+			if s.Source != nil {
+				e.PreviousID = s.Source["other"]
+				if one := s.Source["one"]; one != "" {
+					e.PreviousID = one
+				}
+			}
+		}
+	}
+	content, err = f.MarshalText()
+	if err != nil {
+		return "", nil, err
+	}
+	return filepath.Join(outdir, fmt.Sprintf("translate.%s.po", langTag)), content, nil
+}
+
+// writePOTFile returns the path and content of the POT file with all source messages,
+// which translators use to start translating to a new language.
+func writePOTFile(outdir string, sourceMessageTemplates map[string]*i18n.MessageTemplate) (path string, content []byte, err error) {
+	f := &gettext.File{Language: language.Und}
+	for _, id := range sortedIDs(sourceMessageTemplates) {
+		if _, err := f.AddMessage(sourceMessageTemplates[id].Message, nil); err != nil {
+			return "", nil, err
+		}
+	}
+	content, err = f.MarshalText()
+	if err != nil {
+		return "", nil, err
+	}
+	return filepath.Join(outdir, "translate.pot"), content, nil
+}
+
+func sortedIDs(messageTemplates map[string]*i18n.MessageTemplate) []string {
+	ids := make([]string, 0, len(messageTemplates))
+	for id := range messageTemplates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
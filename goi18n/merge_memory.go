@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// fuzzyThreshold is the minimum similarity of the source content of a message
+// and a translation memory entry for the entry to be suggested.
+const fuzzyThreshold = 0.8
+
+// translationMemory contains translations and the source content that they were translated from.
+// Merge records every complete translation in it so that previous translations can be suggested
+// after the source content of a message changes.
+type translationMemory struct {
+	Entries []*memoryEntry `json:"entries" toml:"entries" yaml:"entries"`
+
+	// index contains the entries by language, id and hash.
+	// byHash contains the entries by language and hash, sorted by id.
+	// byLanguage contains the entries by language.
+	// They are built on first use.
+	index      map[memoryKey]*memoryEntry
+	byHash     map[memoryKey][]*memoryEntry
+	byLanguage map[string][]*memoryEntry
+}
+
+// memoryKey identifies the entries of a language.
+type memoryKey struct {
+	language, id, hash string
+}
+
+// memoryEntry is a translation of a message.
+type memoryEntry struct {
+	Language    string            `json:"language" toml:"language" yaml:"language"`
+	ID          string            `json:"id" toml:"id" yaml:"id"`
+	Hash        string            `json:"hash" toml:"hash" yaml:"hash"`
+	Source      map[string]string `json:"source" toml:"source" yaml:"source"`
+	Translation map[string]string `json:"translation" toml:"translation" yaml:"translation"`
+
+	// sourceText is the text of Source that is compared with other source content.
+	sourceText []rune
+}
+
+// parseTranslationMemory parses the translation memory file at path.
+// The format of the file is determined by its extension.
+func parseTranslationMemory(content []byte, path string) (*translationMemory, error) {
+	format := strings.TrimPrefix(filepath.Ext(path), ".")
+	unmarshalFunc := unmarshalFuncs[format]
+	if unmarshalFunc == nil {
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	tm := &translationMemory{}
+	if len(content) == 0 {
+		return tm, nil
+	}
+	if err := unmarshalFunc(content, tm); err != nil {
+		return nil, fmt.Errorf("failed to load translation memory %s: %s", path, err)
+	}
+	return tm, nil
+}
+
+// marshal returns the content of the translation memory file at path with entries sorted by language, id and hash.
+func (tm *translationMemory) marshal(path string) ([]byte, error) {
+	sort.Slice(tm.Entries, func(i, j int) bool {
+		a, b := tm.Entries[i], tm.Entries[j]
+		if a.Language != b.Language {
+			return a.Language < b.Language
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Hash < b.Hash
+	})
+	return marshal(tm, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// buildIndex indexes the entries if they are not indexed yet.
+func (tm *translationMemory) buildIndex() {
+	if tm.index != nil {
+		return
+	}
+	tm.index = make(map[memoryKey]*memoryEntry, len(tm.Entries))
+	tm.byHash = make(map[memoryKey][]*memoryEntry)
+	tm.byLanguage = make(map[string][]*memoryEntry)
+	entries := tm.Entries
+	tm.Entries = nil
+	for _, e := range entries {
+		tm.insert(e)
+	}
+}
+
+// insert adds e to the entries and the index, or replaces the entry with the same language, id and hash.
+func (tm *translationMemory) insert(entry *memoryEntry) {
+	entry.sourceText = []rune(formsText(entry.Source))
+	key := memoryKey{language: entry.Language, id: entry.ID, hash: entry.Hash}
+	if e := tm.index[key]; e != nil {
+		*e = *entry
+		return
+	}
+	tm.Entries = append(tm.Entries, entry)
+	tm.index[key] = entry
+	tm.byLanguage[entry.Language] = append(tm.byLanguage[entry.Language], entry)
+	hashKey := memoryKey{language: entry.Language, hash: entry.Hash}
+	byHash := append(tm.byHash[hashKey], entry)
+	sort.Slice(byHash, func(i, j int) bool {
+		return byHash[i].ID < byHash[j].ID
+	})
+	tm.byHash[hashKey] = byHash
+}
+
+// add records the translation of src to langTag.
+func (tm *translationMemory) add(langTag language.Tag, src, translation *i18n.MessageTemplate) {
+	tm.buildIndex()
+	tm.insert(&memoryEntry{
+		Language:    langTag.String(),
+		ID:          src.ID,
+		Hash:        src.Hash,
+		Source:      templateForms(src),
+		Translation: templateForms(translation),
+	})
+}
+
+// find returns the entry of the translation to langTag of the source content with hash.
+// The entry of the message id is preferred, then the entry with the smallest id.
+func (tm *translationMemory) find(langTag language.Tag, id, hash string) *memoryEntry {
+	tm.buildIndex()
+	if e := tm.index[memoryKey{language: langTag.String(), id: id, hash: hash}]; e != nil {
+		return e
+	}
+	if entries := tm.byHash[memoryKey{language: langTag.String(), hash: hash}]; len(entries) > 0 {
+		return entries[0]
+	}
+	return nil
+}
+
+// suggestion is a previous translation that is suggested for a message that needs to be translated.
+type suggestion struct {
+	// ID is the id of the message that was translated if it is not the id of the message.
+	ID string
+
+	// Score is the similarity of the source content that was translated
+	// and the current source content from 0 to 1, or 0 if it is unknown.
+	Score float64
+
+	// Source are the plural forms of the source content that was translated, if known.
+	Source map[string]string
+
+	// Translation are the plural forms of the translation.
+	Translation map[string]string
+}
+
+// value returns the value of the suggestion in a message file.
+func (s *suggestion) value() map[string]interface{} {
+	v := map[string]interface{}{"translation": s.Translation}
+	if s.ID != "" {
+		v["id"] = s.ID
+	}
+	if s.Score > 0 {
+		v["score"] = s.Score
+	}
+	if s.Source != nil {
+		v["source"] = s.Source
+	}
+	return v
+}
+
+// withoutSuggestions returns an unmarshal function that removes the suggestions from the messages
+// that it unmarshals. Suggestions are only read by goi18n and are not part of the messages.
+func withoutSuggestions(unmarshal i18n.UnmarshalFunc) i18n.UnmarshalFunc {
+	return func(data []byte, v interface{}) error {
+		if err := unmarshal(data, v); err != nil {
+			return err
+		}
+		if raw, ok := v.(*interface{}); ok {
+			removeSuggestions(*raw, "", map[string]interface{}{})
+		}
+		return nil
+	}
+}
+
+// parseSuggestions returns the suggestions in the message file at path by message id.
+func parseSuggestions(content []byte, path string) (map[string]interface{}, error) {
+	suggestions := make(map[string]interface{})
+	unmarshal := suggestionUnmarshalFuncs[strings.TrimPrefix(filepath.Ext(path), ".")]
+	if unmarshal == nil {
+		return suggestions, nil
+	}
+	var raw interface{}
+	if err := unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+	}
+	removeSuggestions(raw, "", suggestions)
+	return suggestions, nil
+}
+
+// removeSuggestions removes the suggestions from the messages in v and adds them to suggestions by message id.
+// A suggestion is a map with the key "suggestion" in a message that has a hash.
+func removeSuggestions(v interface{}, id string, suggestions map[string]interface{}) {
+	switch data := v.(type) {
+	case map[string]interface{}:
+		if s, ok := data["suggestion"]; ok && isMap(s) && isString(data["hash"]) {
+			suggestions[id] = s
+			delete(data, "suggestion")
+			return
+		}
+		for k, v := range data {
+			removeSuggestions(v, nestedID(id, k), suggestions)
+		}
+	case map[interface{}]interface{}:
+		if s, ok := data["suggestion"]; ok && isMap(s) && isString(data["hash"]) {
+			suggestions[id] = s
+			delete(data, "suggestion")
+			return
+		}
+		for k, v := range data {
+			if k, ok := k.(string); ok {
+				removeSuggestions(v, nestedID(id, k), suggestions)
+			}
+		}
+	}
+}
+
+// nestedID returns the id of the message with key k in the group with id.
+func nestedID(id, k string) string {
+	if id == "" {
+		return k
+	}
+	return id + "." + k
+}
+
+func isMap(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+		return true
+	}
+	return false
+}
+
+func isString(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+// suggest returns a previous translation to langTag for src, if any.
+// It suggests, in order of preference:
+//   - the stale translation of src, which is translated from different source content
+//   - a translation of another message with the same source content (e.g. because the message id was renamed)
+//   - the translation memory entry whose source content is most similar to src
+func suggest(langTag language.Tag, src *i18n.MessageTemplate, merged *mergedMessageTemplates, memory *translationMemory) *suggestion {
+	srcForms := templateForms(src)
+	if stale := merged.stale[langTag][src.ID]; stale != nil {
+		s := &suggestion{Translation: templateForms(stale)}
+		if e := memory.find(langTag, src.ID, stale.Hash); e != nil {
+			s.Source = e.Source
+			s.Score = similarity(formsText(e.Source), formsText(srcForms))
+		}
+		return s
+	}
+
+	for _, h := range []string{src.Hash, legacyHash(src)} {
+		for _, t := range merged.activeByHash[langTag][h] {
+			if t.ID != src.ID {
+				return &suggestion{ID: t.ID, Score: 1, Translation: templateForms(t)}
+			}
+		}
+	}
+
+	memory.buildIndex()
+	srcText := []rune(formsText(srcForms))
+	var best *suggestion
+	var bestEntry *memoryEntry
+	for _, e := range memory.byLanguage[langTag.String()] {
+		// The distance of texts is at least the difference of their lengths,
+		// so entries whose length differs too much are skipped without comparing them.
+		maxScore := roundScore(1 - float64(abs(len(e.sourceText)-len(srcText)))/float64(max(len(e.sourceText), len(srcText), 1)))
+		if maxScore < fuzzyThreshold || best != nil && maxScore < best.Score {
+			continue
+		}
+		score := runeSimilarity(e.sourceText, srcText)
+		if score < fuzzyThreshold {
+			continue
+		}
+		// Ties are broken by id and hash so that suggestions don't depend on the order of entries.
+		if best != nil && (score < best.Score || score == best.Score && (e.ID > bestEntry.ID || e.ID == bestEntry.ID && e.Hash > bestEntry.Hash)) {
+			continue
+		}
+		best, bestEntry = &suggestion{Score: score, Source: e.Source, Translation: e.Translation}, e
+		if e.ID != src.ID {
+			best.ID = e.ID
+		}
+	}
+	return best
+}
+
+// templateForms returns the content of each plural form of t.
+func templateForms(t *i18n.MessageTemplate) map[string]string {
+	forms := make(map[string]string, len(t.PluralTemplates))
+	for pluralForm, template := range t.PluralTemplates {
+		forms[string(pluralForm)] = template.Src
+	}
+	return forms
+}
+
+// formsText returns the content of the plural forms in CLDR order.
+func formsText(forms map[string]string) string {
+	var texts []string
+	for _, pluralForm := range plural.Forms {
+		if text, ok := forms[string(pluralForm)]; ok {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// similarity returns the Levenshtein similarity of a and b from 0 to 1, rounded to two decimals.
+func similarity(a, b string) float64 {
+	return runeSimilarity([]rune(a), []rune(b))
+}
+
+// runeSimilarity returns the similarity of ar and br like similarity.
+func runeSimilarity(ar, br []rune) float64 {
+	if len(ar) == 0 && len(br) == 0 {
+		return 1
+	}
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	distance := prev[len(br)]
+	return roundScore(1 - float64(distance)/float64(max(len(ar), len(br))))
+}
+
+// roundScore rounds a similarity to two decimals.
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,563 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// Formats of XLIFF translate files.
+const (
+	xliff12 = "xliff12"
+	xliff20 = "xliff20"
+)
+
+// Names that identify the parts of XLIFF files that are written by goi18n.
+const (
+	xliffPluralGroup12 = "x-gettext-plurals"
+	xliffPluralGroup20 = "go-i18n:plural"
+	xliffMetadata      = "go-i18n"
+	xliffHash12        = "x-hash"
+	xliffHash20        = "hash"
+	xliffDescription   = "description"
+	xliffLocation      = "location"
+)
+
+// writeXLIFFFile returns the path and content of the XLIFF file with the messages that need to be translated from sourceLanguageTag to langTag.
+// Messages with plural forms are groups with a unit for each plural form, descriptions and references are notes,
+// hashes are metadata and template actions are placeholders that translators can't change.
+// Suggestions are translation candidates.
+func writeXLIFFFile(outdir, format string, sourceLanguageTag, langTag language.Tag, sourceMessageTemplates, messageTemplates map[string]*i18n.MessageTemplate, suggestions map[string]*suggestion) (path string, content []byte, err error) {
+	w := &xliffWriter{version20: format == xliff20}
+	w.line(0, `<?xml version="1.0" encoding="UTF-8"?>`)
+	if w.version20 {
+		w.line(0, `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" xmlns:mda="urn:oasis:names:tc:xliff:metadata:2.0" xmlns:mtc="urn:oasis:names:tc:xliff:matches:2.0" version="2.0" srcLang="%s" trgLang="%s">`, sourceLanguageTag, langTag)
+		w.line(1, `<file id="translate">`)
+	} else {
+		w.line(0, `<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">`)
+		w.line(1, `<file original="translate.%s" source-language="%s" target-language="%s" datatype="plaintext">`, langTag, sourceLanguageTag, langTag)
+		w.line(2, `<body>`)
+	}
+	for _, id := range sortedIDs(messageTemplates) {
+		w.message(sourceMessageTemplates[id], messageTemplates[id], suggestions[id])
+	}
+	if !w.version20 {
+		w.line(2, `</body>`)
+	}
+	w.line(1, `</file>`)
+	w.line(0, `</xliff>`)
+	return filepath.Join(outdir, fmt.Sprintf("translate.%s.xlf", langTag)), w.buf.Bytes(), nil
+}
+
+// xliffWriter writes the content of an XLIFF file.
+type xliffWriter struct {
+	buf       bytes.Buffer
+	version20 bool
+}
+
+// line writes a line indented by depth.
+func (w *xliffWriter) line(depth int, format string, args ...interface{}) {
+	w.buf.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(&w.buf, format, args...)
+	w.buf.WriteString("\n")
+}
+
+// message writes the units of the plural forms of t that need to be translated.
+func (w *xliffWriter) message(src, t *i18n.MessageTemplate, s *suggestion) {
+	depth := 2
+	if !w.version20 {
+		depth = 3
+	}
+	if len(src.PluralTemplates) == 1 {
+		w.unit(depth, src.ID, "", src, t.PluralTemplates[plural.Other], s, true)
+		return
+	}
+	if w.version20 {
+		w.line(depth, `<group id="%s" type="%s">`, escapeXMLAttr(src.ID), xliffPluralGroup20)
+	} else {
+		w.line(depth, `<group id="%s" restype="%s">`, escapeXMLAttr(src.ID), xliffPluralGroup12)
+	}
+	w.metadata(depth+1, src)
+	for _, pluralForm := range sortedPluralForms(t.PluralTemplates) {
+		id := fmt.Sprintf("%s[%s]", src.ID, pluralForm)
+		w.unit(depth+1, id, pluralForm, src, t.PluralTemplates[pluralForm], s, false)
+	}
+	w.line(depth, `</group>`)
+}
+
+// metadata writes the hash and the notes of src.
+func (w *xliffWriter) metadata(depth int, src *i18n.MessageTemplate) {
+	if w.version20 {
+		w.line(depth, `<mda:metadata>`)
+		w.line(depth+1, `<mda:metaGroup category="%s">`, xliffMetadata)
+		w.line(depth+2, `<mda:meta type="%s">%s</mda:meta>`, xliffHash20, escapeXML(src.Hash))
+		w.line(depth+1, `</mda:metaGroup>`)
+		w.line(depth, `</mda:metadata>`)
+		if src.Description == "" && len(src.References) == 0 {
+			return
+		}
+		w.line(depth, `<notes>`)
+		if src.Description != "" {
+			w.line(depth+1, `<note category="%s">%s</note>`, xliffDescription, escapeXML(src.Description))
+		}
+		for _, ref := range src.References {
+			w.line(depth+1, `<note category="%s">%s</note>`, xliffLocation, escapeXML(ref))
+		}
+		w.line(depth, `</notes>`)
+		return
+	}
+	w.line(depth, `<context-group name="%s" purpose="information">`, xliffMetadata)
+	w.line(depth+1, `<context context-type="%s">%s</context>`, xliffHash12, escapeXML(src.Hash))
+	w.line(depth, `</context-group>`)
+	if src.Description != "" {
+		w.line(depth, `<note from="%s">%s</note>`, xliffDescription, escapeXML(src.Description))
+	}
+	for _, ref := range src.References {
+		w.line(depth, `<note from="%s">%s</note>`, xliffLocation, escapeXML(ref))
+	}
+}
+
+// unit writes the unit of a plural form of src whose source content is t.
+// The metadata of messages without plural forms is written in their unit.
+func (w *xliffWriter) unit(depth int, id string, pluralForm plural.Form, src *i18n.MessageTemplate, t *internal.Template, s *suggestion, withMetadata bool) {
+	attrs := ""
+	if pluralForm != plural.Invalid {
+		if w.version20 {
+			attrs = fmt.Sprintf(` name="%s"`, pluralForm)
+		} else {
+			attrs = fmt.Sprintf(` resname="%s"`, pluralForm)
+		}
+	}
+	if preserveSpace(t.Src) {
+		attrs += ` xml:space="preserve"`
+	}
+	placeholders := &xliffPlaceholders{version20: w.version20}
+	source := placeholders.inline(t.Src, src.LeftDelim, src.RightDelim)
+
+	var candidateSource, candidateTarget string
+	candidatePlaceholders := &xliffPlaceholders{version20: w.version20}
+	if s != nil {
+		form := string(pluralForm)
+		if form == "" {
+			form = string(plural.Other)
+		}
+		if translation, ok := s.Translation[form]; ok {
+			candidateSource = s.Source[form]
+			if candidateSource == "" {
+				candidateSource = s.Source[string(plural.Other)]
+			}
+			candidateSource = candidatePlaceholders.inline(candidateSource, src.LeftDelim, src.RightDelim)
+			candidateTarget = candidatePlaceholders.inline(translation, src.LeftDelim, src.RightDelim)
+		} else {
+			s = nil
+		}
+	}
+
+	if !w.version20 {
+		w.line(depth, `<trans-unit id="%s"%s>`, escapeXMLAttr(id), attrs)
+		w.line(depth+1, `<source>%s</source>`, source)
+		if withMetadata {
+			w.metadata(depth+1, src)
+		}
+		if s != nil {
+			quality := ""
+			if s.Score > 0 {
+				quality = fmt.Sprintf(` match-quality="%.0f"`, math.Round(s.Score*100))
+			}
+			w.line(depth+1, `<alt-trans origin="goi18n"%s>`, quality)
+			if candidateSource != "" {
+				w.line(depth+2, `<source>%s</source>`, candidateSource)
+			}
+			w.line(depth+2, `<target>%s</target>`, candidateTarget)
+			w.line(depth+1, `</alt-trans>`)
+		}
+		w.line(depth, `</trans-unit>`)
+		return
+	}
+
+	w.line(depth, `<unit id="%s"%s>`, escapeXMLAttr(id), attrs)
+	if s != nil {
+		similarity := ""
+		if s.Score > 0 {
+			similarity = fmt.Sprintf(` similarity="%.0f"`, math.Round(s.Score*100))
+		}
+		w.line(depth+1, `<mtc:matches>`)
+		w.line(depth+2, `<mtc:match ref="#s1" origin="goi18n"%s>`, similarity)
+		candidatePlaceholders.originalData(w, depth+3)
+		w.line(depth+3, `<source>%s</source>`, candidateSource)
+		w.line(depth+3, `<target>%s</target>`, candidateTarget)
+		w.line(depth+2, `</mtc:match>`)
+		w.line(depth+1, `</mtc:matches>`)
+	}
+	if withMetadata {
+		w.metadata(depth+1, src)
+	}
+	placeholders.originalData(w, depth+1)
+	w.line(depth+1, `<segment id="s1">`)
+	w.line(depth+2, `<source>%s</source>`, source)
+	w.line(depth+1, `</segment>`)
+	w.line(depth, `</unit>`)
+}
+
+// preserveSpace returns true if the whitespace of s would change if it was normalized.
+func preserveSpace(s string) bool {
+	return strings.TrimSpace(s) != s || strings.Contains(s, "  ") || strings.ContainsAny(s, "\t\n\r")
+}
+
+// xliffPlaceholders numbers the template actions of the content of a unit.
+// Equal template actions are the same placeholder.
+type xliffPlaceholders struct {
+	version20 bool
+	actions   []string
+}
+
+// inline returns the XLIFF inline content of src, where template actions are placeholders.
+func (p *xliffPlaceholders) inline(src, leftDelim, rightDelim string) string {
+	parts, err := internal.TemplateActions(src, leftDelim, rightDelim)
+	if err != nil {
+		// Content that is not a valid template is translated as text.
+		parts = []internal.TemplatePart{{Text: src}}
+	}
+	var buf strings.Builder
+	for _, part := range parts {
+		if part.Action == "" {
+			buf.WriteString(escapeXML(part.Text))
+			continue
+		}
+		id := p.id(part.Action)
+		if p.version20 {
+			fmt.Fprintf(&buf, `<ph id="%d" dataRef="d%d"/>`, id, id)
+		} else {
+			fmt.Fprintf(&buf, `<ph id="%d">%s</ph>`, id, escapeXML(part.Action))
+		}
+	}
+	return buf.String()
+}
+
+func (p *xliffPlaceholders) id(action string) int {
+	for i, a := range p.actions {
+		if a == action {
+			return i + 1
+		}
+	}
+	p.actions = append(p.actions, action)
+	return len(p.actions)
+}
+
+// originalData writes the template actions that the placeholders of XLIFF 2.0 refer to.
+func (p *xliffPlaceholders) originalData(w *xliffWriter, depth int) {
+	if len(p.actions) == 0 {
+		return
+	}
+	w.line(depth, `<originalData>`)
+	for i, action := range p.actions {
+		w.line(depth+1, `<data id="d%d">%s</data>`, i+1, escapeXML(action))
+	}
+	w.line(depth, `</originalData>`)
+}
+
+var (
+	xmlTextReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	xmlAttrReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;", `"`, "&quot;", "\n", "&#xA;", "\t", "&#x9;")
+)
+
+// escapeXML escapes s for the text of an element.
+func escapeXML(s string) string {
+	return xmlTextReplacer.Replace(s)
+}
+
+// escapeXMLAttr escapes s for the value of an attribute.
+func escapeXMLAttr(s string) string {
+	return xmlAttrReplacer.Replace(s)
+}
+
+// xliffContent is inline content of an XLIFF file.
+type xliffContent struct {
+	Inner string `xml:",innerxml"`
+}
+
+type xliffNote struct {
+	From     string `xml:"from,attr"`
+	Category string `xml:"category,attr"`
+	Text     string `xml:",chardata"`
+}
+
+type xliff12File struct {
+	Files []struct {
+		Groups []xliff12Group `xml:"body>group"`
+		Units  []xliff12Unit  `xml:"body>trans-unit"`
+	} `xml:"file"`
+}
+
+type xliff12Group struct {
+	ID       string           `xml:"id,attr"`
+	Contexts []xliff12Context `xml:"context-group>context"`
+	Notes    []xliffNote      `xml:"note"`
+	Units    []xliff12Unit    `xml:"trans-unit"`
+}
+
+type xliff12Unit struct {
+	ID       string           `xml:"id,attr"`
+	Resname  string           `xml:"resname,attr"`
+	Source   xliffContent     `xml:"source"`
+	Target   *xliffContent    `xml:"target"`
+	Contexts []xliff12Context `xml:"context-group>context"`
+	Notes    []xliffNote      `xml:"note"`
+}
+
+type xliff12Context struct {
+	Type string `xml:"context-type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type xliff20File struct {
+	Files []struct {
+		Groups []xliff20Group `xml:"group"`
+		Units  []xliff20Unit  `xml:"unit"`
+	} `xml:"file"`
+}
+
+type xliff20Group struct {
+	ID    string        `xml:"id,attr"`
+	Metas []xliff20Meta `xml:"metadata>metaGroup>meta"`
+	Notes []xliffNote   `xml:"notes>note"`
+	Units []xliff20Unit `xml:"unit"`
+}
+
+type xliff20Unit struct {
+	ID    string        `xml:"id,attr"`
+	Name  string        `xml:"name,attr"`
+	Metas []xliff20Meta `xml:"metadata>metaGroup>meta"`
+	Notes []xliffNote   `xml:"notes>note"`
+	Data  []struct {
+		ID   string `xml:"id,attr"`
+		Text string `xml:",chardata"`
+	} `xml:"originalData>data"`
+	Segments []struct {
+		Source xliffContent  `xml:"source"`
+		Target *xliffContent `xml:"target"`
+	} `xml:"segment"`
+}
+
+type xliff20Meta struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// unmarshalXLIFF is an i18n.UnmarshalFunc for XLIFF 1.2 and 2.0 files.
+// Each unit (or group of units of plural forms) is a message whose content is the target of its units.
+func unmarshalXLIFF(data []byte, v interface{}) error {
+	var root struct {
+		Version string `xml:"version,attr"`
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	var messages map[string]interface{}
+	var err error
+	switch {
+	case root.Version == "1.2":
+		messages, err = xliff12Messages(data)
+	case strings.HasPrefix(root.Version, "2."):
+		messages, err = xliff20Messages(data)
+	default:
+		err = fmt.Errorf("unsupported XLIFF version %q", root.Version)
+	}
+	if err != nil {
+		return err
+	}
+	p, ok := v.(*interface{})
+	if !ok {
+		return fmt.Errorf("unsupported type %T", v)
+	}
+	*p = messages
+	return nil
+}
+
+func xliff12Messages(data []byte) (map[string]interface{}, error) {
+	var f xliff12File
+	if err := xml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	messages := make(map[string]interface{})
+	add := func(id string, contexts []xliff12Context, notes []xliffNote, units []xliff12Unit, pluralForms bool) error {
+		m := map[string]interface{}{}
+		for _, c := range contexts {
+			if c.Type == xliffHash12 {
+				m["hash"] = c.Text
+			}
+		}
+		for _, n := range notes {
+			if n.From == xliffDescription {
+				m["description"] = n.Text
+			}
+		}
+		for _, u := range units {
+			if u.Target == nil {
+				continue
+			}
+			_, placeholders, err := xliffText(u.Source.Inner, nil, nil)
+			if err != nil {
+				return fmt.Errorf("unit %q: %s", u.ID, err)
+			}
+			target, _, err := xliffText(u.Target.Inner, nil, placeholders)
+			if err != nil {
+				return fmt.Errorf("unit %q: %s", u.ID, err)
+			}
+			if target != "" {
+				m[xliffPluralForm(id, u.ID, u.Resname, pluralForms)] = target
+			}
+		}
+		return addXLIFFMessage(messages, id, m)
+	}
+	for _, file := range f.Files {
+		for _, g := range file.Groups {
+			if err := add(g.ID, g.Contexts, g.Notes, g.Units, true); err != nil {
+				return nil, err
+			}
+		}
+		for _, u := range file.Units {
+			if err := add(u.ID, u.Contexts, u.Notes, []xliff12Unit{u}, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return messages, nil
+}
+
+func xliff20Messages(data []byte) (map[string]interface{}, error) {
+	var f xliff20File
+	if err := xml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	messages := make(map[string]interface{})
+	add := func(id string, metas []xliff20Meta, notes []xliffNote, units []xliff20Unit, pluralForms bool) error {
+		m := map[string]interface{}{}
+		for _, meta := range metas {
+			if meta.Type == xliffHash20 {
+				m["hash"] = meta.Text
+			}
+		}
+		for _, n := range notes {
+			if n.Category == xliffDescription {
+				m["description"] = n.Text
+			}
+		}
+		for _, u := range units {
+			originalData := make(map[string]string, len(u.Data))
+			for _, d := range u.Data {
+				originalData[d.ID] = d.Text
+			}
+			var target strings.Builder
+			for _, s := range u.Segments {
+				if s.Target == nil {
+					continue
+				}
+				_, placeholders, err := xliffText(s.Source.Inner, originalData, nil)
+				if err != nil {
+					return fmt.Errorf("unit %q: %s", u.ID, err)
+				}
+				text, _, err := xliffText(s.Target.Inner, originalData, placeholders)
+				if err != nil {
+					return fmt.Errorf("unit %q: %s", u.ID, err)
+				}
+				target.WriteString(text)
+			}
+			if target.Len() > 0 {
+				m[xliffPluralForm(id, u.ID, u.Name, pluralForms)] = target.String()
+			}
+		}
+		return addXLIFFMessage(messages, id, m)
+	}
+	for _, file := range f.Files {
+		for _, g := range file.Groups {
+			if err := add(g.ID, g.Metas, g.Notes, g.Units, true); err != nil {
+				return nil, err
+			}
+		}
+		for _, u := range file.Units {
+			if err := add(u.ID, u.Metas, u.Notes, []xliff20Unit{u}, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return messages, nil
+}
+
+func addXLIFFMessage(messages map[string]interface{}, id string, m map[string]interface{}) error {
+	if _, ok := messages[id]; ok {
+		return fmt.Errorf("duplicate message id %q", id)
+	}
+	messages[id] = m
+	return nil
+}
+
+// xliffPluralForm returns the plural form of the unit with unitID and name in the message with id.
+// Units of plural forms are named after their plural form or have ids of the form "id[form]".
+func xliffPluralForm(id, unitID, name string, pluralForms bool) string {
+	if !pluralForms {
+		return string(plural.Other)
+	}
+	if name != "" {
+		return name
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(unitID, id+"["), "]")
+}
+
+// xliffText returns the content of a message from XLIFF inline content and the template actions of its placeholders by id.
+// Template actions are the content of XLIFF 1.2 placeholders or the originalData of XLIFF 2.0 placeholders.
+// Placeholders that have neither (e.g. <x id="1"/>) are looked up by id in sourcePlaceholders.
+// The markup of other inline elements is removed.
+func xliffText(inner string, originalData, sourcePlaceholders map[string]string) (string, map[string]string, error) {
+	d := xml.NewDecoder(strings.NewReader(inner))
+	var text strings.Builder
+	placeholders := make(map[string]string)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return text.String(), placeholders, nil
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			if t.Name.Local != "ph" && t.Name.Local != "x" {
+				continue
+			}
+			var id, dataRef string
+			for _, attr := range t.Attr {
+				switch attr.Name.Local {
+				case "id":
+					id = attr.Value
+				case "dataRef":
+					dataRef = attr.Value
+				}
+			}
+			var content string
+			if err := d.DecodeElement(&content, &t); err != nil {
+				return "", nil, err
+			}
+			action := content
+			if dataRef != "" {
+				action = originalData[dataRef]
+			}
+			if action == "" {
+				action = sourcePlaceholders[id]
+			}
+			placeholders[id] = action
+			text.WriteString(action)
+		}
+	}
+}
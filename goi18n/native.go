@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal"
+	"github.com/nicksnyder/go-i18n/v2/internal/message"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// nativeMessage is a message in the native format of a mobile platform,
+// where template fields are printf arguments (e.g. "%1$s").
+type nativeMessage struct {
+	// name is the name of the message in the native format, which is not always its id.
+	name        string
+	description string
+
+	// plural is true if the message has plural forms.
+	// Messages without plural forms only have the other form.
+	plural bool
+	forms  map[plural.Form]string
+}
+
+// nativeFormat reads and writes the string resources of a mobile platform.
+type nativeFormat interface {
+	// name returns the name of the message id in the native format.
+	name(id string) string
+
+	// argument returns the printf conversion of the nth argument (starting at 1),
+	// which is an integer if count is true and a string otherwise.
+	argument(n int, count bool) string
+
+	// isFile returns true if path is a native file of the format.
+	isFile(path string) bool
+
+	// write returns the native files with the messages of every language.
+	write(outdir string, sourceLanguageTag language.Tag, messages map[language.Tag][]*nativeMessage) (map[string][]byte, error)
+
+	// read returns the messages by language in the native file at path.
+	read(path string, content []byte, sourceLanguageTag language.Tag) (map[language.Tag][]*nativeMessage, error)
+}
+
+var nativeFormats = map[string]nativeFormat{
+	"android":     androidFormat{},
+	"stringsdict": stringsdictFormat{},
+	"xcstrings":   xcstringsFormat{},
+}
+
+// printfArgs returns the template fields that are the printf arguments of the native messages of src, in order.
+// The first argument of messages with plural forms is the plural count, which is printed by the field
+// PluralCount or Count. The other fields are in the order of their first use in the other form and then
+// the remaining plural forms.
+func printfArgs(src *i18n.MessageTemplate) ([]string, error) {
+	var args []string
+	pluralMessage := isPluralMessage(src)
+	if pluralMessage {
+		args = append(args, message.CountFields[0])
+	}
+	forms := []plural.Form{plural.Other}
+	for _, form := range sortedPluralForms(src.PluralTemplates) {
+		if form != plural.Other {
+			forms = append(forms, form)
+		}
+	}
+	countField := ""
+	for _, form := range forms {
+		t := src.PluralTemplates[form]
+		if t == nil {
+			continue
+		}
+		parts, err := internal.TemplateParts(t.Src, t.LeftDelim, t.RightDelim)
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			field := part.Field
+			if field == "" {
+				continue
+			}
+			if pluralMessage && countField == "" && slices.Contains(message.CountFields, field) {
+				countField = field
+				args[0] = field
+				continue
+			}
+			if argIndex(args, field) < 0 {
+				args = append(args, field)
+			}
+		}
+	}
+	return args, nil
+}
+
+// isPluralMessage returns true if t has plural forms other than the other form.
+func isPluralMessage(t *i18n.MessageTemplate) bool {
+	for form := range t.PluralTemplates {
+		if form != plural.Other {
+			return true
+		}
+	}
+	return false
+}
+
+func argIndex(args []string, field string) int {
+	for i, arg := range args {
+		if arg == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// toPrintf converts the template fields in src to the printf arguments args in the native format f.
+// Percent signs are escaped if the message has arguments, because only then it is formatted by the platform.
+func toPrintf(f nativeFormat, src, leftDelim, rightDelim string, args []string, pluralMessage bool) (string, error) {
+	parts, err := internal.TemplateParts(src, leftDelim, rightDelim)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, part := range parts {
+		if part.Field == "" {
+			if len(args) > 0 {
+				b.WriteString(strings.ReplaceAll(part.Text, "%", "%%"))
+			} else {
+				b.WriteString(part.Text)
+			}
+			continue
+		}
+		i := argIndex(args, part.Field)
+		if i < 0 {
+			return "", fmt.Errorf("field %s is not used by the source message", part.Field)
+		}
+		b.WriteString(f.argument(i+1, pluralMessage && i == 0))
+	}
+	return b.String(), nil
+}
+
+// printfConversion matches the flags, width, precision, length and verb of a printf conversion (e.g. "lld").
+const printfConversion = `[-#+0']*[0-9]*(?:\.[0-9]+)?(?:hh|h|ll|l|q|z|t|j|L)?[@a-zA-Z]`
+
+// printfRegexp matches escaped percent signs and positional printf conversions (e.g. "%1$s").
+var printfRegexp = regexp.MustCompile(`%(?:%|([1-9][0-9]*)\$` + printfConversion + `)`)
+
+// fromPrintf converts the positional printf arguments in s (e.g. "%1$s") to the template fields args.
+// Messages without arguments are not formatted, so s is returned as is.
+// Other percent signs (e.g. "100 % de") are text.
+func fromPrintf(s string, args []string, leftDelim, rightDelim string) (string, error) {
+	if len(args) == 0 {
+		return s, nil
+	}
+	if leftDelim == "" {
+		leftDelim = "{{"
+	}
+	if rightDelim == "" {
+		rightDelim = "}}"
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range printfRegexp.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(s[last:m[0]])
+		last = m[1]
+		if m[2] < 0 {
+			b.WriteString("%")
+			continue
+		}
+		n, err := strconv.Atoi(s[m[2]:m[3]])
+		if err != nil {
+			return "", err
+		}
+		i := n - 1
+		if i >= len(args) {
+			return "", fmt.Errorf("argument %s is not used by the source message", s[m[0]:m[1]])
+		}
+		b.WriteString(leftDelim + "." + args[i] + rightDelim)
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+// exportNative converts the message templates of each language to native messages.
+// Messages that can not be converted are skipped with a warning.
+func exportNative(f nativeFormat, source map[string]*i18n.MessageTemplate, all map[language.Tag]map[string]*i18n.MessageTemplate) (map[language.Tag][]*nativeMessage, []string, error) {
+	names := make(map[string]string, len(source))
+	messages := make(map[language.Tag][]*nativeMessage)
+	var warnings []string
+	for _, id := range sortedIDs(source) {
+		src := source[id]
+		name := f.name(id)
+		if other, ok := names[name]; ok {
+			return nil, nil, fmt.Errorf("messages %q and %q have the same name %q", other, id, name)
+		}
+		names[name] = id
+		args, err := printfArgs(src)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", id, err))
+			continue
+		}
+		for langTag, templates := range all {
+			t := templates[id]
+			if t == nil {
+				continue
+			}
+			m := &nativeMessage{
+				name:        name,
+				description: src.Description,
+				plural:      isPluralMessage(src),
+				forms:       make(map[plural.Form]string, len(t.PluralTemplates)),
+			}
+			for form, pt := range t.PluralTemplates {
+				if m.forms[form], err = toPrintf(f, pt.Src, pt.LeftDelim, pt.RightDelim, args, m.plural); err != nil {
+					break
+				}
+			}
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s: %s", langTag, id, err))
+				continue
+			}
+			messages[langTag] = append(messages[langTag], m)
+		}
+	}
+	return messages, warnings, nil
+}
+
+// importNative converts native messages to the message values of message files by language.
+// Messages that are not in the source messages or that can not be converted are skipped with a warning.
+func importNative(f nativeFormat, source map[string]*i18n.MessageTemplate, messages map[language.Tag][]*nativeMessage) (map[language.Tag]map[string]interface{}, []string) {
+	ids := make(map[string]string, len(source))
+	for id := range source {
+		ids[f.name(id)] = id
+	}
+	values := make(map[language.Tag]map[string]interface{})
+	var warnings []string
+	for langTag, langMessages := range messages {
+		for _, m := range langMessages {
+			id, ok := ids[m.name]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("%s: %s: no source message", langTag, m.name))
+				continue
+			}
+			src := source[id]
+			args, err := printfArgs(src)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s: %s", langTag, id, err))
+				continue
+			}
+			leftDelim, rightDelim := templateDelims(src)
+			v := make(map[string]interface{}, len(m.forms))
+			for form, s := range m.forms {
+				if s == "" {
+					continue
+				}
+				if v[string(form)], err = fromPrintf(s, args, leftDelim, rightDelim); err != nil {
+					break
+				}
+			}
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s: %s", langTag, id, err))
+				continue
+			}
+			if len(v) == 0 {
+				continue
+			}
+			v["hash"] = src.Hash
+			if leftDelim != "" || rightDelim != "" {
+				v["leftDelim"] = leftDelim
+				v["rightDelim"] = rightDelim
+			}
+			if values[langTag] == nil {
+				values[langTag] = make(map[string]interface{})
+			}
+			values[langTag][id] = v
+		}
+	}
+	return values, warnings
+}
+
+// templateDelims returns the delimiters of the templates of mt, which are the same for all plural forms.
+func templateDelims(mt *i18n.MessageTemplate) (leftDelim, rightDelim string) {
+	forms := sortedPluralForms(mt.PluralTemplates)
+	if len(forms) == 0 {
+		return "", ""
+	}
+	t := mt.PluralTemplates[forms[0]]
+	return t.LeftDelim, t.RightDelim
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func usagePrune() {
+	fmt.Fprintf(os.Stderr, `usage: goi18n prune [options] [message files]
+
+Prune compares the messages in the message files with the messages that are used by Go files
+and prints the messages that are no longer used. Messages are used if they are in the
+message file that is written by goi18n extract (see -extracted) or if their ids are
+passed to Localizer methods like LocalizeMessageID (see -referenced).
+
+	goi18n extract -outdir extracted -referenced extracted/referenced.txt
+	goi18n prune -extracted extracted/active.en.toml -referenced extracted/referenced.txt active.*.toml translate.*.toml
+
+Flags:
+
+	-extracted file
+		The message file written by goi18n extract that contains the messages that are used.
+
+	-referenced file
+		The file written by goi18n extract -referenced that contains the message ids
+		that are used without defining a message.
+
+	-sourceLanguage tag
+		The language of the messages in the extracted file (e.g. en, en-US, zh-Hant-CN).
+		Default: en
+
+	-archive directory
+		Move unused messages to archive files (e.g. archive.es.toml) in this directory.
+		Messages are added to existing archive files. Messages in translate files
+		don't replace archived translations.
+
+	-delete
+		Delete unused messages from the message files.
+`)
+}
+
+type pruneCommand struct {
+	messageFiles   []string
+	extracted      string
+	referenced     string
+	sourceLanguage languageTag
+	archive        string
+	delete         bool
+}
+
+func (pc *pruneCommand) name() string {
+	return "prune"
+}
+
+func (pc *pruneCommand) parse(args []string) error {
+	flags := flag.NewFlagSet("prune", flag.ExitOnError)
+	flags.Usage = usagePrune
+
+	flags.StringVar(&pc.extracted, "extracted", "", "")
+	flags.StringVar(&pc.referenced, "referenced", "", "")
+	flags.Var(&pc.sourceLanguage, "sourceLanguage", "en")
+	flags.StringVar(&pc.archive, "archive", "", "")
+	flags.BoolVar(&pc.delete, "delete", false, "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if pc.archive != "" && pc.delete {
+		return fmt.Errorf("-archive and -delete can not be used together")
+	}
+	pc.messageFiles = flags.Args()
+	return nil
+}
+
+func (pc *pruneCommand) execute() error {
+	if pc.extracted == "" {
+		return fmt.Errorf("need an extracted message file")
+	}
+	if len(pc.messageFiles) < 1 {
+		return fmt.Errorf("need at least one message file to prune")
+	}
+	extracted, err := os.ReadFile(pc.extracted)
+	if err != nil {
+		return err
+	}
+	used, err := usedMessageIDs(extracted, pc.extracted)
+	if err != nil {
+		return err
+	}
+	if pc.referenced != "" {
+		referenced, err := os.ReadFile(pc.referenced)
+		if err != nil {
+			return err
+		}
+		for _, id := range strings.Split(string(referenced), "\n") {
+			if id != "" {
+				used[id] = struct{}{}
+			}
+		}
+	}
+	inFiles := make(map[string][]byte)
+	for _, path := range pc.messageFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		inFiles[path] = content
+	}
+	unused, err := unusedMessages(inFiles, used)
+	if err != nil {
+		return err
+	}
+	for _, u := range unused {
+		fmt.Println(u)
+	}
+	if pc.archive == "" && !pc.delete {
+		return nil
+	}
+	ops, err := prune(inFiles, unused, pc.sourceLanguage.Tag(), pc.archive)
+	if err != nil {
+		return err
+	}
+	for path, content := range ops.writeFiles {
+		if err := os.WriteFile(path, content, 0666); err != nil {
+			return err
+		}
+	}
+	for _, path := range ops.deleteFiles {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unusedMessage is a message in a message file that is not used.
+type unusedMessage struct {
+	path      string
+	messageID string
+}
+
+func (u *unusedMessage) String() string {
+	return fmt.Sprintf("%s: %s", u.path, u.messageID)
+}
+
+// usedMessageIDs returns the ids of the messages in the extracted message file at path.
+func usedMessageIDs(extracted []byte, path string) (map[string]struct{}, error) {
+	mf, err := i18n.ParseMessageFileBytes(extracted, path, unmarshalFuncs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+	}
+	used := make(map[string]struct{}, len(mf.Messages))
+	for _, m := range mf.Messages {
+		used[m.ID] = struct{}{}
+	}
+	return used, nil
+}
+
+// unusedMessages returns the messages in messageFiles whose ids are not in used, sorted by path and id.
+func unusedMessages(messageFiles map[string][]byte, used map[string]struct{}) ([]*unusedMessage, error) {
+	var unused []*unusedMessage
+	for path, content := range messageFiles {
+		mf, err := i18n.ParseMessageFileBytes(content, path, unmarshalFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+		}
+		for _, m := range mf.Messages {
+			if _, ok := used[m.ID]; !ok {
+				unused = append(unused, &unusedMessage{path: path, messageID: m.ID})
+			}
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		if unused[i].path != unused[j].path {
+			return unused[i].path < unused[j].path
+		}
+		return unused[i].messageID < unused[j].messageID
+	})
+	return unused, nil
+}
+
+// prune removes the unused messages from messageFiles.
+// If archiveDir is not empty, the unused messages are added to the archive file
+// of their language and format in archiveDir. Messages of translate files are only
+// archived if no other message with the same id is archived because they are not translated.
+// Message files without any remaining messages are deleted.
+func prune(messageFiles map[string][]byte, unused []*unusedMessage, sourceLanguageTag language.Tag, archiveDir string) (*fileSystemOp, error) {
+	unusedByPath := make(map[string]map[string]struct{})
+	for _, u := range unused {
+		if unusedByPath[u.path] == nil {
+			unusedByPath[u.path] = make(map[string]struct{})
+		}
+		unusedByPath[u.path][u.messageID] = struct{}{}
+	}
+	paths := make([]string, 0, len(unusedByPath))
+	for path := range unusedByPath {
+		paths = append(paths, path)
+	}
+	// Translate files are pruned last so that their messages don't replace archived translations.
+	sort.Slice(paths, func(i, j int) bool {
+		if ti, tj := isTranslateFile(paths[i]), isTranslateFile(paths[j]); ti != tj {
+			return tj
+		}
+		return paths[i] < paths[j]
+	})
+	ops := &fileSystemOp{writeFiles: make(map[string][]byte)}
+	// archives are the message files of the archive files by path.
+	archives := make(map[string]*i18n.MessageFile)
+	archived := make(map[string]map[string]*i18n.MessageTemplate)
+	for _, path := range paths {
+		ids := unusedByPath[path]
+		mf, err := i18n.ParseMessageFileBytes(messageFiles[path], path, unmarshalFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+		}
+		translateFile := isTranslateFile(path)
+		kept := make(map[string]*i18n.MessageTemplate)
+		for _, m := range mf.Messages {
+			template := i18n.NewMessageTemplate(m)
+			if template == nil {
+				continue
+			}
+			if _, ok := ids[m.ID]; !ok {
+				kept[m.ID] = template
+				continue
+			}
+			if archiveDir == "" {
+				continue
+			}
+			archivePath := filepath.Join(archiveDir, fmt.Sprintf("archive.%s.%s", mf.Tag, mf.Format))
+			if archived[archivePath] == nil {
+				archived[archivePath], err = readArchive(archivePath)
+				if err != nil {
+					return nil, err
+				}
+				archives[archivePath] = mf
+			}
+			if _, ok := archived[archivePath][m.ID]; ok && translateFile {
+				continue
+			}
+			archived[archivePath][m.ID] = template
+		}
+		if len(kept) == 0 {
+			ops.deleteFiles = append(ops.deleteFiles, path)
+			continue
+		}
+		v := marshalValue(kept, mf.Tag == sourceLanguageTag)
+		if translateFile {
+			// Keep the suggestions that merge added to the messages that need to be translated.
+			suggestions, err := parseSuggestions(messageFiles[path], path)
+			if err != nil {
+				return nil, err
+			}
+			for id, s := range suggestions {
+				if m, ok := v[id].(map[string]interface{}); ok {
+					m["suggestion"] = s
+				}
+			}
+		}
+		content, err := marshal(v, mf.Format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %s", path, err)
+		}
+		ops.writeFiles[path] = content
+	}
+	for archivePath, templates := range archived {
+		mf := archives[archivePath]
+		_, content, err := writeFile(archiveDir, "archive", mf.Tag, mf.Format, templates, mf.Tag == sourceLanguageTag)
+		if err != nil {
+			return nil, err
+		}
+		ops.writeFiles[archivePath] = content
+	}
+	sort.Strings(ops.deleteFiles)
+	return ops, nil
+}
+
+// readArchive returns the message templates in the archive file at path, if it exists.
+func readArchive(path string) (map[string]*i18n.MessageTemplate, error) {
+	templates := make(map[string]*i18n.MessageTemplate)
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return templates, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	mf, err := i18n.ParseMessageFileBytes(content, path, unmarshalFuncs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load message file %s: %s", path, err)
+	}
+	for _, m := range mf.Messages {
+		if template := i18n.NewMessageTemplate(m); template != nil {
+			templates[m.ID] = template
+		}
+	}
+	return templates, nil
+}
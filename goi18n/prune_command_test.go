@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestUnusedMessages(t *testing.T) {
+	used, err := usedMessageIDs([]byte(`
+Hello = "Hello"
+Goodbye = "Goodbye"
+`), "active.en.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unused, err := unusedMessages(map[string][]byte{
+		"active.en.toml": []byte(`
+Hello = "Hello"
+Removed = "Removed"
+`),
+		"active.es.toml": []byte(`
+[Hello]
+hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+other = "Hola"
+
+[Old]
+hash = "sha1-1"
+other = "Viejo"
+
+[Removed]
+hash = "sha1-2"
+other = "Eliminado"
+`),
+	}, used)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual []string
+	for _, u := range unused {
+		actual = append(actual, u.String())
+	}
+	expected := []string{
+		"active.en.toml: Removed",
+		"active.es.toml: Old",
+		"active.es.toml: Removed",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %#v; got %#v", expected, actual)
+	}
+}
+
+func TestPruneCommand(t *testing.T) {
+	files := map[string]string{
+		"extracted.en.toml": `Hello = "Hello"
+`,
+		"active.en.toml": `Hello = "Hello"
+Removed = "Removed"
+`,
+		"active.es.toml": `[Hello]
+hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+other = "Hola"
+
+[Removed]
+hash = "sha1-2"
+other = "Eliminado"
+`,
+		"translate.es.toml": `[Old]
+hash = "sha1-1"
+other = "Old"
+`,
+		"archive.es.toml": `[Older]
+hash = "sha1-0"
+other = "Más viejo"
+`,
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected map[string]string
+	}{
+		{
+			name: "list",
+			expected: map[string]string{
+				"active.en.toml":    files["active.en.toml"],
+				"active.es.toml":    files["active.es.toml"],
+				"translate.es.toml": files["translate.es.toml"],
+				"archive.es.toml":   files["archive.es.toml"],
+			},
+		},
+		{
+			name: "delete",
+			args: []string{"-delete"},
+			expected: map[string]string{
+				"active.en.toml": `Hello = "Hello"
+`,
+				"active.es.toml": `[Hello]
+hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+other = "Hola"
+`,
+				"archive.es.toml": files["archive.es.toml"],
+			},
+		},
+		{
+			name: "archive",
+			args: []string{"-archive", "."},
+			expected: map[string]string{
+				"active.en.toml": `Hello = "Hello"
+`,
+				"active.es.toml": `[Hello]
+hash = "sha1-f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"
+other = "Hola"
+`,
+				"archive.en.toml": `Removed = "Removed"
+`,
+				"archive.es.toml": `[Old]
+hash = "sha1-1"
+other = "Old"
+
+[Older]
+hash = "sha1-0"
+other = "Más viejo"
+
+[Removed]
+hash = "sha1-2"
+other = "Eliminado"
+`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := mustTempDir("TestPruneCommand")
+			defer mustRemoveAll(t, dir)
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Chdir(dir)
+
+			args := append([]string{"prune", "-extracted", "extracted.en.toml"}, test.args...)
+			args = append(args, "active.en.toml", "active.es.toml", "translate.es.toml")
+			if code := testableMain(args); code != 0 {
+				t.Fatalf("expected exit code 0; got %d", code)
+			}
+			for name, content := range test.expected {
+				actual, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(actual, []byte(content)) {
+					t.Errorf("unexpected %s\nexpected:\n%s\ngot:\n%s", name, content, actual)
+				}
+			}
+			if _, ok := test.expected["translate.es.toml"]; !ok {
+				if _, err := os.Stat(filepath.Join(dir, "translate.es.toml")); !os.IsNotExist(err) {
+					t.Errorf("expected translate.es.toml to be deleted; got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneTranslateFile(t *testing.T) {
+	messageFiles := map[string][]byte{
+		"active.es.toml": []byte(`[Removed]
+hash = "sha1-2"
+other = "Eliminado"
+`),
+		"translate.es.toml": []byte(`[Hello]
+hash = "sha1-1"
+other = "Hello"
+[Hello.suggestion]
+id = "Hi"
+score = 1.0
+[Hello.suggestion.translation]
+other = "Hola"
+
+[Removed]
+hash = "sha1-3"
+other = "Removed"
+`),
+	}
+	unused := []*unusedMessage{
+		{path: "active.es.toml", messageID: "Removed"},
+		{path: "translate.es.toml", messageID: "Removed"},
+	}
+	dir := t.TempDir()
+	ops, err := prune(messageFiles, unused, language.English, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"translate.es.toml": `[Hello]
+hash = "sha1-1"
+other = "Hello"
+[Hello.suggestion]
+id = "Hi"
+score = 1.0
+[Hello.suggestion.translation]
+other = "Hola"
+`,
+		filepath.Join(dir, "archive.es.toml"): `[Removed]
+hash = "sha1-2"
+other = "Eliminado"
+`,
+	}
+	for path, content := range expected {
+		if actual := ops.writeFiles[path]; !bytes.Equal(actual, []byte(content)) {
+			t.Errorf("unexpected %s\nexpected:\n%s\ngot:\n%s", path, content, actual)
+		}
+	}
+	if expected := []string{"active.es.toml"}; !reflect.DeepEqual(ops.deleteFiles, expected) {
+		t.Errorf("expected deleted files %v; got %v", expected, ops.deleteFiles)
+	}
+}
+
+func TestPruneReferenced(t *testing.T) {
+	dir := mustTempDir("TestPruneReferenced")
+	defer mustRemoveAll(t, dir)
+	files := map[string]string{
+		"main.go": `package main
+
+import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+var hello = &i18n.Message{ID: "Hello", Other: "Hello"}
+
+func title(l *i18n.Localizer) string {
+	return l.MustLocalizeMessageID("Title")
+}
+`,
+		"active.en.toml": `Hello = "Hello"
+Removed = "Removed"
+Title = "Title"
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+	if err := os.Mkdir("extracted", 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := testableMain([]string{"extract", "-outdir", "extracted", "-referenced", "referenced.txt", "main.go"}); code != 0 {
+		t.Fatalf("expected exit code 0; got %d", code)
+	}
+	referenced, err := os.ReadFile("referenced.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Title\n"; string(referenced) != expected {
+		t.Fatalf("expected referenced %q; got %q", expected, referenced)
+	}
+	if code := testableMain([]string{"prune", "-extracted", "extracted/active.en.toml", "-referenced", "referenced.txt", "-delete", "active.en.toml"}); code != 0 {
+		t.Fatalf("expected exit code 0; got %d", code)
+	}
+	actual, err := os.ReadFile("active.en.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Hello = "Hello"
+Title = "Title"
+`
+	if string(actual) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+func usageStats() {
+	fmt.Fprintf(os.Stderr, `usage: goi18n stats [options] [message files]
+
+Stats reads all messages in the message files and prints translation coverage for each language.
+It accepts the same message files as merge and counts messages the same way that merge does.
+
+	translated
+		Messages with all of the plural forms required by the language.
+
+	partial
+		Messages with some but not all of the plural forms required by the language.
+
+	stale
+		Messages that are only translated from different source content.
+
+	missing
+		Messages that are not translated.
+
+Flags:
+
+	-sourceLanguage tag
+		Translate messages from this language (e.g. en, en-US, zh-Hant-CN)
+		Default: en
+
+	-format format
+		Print coverage in this format.
+		Supported formats: text, json, markdown
+		Default: text
+
+	-placeholders mode
+		What to do with translations whose template fields or functions differ from the source language,
+		like merge does. Plural forms of rejected translations are not counted as translated.
+		Supported modes: ignore, warn, reject
+		Default: warn
+`)
+}
+
+type statsCommand struct {
+	messageFiles   []string
+	sourceLanguage languageTag
+	format         string
+	placeholders   string
+}
+
+func (sc *statsCommand) name() string {
+	return "stats"
+}
+
+func (sc *statsCommand) parse(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	flags.Usage = usageStats
+
+	flags.Var(&sc.sourceLanguage, "sourceLanguage", "en")
+	flags.StringVar(&sc.format, "format", "text", "")
+	flags.StringVar(&sc.placeholders, "placeholders", placeholdersWarn, "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := checkPlaceholdersMode(sc.placeholders); err != nil {
+		return err
+	}
+
+	sc.messageFiles = flags.Args()
+	return nil
+}
+
+func (sc *statsCommand) execute() error {
+	if len(sc.messageFiles) < 1 {
+		return fmt.Errorf("need at least one message file to parse")
+	}
+	inFiles := make(map[string][]byte)
+	for _, path := range sc.messageFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		inFiles[path] = content
+	}
+	s, warnings, err := stats(inFiles, sc.sourceLanguage.Tag(), sc.placeholders)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	content, err := marshalStats(s, sc.format)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(content)
+	return err
+}
+
+// languageStats is the translation coverage of a language.
+type languageStats struct {
+	Language   string `json:"language"`
+	Total      int    `json:"total"`
+	Translated int    `json:"translated"`
+	Partial    int    `json:"partial"`
+	Stale      int    `json:"stale"`
+	Missing    int    `json:"missing"`
+}
+
+// stats returns the translation coverage of every language in messageFiles except the source language, sorted by language,
+// and the warnings about placeholders that merge reports.
+func stats(messageFiles map[string][]byte, sourceLanguageTag language.Tag, placeholders string) ([]*languageStats, []string, error) {
+	merged, err := mergeMessageTemplates(messageFiles, sourceLanguageTag, placeholders)
+	if err != nil {
+		return nil, nil, err
+	}
+	pluralRules := plural.DefaultRules()
+	var s []*languageStats
+	for langTag, messageTemplates := range merged.all {
+		if langTag == sourceLanguageTag {
+			continue
+		}
+		pluralRule := pluralRules.Rule(langTag)
+		if pluralRule == nil {
+			continue
+		}
+		ls := &languageStats{Language: langTag.String()}
+		for id, srcTemplate := range merged.source {
+			ls.Total++
+			active, translate := activeDst(srcTemplate, messageTemplates[id], pluralRule)
+			_, stale := merged.stale[langTag][id]
+			switch {
+			case translate == nil:
+				ls.Translated++
+			case active != nil:
+				ls.Partial++
+			case stale:
+				ls.Stale++
+			default:
+				ls.Missing++
+			}
+		}
+		s = append(s, ls)
+	}
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Language < s[j].Language
+	})
+	return s, merged.warnings, nil
+}
+
+func marshalStats(s []*languageStats, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "text":
+		w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "language\ttotal\ttranslated\tpartial\tstale\tmissing")
+		for _, ls := range s {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", ls.Language, ls.Total, ls.Translated, ls.Partial, ls.Stale, ls.Missing)
+		}
+		if err := w.Flush(); err != nil {
+			return nil, err
+		}
+	case "json":
+		if s == nil {
+			s = []*languageStats{}
+		}
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(s); err != nil {
+			return nil, err
+		}
+	case "markdown":
+		fmt.Fprintln(&buf, "| Language | Total | Translated | Partial | Stale | Missing |")
+		fmt.Fprintln(&buf, "| --- | ---: | ---: | ---: | ---: | ---: |")
+		for _, ls := range s {
+			fmt.Fprintf(&buf, "| %s | %d | %d | %d | %d | %d |\n", ls.Language, ls.Total, ls.Translated, ls.Partial, ls.Stale, ls.Missing)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	return buf.Bytes(), nil
+}
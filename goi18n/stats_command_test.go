@@ -0,0 +1,156 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func TestStats(t *testing.T) {
+	inFiles := map[string][]byte{
+		"active.en.toml": []byte(`
+Translated = "Translated"
+Stale = "Stale"
+Missing = "Missing"
+
+[Partial]
+one = "{{.Count}} partial"
+other = "{{.Count}} partials"
+`),
+		"active.es.toml": []byte(`
+[Translated]
+hash = "` + testHash("Translated") + `"
+other = "Traducido"
+
+[Stale]
+hash = "` + testHash("Old stale") + `"
+other = "Obsoleto"
+
+[Partial]
+hash = "` + hash(i18n.NewMessageTemplate(&i18n.Message{One: "{{.Count}} partial", Other: "{{.Count}} partials"})) + `"
+one = "{{.Count}} parcial"
+`),
+		"active.ja.toml": []byte(``),
+	}
+	actual, warnings, err := stats(inFiles, language.English, placeholdersIgnore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*languageStats{
+		{Language: "es", Total: 4, Translated: 1, Partial: 1, Stale: 1, Missing: 1},
+		{Language: "ja", Total: 4, Missing: 4},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %+v; got %+v", expected, actual)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warnings; got %q", warnings)
+	}
+}
+
+func TestStatsPlaceholders(t *testing.T) {
+	inFiles := map[string][]byte{
+		"active.en.toml": []byte(`Hello = "Hello {{.Name}}"`),
+		"active.es.toml": []byte(`
+[Hello]
+hash = "` + testHash("Hello {{.Name}}") + `"
+other = "Hola {{.Nombre}}"
+`),
+	}
+	tests := []struct {
+		placeholders string
+		expected     []*languageStats
+		warnings     int
+	}{
+		{
+			placeholders: placeholdersIgnore,
+			expected:     []*languageStats{{Language: "es", Total: 1, Translated: 1}},
+		},
+		{
+			placeholders: placeholdersWarn,
+			expected:     []*languageStats{{Language: "es", Total: 1, Translated: 1}},
+			warnings:     1,
+		},
+		{
+			placeholders: placeholdersReject,
+			expected:     []*languageStats{{Language: "es", Total: 1, Missing: 1}},
+			warnings:     1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.placeholders, func(t *testing.T) {
+			actual, warnings, err := stats(inFiles, language.English, test.placeholders)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %+v; got %+v", test.expected, actual)
+			}
+			if len(warnings) != test.warnings {
+				t.Errorf("expected %d warnings; got %q", test.warnings, warnings)
+			}
+		})
+	}
+}
+
+func TestMarshalStats(t *testing.T) {
+	s := []*languageStats{
+		{Language: "es", Total: 4, Translated: 1, Partial: 1, Stale: 1, Missing: 1},
+		{Language: "zh-Hant", Total: 4, Missing: 4},
+	}
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "text",
+			expected: `language  total  translated  partial  stale  missing
+es        4      1           1        1      1
+zh-Hant   4      0           0        0      4
+`,
+		},
+		{
+			format: "markdown",
+			expected: `| Language | Total | Translated | Partial | Stale | Missing |
+| --- | ---: | ---: | ---: | ---: | ---: |
+| es | 4 | 1 | 1 | 1 | 1 |
+| zh-Hant | 4 | 0 | 0 | 0 | 4 |
+`,
+		},
+		{
+			format: "json",
+			expected: `[
+  {
+    "language": "es",
+    "total": 4,
+    "translated": 1,
+    "partial": 1,
+    "stale": 1,
+    "missing": 1
+  },
+  {
+    "language": "zh-Hant",
+    "total": 4,
+    "translated": 0,
+    "partial": 0,
+    "stale": 0,
+    "missing": 4
+  }
+]
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			actual, err := marshalStats(s, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != test.expected {
+				t.Fatalf("\nexpected:\n%s\n\ngot:\n%s", test.expected, actual)
+			}
+		})
+	}
+}
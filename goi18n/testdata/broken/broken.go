@@ -0,0 +1,3 @@
+package broken
+
+var s string = 1
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"github.com/nicksnyder/go-i18n/v2/goi18n/testdata/packages/ids"
+	"github.com/nicksnyder/go-i18n/v2/goi18n/testdata/packages/ui"
+	msg "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type localizedMessage = msg.Message
+
+var messages = []*localizedMessage{
+	// Greets the user on the home page.
+	{
+		ID:    ids.Greeting,
+		Other: ids.GreetingOther,
+	},
+}
+
+func farewell(l *msg.Localizer) string {
+	return l.MustLocalizeMessageID(ids.Farewell)
+}
+
+type notLocalizer struct{}
+
+func (notLocalizer) LocalizeMessageID(id string) string {
+	return id
+}
+
+var _ = notLocalizer{}.LocalizeMessageID("NotAReference")
+
+var save = ui.T("Save", "Save changes", nil)
@@ -0,0 +1,11 @@
+package app
+
+import . "github.com/nicksnyder/go-i18n/v2/i18n"
+
+var emails = map[string]*Message{
+	"emails": {
+		ID:    "Emails",
+		One:   "{{.PluralCount}} email",
+		Other: "{{.PluralCount}} emails",
+	},
+}
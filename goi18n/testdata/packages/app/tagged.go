@@ -0,0 +1,12 @@
+//go:build tagged
+
+package app
+
+import "github.com/nicksnyder/go-i18n/v2/i18n"
+
+var tagged = i18n.LocalizeConfig{
+	DefaultMessage: &i18n.Message{
+		ID:    "Tagged",
+		Other: "Only built with the tagged build tag",
+	},
+}
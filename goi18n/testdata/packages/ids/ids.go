@@ -0,0 +1,12 @@
+// Package ids declares message ids and default messages that are used by other packages.
+package ids
+
+const (
+	Greeting = "Greeting"
+	Farewell = "Farewell"
+)
+
+const greetingOther = "Hello {{.Name}}"
+
+// GreetingOther is the default message of Greeting.
+const GreetingOther = greetingOther + "!"
@@ -0,0 +1,7 @@
+// Package ui wraps the i18n package.
+package ui
+
+// T localizes the message with the id and default text.
+func T(id, defaultText string, data any) string {
+	return defaultText
+}
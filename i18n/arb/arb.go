@@ -0,0 +1,256 @@
+// Package arb converts messages to and from the Application Resource Bundle (ARB) files of Flutter apps.
+//
+// Each message is a resource whose value is an ICU message. Template fields are arguments
+// ({{.Name}} is {Name}) and the plural forms of a message are the options of a plural argument
+// ({Count, plural, one{{Count} cat} other{{Count} cats}}) whose variable is the field PluralCount or Count.
+// The explicit values =0, =1 and =2 of plurals are the plural forms zero, one and two unless
+// the plural also has these keywords, and # in the options of a plural is its variable.
+//
+// Descriptions are the descriptions of the metadata of resources (@Hello).
+// Other template actions, select arguments and ICU quoting are not supported.
+//
+// Load ARB files into a bundle with Unmarshal.
+//
+//	bundle.RegisterUnmarshalFunc("arb", arb.Unmarshal)
+//	bundle.MustLoadMessageFile("active.es.arb")
+package arb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal/message"
+	"golang.org/x/text/language"
+)
+
+// File is an ARB file.
+type File struct {
+	// Locale is the locale of the messages (@@locale), or language.Und if it is unknown.
+	Locale language.Tag
+
+	// Messages are the messages of the file, whose content is Go template syntax.
+	Messages []*i18n.Message
+
+	// plurals contains the ids of messages that are plurals even if they only have the other form.
+	plurals map[string]bool
+}
+
+// AddMessage adds the translation of src to f, or src if translation is nil.
+// Translations of messages with plural forms are plurals, even if the language of f only has the other form.
+// It returns an error if the translation can not be converted to an ICU message.
+func (f *File) AddMessage(src, translation *i18n.Message) error {
+	m := src
+	if translation != nil {
+		m = &i18n.Message{
+			ID:          src.ID,
+			Description: src.Description,
+			LeftDelim:   translation.LeftDelim,
+			RightDelim:  translation.RightDelim,
+			Zero:        translation.Zero,
+			One:         translation.One,
+			Two:         translation.Two,
+			Few:         translation.Few,
+			Many:        translation.Many,
+			Other:       translation.Other,
+		}
+	}
+	pluralMessage := message.IsPlural(message.Forms(src))
+	if _, _, _, err := icuMessage(m, pluralMessage); err != nil {
+		return fmt.Errorf("message %q: %w", m.ID, err)
+	}
+	if pluralMessage {
+		if f.plurals == nil {
+			f.plurals = make(map[string]bool)
+		}
+		f.plurals[m.ID] = true
+	}
+	f.Messages = append(f.Messages, m)
+	return nil
+}
+
+type metadata struct {
+	Description  string                 `json:"description,omitempty"`
+	Placeholders map[string]placeholder `json:"placeholders,omitempty"`
+}
+
+type placeholder struct {
+	Type string `json:"type,omitempty"`
+}
+
+// MarshalJSON returns the ARB file with the messages of f in order.
+// The metadata of each message has its description and its placeholders,
+// where the plural count is an int.
+func (f *File) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	write := func(key string, v interface{}) error {
+		if buf.Len() > 1 {
+			buf.WriteString(",")
+		}
+		k, err := marshal(key)
+		if err != nil {
+			return err
+		}
+		value, err := marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(value)
+		return nil
+	}
+	if f.Locale != language.Und {
+		if err := write("@@locale", locale(f.Locale)); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range f.Messages {
+		value, fields, countField, err := icuMessage(m, f.plurals[m.ID] || message.IsPlural(message.Forms(m)))
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %w", m.ID, err)
+		}
+		if err := write(m.ID, value); err != nil {
+			return nil, err
+		}
+		md := &metadata{Description: m.Description}
+		if len(fields) > 0 {
+			md.Placeholders = make(map[string]placeholder, len(fields))
+			for _, field := range fields {
+				p := placeholder{}
+				if field == countField {
+					p.Type = "int"
+				}
+				md.Placeholders[field] = p
+			}
+		}
+		if md.Description != "" || md.Placeholders != nil {
+			if err := write("@"+m.ID, md); err != nil {
+				return nil, err
+			}
+		}
+	}
+	buf.WriteString("}")
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteString("\n")
+	return indented.Bytes(), nil
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// locale returns the ARB locale of langTag (e.g. pt_BR).
+func locale(langTag language.Tag) string {
+	return strings.ReplaceAll(langTag.String(), "-", "_")
+}
+
+// UnmarshalJSON parses the ARB file data.
+// Messages are sorted by id.
+func (f *File) UnmarshalJSON(data []byte) error {
+	var resources map[string]json.RawMessage
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return err
+	}
+	f.Locale = language.Und
+	if raw, ok := resources["@@locale"]; ok {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("@@locale: %w", err)
+		}
+		langTag, err := language.Parse(strings.ReplaceAll(s, "_", "-"))
+		if err != nil {
+			return fmt.Errorf("@@locale: %w", err)
+		}
+		f.Locale = langTag
+	}
+	ids := make([]string, 0, len(resources))
+	for id := range resources {
+		if !strings.HasPrefix(id, "@") {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	f.Messages = nil
+	f.plurals = nil
+	for _, id := range ids {
+		var value string
+		if err := json.Unmarshal(resources[id], &value); err != nil {
+			return fmt.Errorf("resource %q: %w", id, err)
+		}
+		var md metadata
+		if raw, ok := resources["@"+id]; ok {
+			if err := json.Unmarshal(raw, &md); err != nil {
+				return fmt.Errorf("resource %q: metadata: %w", id, err)
+			}
+		}
+		v := map[string]interface{}{"id": id}
+		if md.Description != "" {
+			v["description"] = md.Description
+		}
+		if value != "" {
+			forms, pluralMessage, err := parseICU(value)
+			if err != nil {
+				return fmt.Errorf("resource %q: %w", id, err)
+			}
+			for form, content := range forms {
+				v[string(form)] = content
+			}
+			if pluralMessage {
+				if f.plurals == nil {
+					f.plurals = make(map[string]bool)
+				}
+				f.plurals[id] = true
+			}
+		}
+		m, err := i18n.NewMessage(v)
+		if err != nil {
+			return fmt.Errorf("resource %q: %w", id, err)
+		}
+		f.Messages = append(f.Messages, m)
+	}
+	return nil
+}
+
+// Unmarshal parses the ARB file data and stores its messages in the value pointed to by v,
+// which must be a *interface{} or a *map[string]interface{}.
+// It is an i18n.UnmarshalFunc.
+func Unmarshal(data []byte, v interface{}) error {
+	f := &File{}
+	if err := f.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	messages := make(map[string]interface{}, len(f.Messages))
+	for _, m := range f.Messages {
+		value := map[string]interface{}{}
+		if m.Description != "" {
+			value["description"] = m.Description
+		}
+		for form, content := range message.Forms(m) {
+			value[string(form)] = content
+		}
+		messages[m.ID] = value
+	}
+	switch p := v.(type) {
+	case *interface{}:
+		*p = messages
+	case *map[string]interface{}:
+		*p = messages
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+	return nil
+}
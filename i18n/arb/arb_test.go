@@ -0,0 +1,232 @@
+package arb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	f := &File{Locale: language.BrazilianPortuguese}
+	for _, m := range []*i18n.Message{
+		{ID: "Hello", Description: "Greets the user", Other: "Olá {{.Name}}"},
+		{ID: "Delims", LeftDelim: "<<", RightDelim: ">>", Other: "<<.Name>> \"diz\" oi"},
+		{ID: "PersonCats", One: "{{.Name}} tem {{.Count}} gato", Other: "{{.Name}} tem {{.Count}} gatos"},
+		{ID: "Cats", Zero: "Nenhum gato", Other: "Alguns gatos"},
+	} {
+		if err := f.AddMessage(m, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, m := range []*i18n.Message{
+		{ID: "Braces", Other: "{literal}"},
+		{ID: "Function", Other: "{{upper .Name}}"},
+	} {
+		if err := f.AddMessage(m, nil); err == nil {
+			t.Errorf("%s: expected error", m.ID)
+		}
+	}
+	actual, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "@@locale": "pt_BR",
+  "Hello": "Olá {Name}",
+  "@Hello": {
+    "description": "Greets the user",
+    "placeholders": {
+      "Name": {}
+    }
+  },
+  "Delims": "{Name} \"diz\" oi",
+  "@Delims": {
+    "placeholders": {
+      "Name": {}
+    }
+  },
+  "PersonCats": "{Count, plural, one{{Name} tem {Count} gato} other{{Name} tem {Count} gatos}}",
+  "@PersonCats": {
+    "placeholders": {
+      "Count": {
+        "type": "int"
+      },
+      "Name": {}
+    }
+  },
+  "Cats": "{count, plural, zero{Nenhum gato} other{Alguns gatos}}",
+  "@Cats": {
+    "placeholders": {
+      "count": {
+        "type": "int"
+      }
+    }
+  }
+}
+`
+	if string(actual) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+
+	parsed := &File{}
+	if err := parsed.UnmarshalJSON(actual); err != nil {
+		t.Fatal(err)
+	}
+	expectedMessages := []*i18n.Message{
+		{ID: "Cats", Zero: "Nenhum gato", Other: "Alguns gatos"},
+		{ID: "Delims", Other: "{{.Name}} \"diz\" oi"},
+		{ID: "Hello", Description: "Greets the user", Other: "Olá {{.Name}}"},
+		{ID: "PersonCats", One: "{{.Name}} tem {{.Count}} gato", Other: "{{.Name}} tem {{.Count}} gatos"},
+	}
+	if parsed.Locale != language.BrazilianPortuguese {
+		t.Errorf("expected locale %s; got %s", language.BrazilianPortuguese, parsed.Locale)
+	}
+	if !reflect.DeepEqual(parsed.Messages, expectedMessages) {
+		t.Errorf("expected %#v; got %#v", expectedMessages, parsed.Messages)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]interface{}
+		err      bool
+	}{
+		{
+			name: "messages",
+			data: `{
+  "@@locale": "es",
+  "@@last_modified": "2024-01-01",
+  "hello": "¡Hola {name}!",
+  "@hello": {"description": "Greeting", "placeholders": {"name": {"type": "String"}}},
+  "cats": "Tienes {count, plural, =0{ningún gato} =1{un gato} other{{count} gatos}}.",
+  "items": "{count,plural, =1{un elemento} one{{count} elemento} other{{count} elementos}}",
+  "empty": ""
+}`,
+			expected: map[string]interface{}{
+				"hello": map[string]interface{}{
+					"description": "Greeting",
+					"other":       "¡Hola {{.name}}!",
+				},
+				"cats": map[string]interface{}{
+					"zero":  "Tienes ningún gato.",
+					"one":   "Tienes un gato.",
+					"other": "Tienes {{.count}} gatos.",
+				},
+				"items": map[string]interface{}{
+					"one":   "{{.count}} elemento",
+					"other": "{{.count}} elementos",
+				},
+				"empty": map[string]interface{}{},
+			},
+		},
+		{
+			name: "number sign",
+			data: `{"cats": "# {n, plural, one{# gato} other{# gatos de {name}}}"}`,
+			expected: map[string]interface{}{
+				"cats": map[string]interface{}{
+					"one":   "# {{.n}} gato",
+					"other": "# {{.n}} gatos de {{.name}}",
+				},
+			},
+		},
+		{
+			name: "select",
+			data: `{"gender": "{gender, select, male{He} other{They}}"}`,
+			err:  true,
+		},
+		{
+			name: "two plurals",
+			data: `{"x": "{a, plural, other{a}} {b, plural, other{b}}"}`,
+			err:  true,
+		},
+		{
+			name: "unknown selector",
+			data: `{"x": "{a, plural, =5{five} other{a}}"}`,
+			err:  true,
+		},
+		{
+			name: "unmatched brace",
+			data: `{"x": "a}"}`,
+			err:  true,
+		},
+		{
+			name: "not a string",
+			data: `{"x": 1}`,
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual interface{}
+			err := Unmarshal([]byte(test.data), &actual)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected error; got %#v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %#v; got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBundle(t *testing.T) {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("arb", Unmarshal)
+	bundle.MustParseMessageFileBytes([]byte(`{
+  "@@locale": "es",
+  "cats": "{count, plural, one{{count} gato} many{{count} de gatos} other{{count} gatos}}"
+}`), "active.es.arb")
+	localizer := i18n.NewLocalizer(bundle, "es")
+	actual := localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "cats", PluralCount: 2, TemplateData: map[string]int{"count": 2}})
+	if expected := "2 gatos"; actual != expected {
+		t.Errorf("expected %q; got %q", expected, actual)
+	}
+}
+
+func TestAddMessageTranslation(t *testing.T) {
+	f := &File{Locale: language.Japanese}
+	src := &i18n.Message{ID: "Cats", Description: "The number of cats", One: "{{.Count}} cat", Other: "{{.Count}} cats"}
+	if err := f.AddMessage(src, &i18n.Message{ID: "Cats", Other: "猫{{.Count}}匹"}); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "@@locale": "ja",
+  "Cats": "{Count, plural, other{猫{Count}匹}}",
+  "@Cats": {
+    "description": "The number of cats",
+    "placeholders": {
+      "Count": {
+        "type": "int"
+      }
+    }
+  }
+}
+`
+	if string(actual) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+}
+
+func TestAddMessageNumberSign(t *testing.T) {
+	f := &File{}
+	if err := f.AddMessage(&i18n.Message{ID: "Rank", Other: "#{{.Rank}}"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.AddMessage(&i18n.Message{ID: "Cats", One: "#{{.Count}} cat", Other: "#{{.Count}} cats"}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,244 @@
+package arb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal"
+	"github.com/nicksnyder/go-i18n/v2/internal/message"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+)
+
+// defaultCountField is the plural count of messages whose plural forms do not print it.
+const defaultCountField = "count"
+
+// explicitForms are the plural forms of the explicit values of ICU plurals (e.g. =0).
+var explicitForms = map[string]plural.Form{
+	"=0": plural.Zero,
+	"=1": plural.One,
+	"=2": plural.Two,
+}
+
+// icuMessage returns the ICU message format of m and the fields that it prints.
+// If pluralMessage is true, the message is a plural whose plural count is the field PluralCount
+// or Count, or count if its plural forms do not print the plural count.
+func icuMessage(m *i18n.Message, pluralMessage bool) (value string, fields []string, countField string, err error) {
+	forms := message.Forms(m)
+	converted := make(map[plural.Form]string, len(forms))
+	for form, content := range forms {
+		parts, err := internal.TemplateParts(content, m.LeftDelim, m.RightDelim)
+		if err != nil {
+			return "", nil, "", err
+		}
+		var b strings.Builder
+		for _, part := range parts {
+			if part.Field == "" {
+				if strings.ContainsAny(part.Text, "{}") {
+					return "", nil, "", fmt.Errorf("braces can not be converted")
+				}
+				if pluralMessage && strings.Contains(part.Text, "#") {
+					// # prints the plural count in the options of a plural.
+					return "", nil, "", fmt.Errorf("# can not be converted")
+				}
+				b.WriteString(part.Text)
+				continue
+			}
+			b.WriteString("{" + part.Field + "}")
+			if !contains(fields, part.Field) {
+				fields = append(fields, part.Field)
+			}
+			if countField == "" && contains(message.CountFields, part.Field) {
+				countField = part.Field
+			}
+		}
+		converted[form] = b.String()
+	}
+	if !pluralMessage {
+		return converted[plural.Other], fields, "", nil
+	}
+	if countField == "" {
+		countField = defaultCountField
+		fields = append(fields, countField)
+	}
+	var b strings.Builder
+	b.WriteString("{" + countField + ", plural,")
+	for _, form := range plural.Forms {
+		if content, ok := converted[form]; ok {
+			fmt.Fprintf(&b, " %s{%s}", form, content)
+		}
+	}
+	b.WriteString("}")
+	return b.String(), fields, countField, nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// icuParser converts an ICU message to a Go template for each plural form.
+// Text around a plural is part of every plural form.
+type icuParser struct {
+	s   string
+	pos int
+
+	// prefix is the content before the plural of the message.
+	prefix string
+	forms  map[plural.Form]string
+
+	// countField is the argument of the plural, which # prints in its options.
+	countField string
+}
+
+// parseICU returns the Go template of each plural form of the ICU message s
+// and whether it has a plural. Messages without a plural only have the other form.
+func parseICU(s string) (forms map[plural.Form]string, pluralMessage bool, err error) {
+	p := &icuParser{s: s}
+	content, err := p.message(true)
+	if err != nil {
+		return nil, false, err
+	}
+	if p.pos < len(p.s) {
+		return nil, false, fmt.Errorf("unexpected } at %d", p.pos)
+	}
+	if p.forms == nil {
+		return map[plural.Form]string{plural.Other: content}, false, nil
+	}
+	for form, branch := range p.forms {
+		p.forms[form] = p.prefix + branch + content
+	}
+	return p.forms, true, nil
+}
+
+// message parses text and arguments until the end of s or an unmatched }.
+// The top level message may contain one plural.
+func (p *icuParser) message(top bool) (string, error) {
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '}' {
+			break
+		}
+		if c == '#' && !top {
+			b.WriteString("{{." + p.countField + "}}")
+			p.pos++
+			continue
+		}
+		if c != '{' {
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+		p.pos++
+		name := p.ident()
+		if name == "" {
+			return "", fmt.Errorf("expected argument name at %d", p.pos)
+		}
+		p.skipSpace()
+		if p.consume('}') {
+			b.WriteString("{{." + name + "}}")
+			continue
+		}
+		if !p.consume(',') {
+			return "", fmt.Errorf("expected , or } at %d", p.pos)
+		}
+		p.skipSpace()
+		if argType := p.ident(); argType != "plural" {
+			return "", fmt.Errorf("argument %s: %s arguments are not supported", name, argType)
+		}
+		if !top || p.forms != nil {
+			return "", fmt.Errorf("argument %s: only one plural is supported", name)
+		}
+		p.skipSpace()
+		if !p.consume(',') {
+			return "", fmt.Errorf("expected , at %d", p.pos)
+		}
+		p.countField = name
+		if err := p.plural(); err != nil {
+			return "", fmt.Errorf("argument %s: %w", name, err)
+		}
+		p.prefix = b.String()
+		b.Reset()
+	}
+	return b.String(), nil
+}
+
+// plural parses the options of a plural argument and its closing }.
+func (p *icuParser) plural() error {
+	p.forms = make(map[plural.Form]string)
+	explicit := make(map[plural.Form]string)
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			break
+		}
+		start := p.pos
+		p.consume('=')
+		selector := p.s[start:p.pos] + p.ident()
+		p.skipSpace()
+		if !p.consume('{') {
+			return fmt.Errorf("expected { after %s", selector)
+		}
+		branch, err := p.message(false)
+		if err != nil {
+			return err
+		}
+		if !p.consume('}') {
+			return fmt.Errorf("missing } after %s", selector)
+		}
+		if form, ok := explicitForms[selector]; ok {
+			explicit[form] = branch
+			continue
+		}
+		if !contains(formNames, selector) {
+			return fmt.Errorf("unsupported plural selector %s", selector)
+		}
+		p.forms[plural.Form(selector)] = branch
+	}
+	// Explicit values are only used for plural forms without a keyword.
+	for form, branch := range explicit {
+		if _, ok := p.forms[form]; !ok {
+			p.forms[form] = branch
+		}
+	}
+	return nil
+}
+
+var formNames = func() []string {
+	names := make([]string, len(plural.Forms))
+	for i, form := range plural.Forms {
+		names[i] = string(form)
+	}
+	return names
+}()
+
+func (p *icuParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *icuParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *icuParser) consume(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
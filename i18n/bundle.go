@@ -24,6 +24,8 @@ type Bundle struct {
 	pluralRules      plural.Rules
 	tags             []language.Tag
 	matcher          language.Matcher
+
+	placeholderMismatchFunc PlaceholderMismatchFunc
 }
 
 // artTag is the language tag used for artificial languages
@@ -49,6 +51,13 @@ func (b *Bundle) RegisterUnmarshalFunc(format string, unmarshalFunc UnmarshalFun
 	b.unmarshalFuncs[format] = unmarshalFunc
 }
 
+// RegisterPlaceholderMismatchFunc registers a PlaceholderMismatchFunc that AddMessages calls
+// when the placeholders of translations differ from the default language messages in the bundle.
+// Messages for the default language must be added first for them to be compared.
+func (b *Bundle) RegisterPlaceholderMismatchFunc(f PlaceholderMismatchFunc) {
+	b.placeholderMismatchFunc = f
+}
+
 // LoadMessageFile loads the bytes from path
 // and then calls ParseMessageFileBytes.
 func (b *Bundle) LoadMessageFile(path string) (*MessageFile, error) {
@@ -83,6 +92,38 @@ func (b *Bundle) ParseMessageFileBytes(buf []byte, path string) (*MessageFile, e
 	return messageFile, nil
 }
 
+// RegisterPluralRule registers the plural rule of a language, for example one that the bundle has no plural rule for.
+// The rule is defined by the CLDR conditions of its plural forms (https://cldr.unicode.org/index/cldr-spec/plural-rules),
+// whose keys are the plural forms of messages:
+//
+//	err := bundle.RegisterPluralRule(language.MustParse("xx"), map[string]string{
+//		"one": "n % 10 = 1 and n % 100 != 11",
+//		"few": "n % 10 = 2..4 and n % 100 != 12..14",
+//	})
+//
+// The "other" form is used if no other condition is true, so its condition is optional.
+// The rule replaces the plural rule of the language and of the languages that inherit it (e.g. xx-YY)
+// unless they have their own plural rule.
+func (b *Bundle) RegisterPluralRule(tag language.Tag, conditions map[string]string) error {
+	forms := make(map[plural.Form]string, len(conditions))
+	for form, condition := range conditions {
+		forms[plural.Form(form)] = condition
+	}
+	rule, err := plural.NewRule(forms)
+	if err != nil {
+		return fmt.Errorf("invalid plural rule for %s: %s", tag, err)
+	}
+	b.pluralRules[tag] = rule
+	return nil
+}
+
+// MustRegisterPluralRule is similar to RegisterPluralRule except it panics if an error happens.
+func (b *Bundle) MustRegisterPluralRule(tag language.Tag, conditions map[string]string) {
+	if err := b.RegisterPluralRule(tag, conditions); err != nil {
+		panic(err)
+	}
+}
+
 // MustParseMessageFileBytes is similar to ParseMessageFileBytes
 // except it panics if an error happens.
 func (b *Bundle) MustParseMessageFileBytes(buf []byte, path string) {
@@ -98,6 +139,11 @@ func (b *Bundle) AddMessages(tag language.Tag, messages ...*Message) error {
 	if pluralRule == nil {
 		return fmt.Errorf("no plural rule registered for %s", tag)
 	}
+	if b.placeholderMismatchFunc != nil && tag != b.defaultLanguage {
+		if err := b.checkPlaceholders(tag, messages); err != nil {
+			return err
+		}
+	}
 	if b.messageTemplates == nil {
 		b.messageTemplates = map[language.Tag]map[string]*MessageTemplate{}
 	}
@@ -111,6 +157,25 @@ func (b *Bundle) AddMessages(tag language.Tag, messages ...*Message) error {
 	return nil
 }
 
+func (b *Bundle) checkPlaceholders(tag language.Tag, messages []*Message) error {
+	var diffs []*PlaceholderDiff
+	for _, m := range messages {
+		src := b.getMessageTemplate(b.defaultLanguage, m.ID)
+		if src == nil {
+			continue
+		}
+		d, err := ComparePlaceholders(src.Message, m)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, d...)
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	return b.placeholderMismatchFunc(tag, diffs)
+}
+
 // MustAddMessages is similar to AddMessages except it panics if an error happens.
 func (b *Bundle) MustAddMessages(tag language.Tag, messages ...*Message) {
 	if err := b.AddMessages(tag, messages...); err != nil {
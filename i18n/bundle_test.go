@@ -100,6 +100,49 @@ hello = "`+expected+`"
 	}
 }
 
+func TestRegisterPluralRule(t *testing.T) {
+	tag := language.MustParse("tlh")
+	bundle := NewBundle(language.English)
+	if err := bundle.AddMessages(tag, &Message{ID: "cats", Other: "{{.PluralCount}} cats"}); err == nil {
+		t.Fatal("expected error for language without plural rule")
+	}
+	bundle.MustRegisterPluralRule(tag, map[string]string{
+		"one": "n % 10 = 1 and n % 100 != 11",
+		"few": "n % 10 = 2..4 and n % 100 != 12..14 @integer 2~4, 22~24",
+	})
+	bundle.MustAddMessages(tag, &Message{
+		ID:    "cats",
+		One:   "{{.PluralCount}} cat",
+		Few:   "{{.PluralCount}} cats (few)",
+		Other: "{{.PluralCount}} cats",
+	})
+	localizer := NewLocalizer(bundle, "tlh-x-test")
+	tests := map[interface{}]string{
+		1:     "1 cat",
+		21:    "21 cat",
+		11:    "11 cats",
+		3:     "3 cats (few)",
+		13:    "13 cats",
+		"1.5": "1.5 cats",
+	}
+	for count, expected := range tests {
+		localized, err := localizer.Localize(&LocalizeConfig{MessageID: "cats", PluralCount: count})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if localized != expected {
+			t.Errorf("expected %q; got %q", expected, localized)
+		}
+	}
+
+	if err := bundle.RegisterPluralRule(tag, map[string]string{"single": "n = 1"}); err == nil {
+		t.Error("expected error for invalid plural form")
+	}
+	if err := bundle.RegisterPluralRule(tag, map[string]string{"one": "n == 1"}); err == nil {
+		t.Error("expected error for invalid condition")
+	}
+}
+
 func TestJSON(t *testing.T) {
 	bundle := NewBundle(language.English)
 	bundle.MustParseMessageFileBytes([]byte(`{
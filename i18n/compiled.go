@@ -0,0 +1,242 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+// Compiled messages are a binary catalog of the messages of a bundle, which loads faster
+// than message files because it does not need to be unmarshaled.
+//
+// The catalog starts with compiledMagic and the version of its format, followed by a string table
+// and an index of the messages of each language. All numbers are unsigned varints and strings
+// are indexes into the string table, whose first string is empty.
+//
+//	magic version
+//	stringCount (length bytes)...
+//	languageCount (tag messageCount (id leftDelim rightDelim forms content...)...)...
+//
+// The forms of a message are a bit set of its plural forms in the order of compiledForms,
+// followed by the content of each form in the set.
+const (
+	compiledMagic   = "goi18n"
+	compiledVersion = 1
+)
+
+// compiledForms are the plural forms of compiled messages, in the order of their bits.
+var compiledForms = []func(m *Message) *string{
+	func(m *Message) *string { return &m.Zero },
+	func(m *Message) *string { return &m.One },
+	func(m *Message) *string { return &m.Two },
+	func(m *Message) *string { return &m.Few },
+	func(m *Message) *string { return &m.Many },
+	func(m *Message) *string { return &m.Other },
+}
+
+var errInvalidCompiled = errors.New("invalid compiled messages")
+
+// WriteCompiled writes the messages of every language in the bundle to w as compiled messages,
+// which LoadCompiled loads into another bundle.
+// Descriptions and hashes are not written because they are not used to localize messages.
+func (b *Bundle) WriteCompiled(w io.Writer) error {
+	var strs []string
+	indexes := map[string]uint64{}
+	index := func(s string) uint64 {
+		i, ok := indexes[s]
+		if !ok {
+			i = uint64(len(strs))
+			indexes[s] = i
+			strs = append(strs, s)
+		}
+		return i
+	}
+	index("")
+
+	var messages []byte
+	tags := make([]language.Tag, 0, len(b.messageTemplates))
+	for _, tag := range b.tags {
+		if len(b.messageTemplates[tag]) > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	messages = binary.AppendUvarint(messages, uint64(len(tags)))
+	for _, tag := range tags {
+		templates := b.messageTemplates[tag]
+		ids := make([]string, 0, len(templates))
+		for id := range templates {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		messages = binary.AppendUvarint(messages, index(tag.String()))
+		messages = binary.AppendUvarint(messages, uint64(len(ids)))
+		for _, id := range ids {
+			m := templates[id].Message
+			messages = binary.AppendUvarint(messages, index(m.ID))
+			messages = binary.AppendUvarint(messages, index(m.LeftDelim))
+			messages = binary.AppendUvarint(messages, index(m.RightDelim))
+			var forms uint64
+			for i, form := range compiledForms {
+				if *form(m) != "" {
+					forms |= 1 << i
+				}
+			}
+			messages = binary.AppendUvarint(messages, forms)
+			for _, form := range compiledForms {
+				if content := *form(m); content != "" {
+					messages = binary.AppendUvarint(messages, index(content))
+				}
+			}
+		}
+	}
+
+	buf := []byte(compiledMagic)
+	buf = binary.AppendUvarint(buf, compiledVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(strs)))
+	for _, s := range strs {
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+	buf = append(buf, messages...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// LoadCompiled reads compiled messages that WriteCompiled wrote from r
+// and adds the messages of every language to the bundle.
+//
+// Compiled messages can be embedded into a program:
+//
+//	//go:embed messages.i18n
+//	var compiledMessages []byte
+//
+//	err := bundle.LoadCompiled(bytes.NewReader(compiledMessages))
+func (b *Bundle) LoadCompiled(r io.Reader) error {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(buf, []byte(compiledMagic)) {
+		return errInvalidCompiled
+	}
+	// The strings of all messages share the memory of data.
+	data := string(buf[len(compiledMagic):])
+	d := &compiledDecoder{data: data}
+	if version := d.uvarint(); d.err == nil && version != compiledVersion {
+		return fmt.Errorf("unsupported version %d of compiled messages", version)
+	}
+	stringCount := d.uvarint()
+	if d.err == nil && stringCount > uint64(len(d.data)) {
+		d.err = errInvalidCompiled
+	}
+	if d.err != nil {
+		return d.err
+	}
+	strs := make([]string, stringCount)
+	for i := range strs {
+		n := d.uvarint()
+		if d.err == nil && n > uint64(len(d.data)) {
+			d.err = errInvalidCompiled
+		}
+		if d.err != nil {
+			return d.err
+		}
+		strs[i], d.data = d.data[:n], d.data[n:]
+	}
+	str := func() string {
+		i := d.uvarint()
+		if d.err == nil && i >= uint64(len(strs)) {
+			d.err = errInvalidCompiled
+		}
+		if d.err != nil {
+			return ""
+		}
+		return strs[i]
+	}
+
+	// Messages are only added to the bundle if all of them are decoded.
+	var tags []language.Tag
+	var tagMessages [][]*Message
+	languageCount := d.uvarint()
+	for i := uint64(0); i < languageCount && d.err == nil; i++ {
+		tag, err := language.Parse(str())
+		if d.err != nil {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		n := d.uvarint()
+		if d.err == nil && n > uint64(len(d.data)) {
+			d.err = errInvalidCompiled
+		}
+		if d.err != nil {
+			break
+		}
+		messages := make([]Message, n)
+		ptrs := make([]*Message, n)
+		for j := range messages {
+			m := &messages[j]
+			m.ID = str()
+			m.LeftDelim = str()
+			m.RightDelim = str()
+			forms := d.uvarint()
+			for k, form := range compiledForms {
+				if forms&(1<<k) != 0 {
+					*form(m) = str()
+				}
+			}
+			ptrs[j] = m
+		}
+		tags = append(tags, tag)
+		tagMessages = append(tagMessages, ptrs)
+	}
+	if d.err == nil && len(d.data) > 0 {
+		d.err = errInvalidCompiled
+	}
+	if d.err != nil {
+		return d.err
+	}
+	for i, tag := range tags {
+		if err := b.AddMessages(tag, tagMessages[i]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MustLoadCompiled is similar to LoadCompiled except it panics if an error happens.
+func (b *Bundle) MustLoadCompiled(r io.Reader) {
+	if err := b.LoadCompiled(r); err != nil {
+		panic(err)
+	}
+}
+
+// compiledDecoder decodes the numbers of compiled messages.
+// Decoding stops at the first error.
+type compiledDecoder struct {
+	data string
+	err  error
+}
+
+func (d *compiledDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	var v uint64
+	for i := 0; i < len(d.data) && i < binary.MaxVarintLen64; i++ {
+		c := d.data[i]
+		v |= uint64(c&0x7f) << (7 * i)
+		if c < 0x80 {
+			d.data = d.data[i+1:]
+			return v
+		}
+	}
+	d.err = errInvalidCompiled
+	return 0
+}
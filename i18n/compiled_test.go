@@ -0,0 +1,118 @@
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/text/language"
+)
+
+func TestCompiled(t *testing.T) {
+	bundle := NewBundle(language.English)
+	bundle.MustAddMessages(language.English, simpleMessage, detailMessage, everythingMessage)
+	bundle.MustAddMessages(language.Spanish, &Message{ID: "simple", Other: "traducción simple"})
+	var buf bytes.Buffer
+	if err := bundle.WriteCompiled(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewBundle(language.English)
+	if err := loaded.LoadCompiled(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []language.Tag{language.English, language.Spanish}; !reflect.DeepEqual(loaded.LanguageTags(), expected) {
+		t.Errorf("expected tags %v; got %v", expected, loaded.LanguageTags())
+	}
+	expected := &Message{
+		ID:         "everything",
+		LeftDelim:  "<<",
+		RightDelim: ">>",
+		Zero:       "zero translation",
+		One:        "one translation",
+		Two:        "two translation",
+		Few:        "few translation",
+		Many:       "many translation",
+		Other:      "other translation",
+	}
+	if actual := loaded.getMessageTemplate(language.English, "everything").Message; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v; got %#v", expected, actual)
+	}
+	localized := NewLocalizer(loaded, "es").MustLocalize(&LocalizeConfig{MessageID: "simple"})
+	if localized != "traducción simple" {
+		t.Errorf("expected %q; got %q", "traducción simple", localized)
+	}
+
+	// Compiled messages are deterministic.
+	var again bytes.Buffer
+	if err := loaded.WriteCompiled(&again); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Errorf("expected the same compiled messages after loading them")
+	}
+}
+
+func TestLoadCompiledInvalid(t *testing.T) {
+	bundle := NewBundle(language.English)
+	bundle.MustAddMessages(language.English, everythingMessage)
+	var buf bytes.Buffer
+	if err := bundle.WriteCompiled(&buf); err != nil {
+		t.Fatal(err)
+	}
+	compiled := buf.Bytes()
+	tests := map[string][]byte{
+		"empty":     nil,
+		"magic":     []byte("toml = true"),
+		"version":   append([]byte(compiledMagic), 2),
+		"truncated": compiled[:len(compiled)-1],
+		"trailing":  append(append([]byte{}, compiled...), 0),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			loaded := NewBundle(language.English)
+			if err := loaded.LoadCompiled(bytes.NewReader(data)); err == nil {
+				t.Fatal("expected error")
+			}
+			if len(loaded.messageTemplates) != 0 {
+				t.Errorf("expected no messages; got %v", loaded.messageTemplates)
+			}
+		})
+	}
+}
+
+func benchmarkMessageFile(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "[Message%d]\ndescription = \"Message %d\"\none = \"{{.Name}} has {{.Count}} cat\"\nother = \"{{.Name}} has {{.Count}} cats\"\n\n", i, i)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkParseMessageFileBytes(b *testing.B) {
+	messageFile := benchmarkMessageFile(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bundle := NewBundle(language.English)
+		bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+		bundle.MustParseMessageFileBytes(messageFile, "active.en.toml")
+	}
+}
+
+func BenchmarkLoadCompiled(b *testing.B) {
+	bundle := NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+	bundle.MustParseMessageFileBytes(benchmarkMessageFile(1000), "active.en.toml")
+	var buf bytes.Buffer
+	if err := bundle.WriteCompiled(&buf); err != nil {
+		b.Fatal(err)
+	}
+	compiled := buf.Bytes()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewBundle(language.English).MustLoadCompiled(bytes.NewReader(compiled))
+	}
+}
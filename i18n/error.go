@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// Error is an error that can be localized after it has been returned.
+// It allows code that does not know the user's language to return errors
+// that are localized later by code that does (e.g. an HTTP handler).
+//
+//	return &i18n.Error{
+//	    DefaultMessage: &i18n.Message{
+//	        ID:    "FileNotFound",
+//	        Other: "{{.Name}} not found",
+//	    },
+//	    TemplateData: map[string]string{"Name": name},
+//	}
+type Error struct {
+	// MessageID is the id of the message to lookup.
+	// It may be empty if DefaultMessage is set, otherwise it must be the id of DefaultMessage.
+	MessageID string
+
+	// DefaultMessage is used if the message is not found in any message files.
+	DefaultMessage *Message
+
+	// TemplateData is the data passed when executing the message's template.
+	TemplateData interface{}
+
+	// PluralCount determines which plural form of the message is used.
+	PluralCount interface{}
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// errorLocalizer localizes the default message of errors
+// that do not have access to a Localizer.
+var errorLocalizer = sync.OnceValue(func() *Localizer {
+	return NewLocalizer(NewBundle(language.English))
+})
+
+// Error returns the message of the error in the language of its DefaultMessage
+// followed by the underlying error, if any.
+// Plural forms are selected with English plural rules.
+// It returns the message id if there is no DefaultMessage.
+func (e *Error) Error() string {
+	msg, err := e.Localize(errorLocalizer())
+	if err != nil || msg == "" {
+		msg = e.messageID()
+	}
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Localize returns the message of the error localized by l.
+// The underlying error is not included.
+func (e *Error) Localize(l *Localizer) (string, error) {
+	return l.Localize(e.localizeConfig())
+}
+
+func (e *Error) localizeConfig() *LocalizeConfig {
+	return &LocalizeConfig{
+		MessageID:      e.MessageID,
+		DefaultMessage: e.DefaultMessage,
+		TemplateData:   e.TemplateData,
+		PluralCount:    e.PluralCount,
+	}
+}
+
+func (e *Error) messageID() string {
+	if e.DefaultMessage != nil {
+		return e.DefaultMessage.ID
+	}
+	return e.MessageID
+}
+
+// LocalizeError returns the localized message of the first Error in err's tree.
+// If err does not contain an Error, it returns err.Error() and a nil error.
+// It returns "" if err is nil.
+func (l *Localizer) LocalizeError(err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		return err.Error(), nil
+	}
+	return e.Localize(l)
+}
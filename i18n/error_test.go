@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *Error
+		expected string
+	}{
+		{
+			name: "default message",
+			err: &Error{
+				DefaultMessage: &Message{ID: "NotFound", Other: "{{.Name}} not found"},
+				TemplateData:   map[string]string{"Name": "file.txt"},
+			},
+			expected: "file.txt not found",
+		},
+		{
+			name: "plural count",
+			err: &Error{
+				DefaultMessage: &Message{ID: "Files", One: "{{.PluralCount}} file missing", Other: "{{.PluralCount}} files missing"},
+				PluralCount:    1,
+			},
+			expected: "1 file missing",
+		},
+		{
+			name:     "message id",
+			err:      &Error{MessageID: "NotFound"},
+			expected: "NotFound",
+		},
+		{
+			name: "wrapped error",
+			err: &Error{
+				DefaultMessage: &Message{ID: "ReadFailed", Other: "read failed"},
+				Err:            io.EOF,
+			},
+			expected: "read failed: EOF",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.err.Error(); actual != test.expected {
+				t.Fatalf("expected %q; got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestErrorUnwrap(t *testing.T) {
+	err := fmt.Errorf("open: %w", &Error{MessageID: "NotFound", Err: io.EOF})
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected %v to wrap %v", err, io.EOF)
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expected %v to contain an *Error", err)
+	}
+	if e.MessageID != "NotFound" {
+		t.Fatalf("expected %q; got %q", "NotFound", e.MessageID)
+	}
+}
+
+func TestLocalizer_LocalizeError(t *testing.T) {
+	bundle := NewBundle(language.English)
+	bundle.MustAddMessages(language.Spanish, &Message{ID: "NotFound", Other: "{{.Name}} no encontrado"})
+	localizer := NewLocalizer(bundle, "es")
+
+	err := fmt.Errorf("handler: %w", &Error{
+		DefaultMessage: &Message{ID: "NotFound", Other: "{{.Name}} not found"},
+		TemplateData:   map[string]string{"Name": "file.txt"},
+	})
+	localized, lerr := localizer.LocalizeError(err)
+	if lerr != nil {
+		t.Fatal(lerr)
+	}
+	if expected := "file.txt no encontrado"; localized != expected {
+		t.Fatalf("expected %q; got %q", expected, localized)
+	}
+
+	localized, lerr = localizer.LocalizeError(io.EOF)
+	if lerr != nil {
+		t.Fatal(lerr)
+	}
+	if expected := "EOF"; localized != expected {
+		t.Fatalf("expected %q; got %q", expected, localized)
+	}
+
+	localized, lerr = localizer.LocalizeError(nil)
+	if lerr != nil || localized != "" {
+		t.Fatalf("expected empty message; got %q, %v", localized, lerr)
+	}
+}
@@ -139,3 +139,26 @@ func ExampleLocalizer_MustLocalize_customTemplateDelims() {
 	// Output:
 	// Hello Nick!
 }
+
+func ExampleLocalizer_LocalizeError() {
+	bundle := i18n.NewBundle(language.English)
+	bundle.MustAddMessages(language.Spanish, &i18n.Message{
+		ID:    "FileNotFound",
+		Other: "No se encontró {{.Name}}",
+	})
+
+	err := fmt.Errorf("open config: %w", &i18n.Error{
+		DefaultMessage: &i18n.Message{
+			ID:    "FileNotFound",
+			Other: "{{.Name}} not found",
+		},
+		TemplateData: map[string]string{"Name": "config.toml"},
+	})
+	fmt.Println(err)
+
+	localizer := i18n.NewLocalizer(bundle, "es")
+	fmt.Println(localizer.LocalizeError(err))
+	// Output:
+	// open config: config.toml not found
+	// No se encontró config.toml <nil>
+}
@@ -0,0 +1,219 @@
+// Package gettext converts messages to and from gettext PO files.
+//
+// Each message is an entry whose context (msgctxt) is the message id and whose msgid
+// (and msgid_plural) is the content of the source message that is translated.
+// Entries without a context use their msgid as the message id.
+//
+// Messages with plural forms have a msgstr[n] for each plural form of the language of the file
+// in CLDR order (zero, one, two, few, many, other), which is the order of the Plural-Forms header.
+// Source messages can only have the one and other plural forms, which are the msgid and msgid_plural.
+// The language is read from the Language header.
+//
+// Descriptions are comments for translators (#.), as is the hash of the source message (#. hash: ...).
+// Translations of fuzzy entries are not used.
+//
+// Load PO files into a bundle with Unmarshal.
+//
+//	bundle.RegisterUnmarshalFunc("po", gettext.Unmarshal)
+//	bundle.MustLoadMessageFile("active.es.po")
+package gettext
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// hashComment is the prefix of the comment that contains the hash of the source message.
+const hashComment = "hash: "
+
+var pluralRules = plural.DefaultRules()
+
+// gettextPluralForms returns the Plural-Forms header of PO files of langTag, or "" if it is unknown.
+func gettextPluralForms(langTag language.Tag) string {
+	if langTag == language.Und {
+		return ""
+	}
+	if rule := pluralRules.Rule(langTag); rule != nil {
+		return rule.GettextPluralForms
+	}
+	return ""
+}
+
+// pluralForms returns the plural forms of the translations of f in the order of msgstr[n].
+func (f *File) pluralForms() ([]plural.Form, error) {
+	if f.Language == language.Und {
+		return nil, fmt.Errorf("plural forms require a Language header")
+	}
+	rule := pluralRules.Rule(f.Language)
+	if rule == nil {
+		return nil, fmt.Errorf("no plural rule for language %s", f.Language)
+	}
+	return rule.SortedPluralForms(), nil
+}
+
+// AddMessage adds an entry for the translation of src to the language of f and returns it.
+// The translation may be nil if src is not translated yet.
+// POT files have empty translations.
+// The msgid and msgid_plural of entries can only contain the one and other plural forms of src,
+// so it returns an error if src has other plural forms.
+func (f *File) AddMessage(src, translation *i18n.Message) (*Entry, error) {
+	var unsupported []string
+	for _, form := range []plural.Form{plural.Zero, plural.Two, plural.Few, plural.Many} {
+		if pluralContent(src, form) != "" {
+			unsupported = append(unsupported, string(form))
+		}
+	}
+	if len(unsupported) > 0 {
+		return nil, fmt.Errorf("message %q has the plural forms %s, but PO files only contain the one and other forms of source messages", src.ID, strings.Join(unsupported, ", "))
+	}
+	e := &Entry{
+		References: src.References,
+		Context:    src.ID,
+		ID:         src.Other,
+	}
+	if src.Description != "" {
+		e.ExtractedComments = strings.Split(src.Description, "\n")
+	}
+	if src.Hash != "" {
+		e.ExtractedComments = append(e.ExtractedComments, hashComment+src.Hash)
+	}
+	if translation == nil {
+		translation = &i18n.Message{}
+	}
+	if src.One == "" {
+		e.Str = []string{translation.Other}
+		f.Entries = append(f.Entries, e)
+		return e, nil
+	}
+
+	if src.One != "" {
+		e.ID = src.One
+	}
+	e.IDPlural = src.Other
+	if f.Language == language.Und {
+		// POT files have the plural forms of English.
+		e.Str = []string{"", ""}
+		f.Entries = append(f.Entries, e)
+		return e, nil
+	}
+	forms, err := f.pluralForms()
+	if err != nil {
+		return nil, err
+	}
+	for _, form := range forms {
+		e.Str = append(e.Str, pluralContent(translation, form))
+	}
+	f.Entries = append(f.Entries, e)
+	return e, nil
+}
+
+func pluralContent(m *i18n.Message, form plural.Form) string {
+	switch form {
+	case plural.Zero:
+		return m.Zero
+	case plural.One:
+		return m.One
+	case plural.Two:
+		return m.Two
+	case plural.Few:
+		return m.Few
+	case plural.Many:
+		return m.Many
+	}
+	return m.Other
+}
+
+// Unmarshal parses the PO file data and stores its messages in the value pointed to by v,
+// which must be a *interface{} or a *map[string]interface{}.
+// It is an i18n.UnmarshalFunc.
+func Unmarshal(data []byte, v interface{}) error {
+	f := &File{}
+	if err := f.UnmarshalText(data); err != nil {
+		return err
+	}
+	messages, err := f.values()
+	if err != nil {
+		return err
+	}
+	switch p := v.(type) {
+	case *interface{}:
+		*p = messages
+	case *map[string]interface{}:
+		*p = messages
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+	return nil
+}
+
+// values returns the messages of f by id in the format of message files.
+func (f *File) values() (map[string]interface{}, error) {
+	messages := make(map[string]interface{}, len(f.Entries))
+	for _, e := range f.Entries {
+		id := e.Context
+		if id == "" {
+			id = e.ID
+		}
+		if _, ok := messages[id]; ok {
+			return nil, fmt.Errorf("duplicate message id %q", id)
+		}
+		m := map[string]interface{}{}
+		var description []string
+		for _, c := range e.ExtractedComments {
+			if h, ok := strings.CutPrefix(c, hashComment); ok {
+				m["hash"] = h
+				continue
+			}
+			description = append(description, c)
+		}
+		if len(description) > 0 {
+			m["description"] = strings.Join(description, "\n")
+		}
+		if len(e.References) > 0 {
+			references := make([]interface{}, len(e.References))
+			for i, r := range e.References {
+				references[i] = r
+			}
+			m["references"] = references
+		}
+		if !e.Fuzzy() {
+			if err := f.addTranslations(m, e); err != nil {
+				return nil, fmt.Errorf("message %q: %w", id, err)
+			}
+		}
+		messages[id] = m
+	}
+	return messages, nil
+}
+
+// addTranslations adds the translations of e to the message m.
+func (f *File) addTranslations(m map[string]interface{}, e *Entry) error {
+	if e.IDPlural == "" {
+		if len(e.Str) > 0 {
+			m[string(plural.Other)] = e.Str[0]
+		}
+		return nil
+	}
+	translated := false
+	for _, s := range e.Str {
+		translated = translated || s != ""
+	}
+	if !translated {
+		return nil
+	}
+	forms, err := f.pluralForms()
+	if err != nil {
+		return err
+	}
+	if len(e.Str) > len(forms) {
+		return fmt.Errorf("%d plural forms but language %s has %d", len(e.Str), f.Language, len(forms))
+	}
+	for i, s := range e.Str {
+		m[string(forms[i])] = s
+	}
+	return nil
+}
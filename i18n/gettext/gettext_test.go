@@ -0,0 +1,297 @@
+package gettext
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func TestMarshalText(t *testing.T) {
+	f := &File{Language: language.Russian}
+	if _, err := f.AddMessage(&i18n.Message{
+		ID:          "Cats",
+		Description: "The number of cats",
+		Hash:        "v2-sha256-1",
+		References:  []string{"main.go:12"},
+		One:         "{{.Count}} cat",
+		Other:       "{{.Count}} cats",
+	}, &i18n.Message{
+		One:   "{{.Count}} кошка",
+		Few:   "{{.Count}} кошки",
+		Many:  "{{.Count}} кошек",
+		Other: "{{.Count}} кошки",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := f.AddMessage(&i18n.Message{
+		ID:    "Hello",
+		Hash:  "v2-sha256-2",
+		Other: "Hello \"{{.Name}}\"\nWelcome",
+	}, &i18n.Message{Other: "Привет"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Flags = append(e.Flags, "fuzzy")
+	e.PreviousID = "Hello"
+
+	actual, err := f.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `msgid ""
+msgstr ""
+"Language: ru\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: ` + pluralRules.Rule(language.Russian).GettextPluralForms + `\n"
+
+#. The number of cats
+#. hash: v2-sha256-1
+#: main.go:12
+msgctxt "Cats"
+msgid "{{.Count}} cat"
+msgid_plural "{{.Count}} cats"
+msgstr[0] "{{.Count}} кошка"
+msgstr[1] "{{.Count}} кошки"
+msgstr[2] "{{.Count}} кошек"
+msgstr[3] "{{.Count}} кошки"
+
+#. hash: v2-sha256-2
+#, fuzzy
+#| msgid "Hello"
+msgctxt "Hello"
+msgid ""
+"Hello \"{{.Name}}\"\n"
+"Welcome"
+msgstr "Привет"
+`
+	if string(actual) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+
+	parsed := &File{}
+	if err := parsed.UnmarshalText(actual); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, f) {
+		t.Errorf("expected %#v; got %#v", f, parsed)
+	}
+}
+
+func TestMarshalTextPOT(t *testing.T) {
+	f := &File{Language: language.Und}
+	if _, err := f.AddMessage(&i18n.Message{ID: "Cats", One: "{{.Count}} cat", Other: "{{.Count}} cats"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := f.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `msgid ""
+msgstr ""
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+
+msgctxt "Cats"
+msgid "{{.Count}} cat"
+msgid_plural "{{.Count}} cats"
+msgstr[0] ""
+msgstr[1] ""
+`
+	if string(actual) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]interface{}
+		err      bool
+	}{
+		{
+			name: "messages",
+			data: `# Translated by hand.
+msgid ""
+msgstr ""
+"Language: es\n"
+"Plural-Forms: nplurals=3; plural=(n == 1 ? 0 : n != 0 && n % 1000000 == 0 ? 1 : 2);\n"
+
+# Checked
+#. Greets the user.
+#. Shown on the home page.
+#. hash: v2-sha256-1
+#: main.go:12 main.go:20
+msgctxt "Hello"
+msgid "Hello"
+msgstr ""
+"Hola\n"
+"Bienvenido"
+
+msgctxt "Cats"
+msgid "{{.Count}} cat"
+msgid_plural "{{.Count}} cats"
+msgstr[0] "{{.Count}} gato"
+msgstr[1] "{{.Count}} de gatos"
+msgstr[2] "{{.Count}} gatos"
+
+msgid "Bye"
+msgstr "Adiós"
+
+#, fuzzy
+#| msgid "Goodbye"
+msgctxt "Goodbye"
+msgid "Good bye"
+msgstr "Adiós"
+
+#~ msgid "Old"
+#~ msgstr "Viejo"
+`,
+			expected: map[string]interface{}{
+				"Hello": map[string]interface{}{
+					"description": "Greets the user.\nShown on the home page.",
+					"hash":        "v2-sha256-1",
+					"references":  []interface{}{"main.go:12", "main.go:20"},
+					"other":       "Hola\nBienvenido",
+				},
+				"Cats": map[string]interface{}{
+					"one":   "{{.Count}} gato",
+					"many":  "{{.Count}} de gatos",
+					"other": "{{.Count}} gatos",
+				},
+				"Bye": map[string]interface{}{
+					"other": "Adiós",
+				},
+				"Goodbye": map[string]interface{}{},
+			},
+		},
+		{
+			name: "plural without language",
+			data: `msgid "{{.Count}} cat"
+msgid_plural "{{.Count}} cats"
+msgstr[0] "{{.Count}} gato"
+msgstr[1] "{{.Count}} gatos"
+`,
+			err: true,
+		},
+		{
+			name: "untranslated plural without language",
+			data: `msgid "{{.Count}} cat"
+msgid_plural "{{.Count}} cats"
+msgstr[0] ""
+msgstr[1] ""
+`,
+			expected: map[string]interface{}{
+				"{{.Count}} cat": map[string]interface{}{},
+			},
+		},
+		{
+			name: "duplicate id",
+			data: `msgid "Hello"
+msgstr "Hola"
+
+msgid "Hello"
+msgstr "Buenos días"
+`,
+			err: true,
+		},
+		{
+			name: "invalid string",
+			data: `msgid Hello
+msgstr "Hola"
+`,
+			err: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual interface{}
+			err := Unmarshal([]byte(test.data), &actual)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected error; got %#v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %#v; got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBundle(t *testing.T) {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("po", Unmarshal)
+	bundle.MustParseMessageFileBytes([]byte(`msgid ""
+msgstr "Language: es\n"
+
+msgctxt "Cats"
+msgid "{{.Count}} cat"
+msgid_plural "{{.Count}} cats"
+msgstr[0] "{{.Count}} gato"
+msgstr[1] "{{.Count}} de gatos"
+msgstr[2] "{{.Count}} gatos"
+`), "active.es.po")
+	localizer := i18n.NewLocalizer(bundle, "es")
+	actual := localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "Cats", PluralCount: 2, TemplateData: map[string]int{"Count": 2}})
+	if expected := "2 gatos"; actual != expected {
+		t.Errorf("expected %q; got %q", expected, actual)
+	}
+}
+
+func TestAddMessageUnsupportedPluralForms(t *testing.T) {
+	f := &File{Language: language.Arabic}
+	if _, err := f.AddMessage(&i18n.Message{ID: "Cats", Zero: "no cats", One: "{{.Count}} cat", Other: "{{.Count}} cats"}, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(f.Entries) != 0 {
+		t.Fatalf("expected no entries; got %d", len(f.Entries))
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected string
+		err      bool
+	}{
+		{s: `""`, expected: ""},
+		{s: `"hello"`, expected: "hello"},
+		{s: `"\"quoted\" \\ \n\r\t"`, expected: "\"quoted\" \\ \n\r\t"},
+		{s: `"it\'s\?"`, expected: "it's?"},
+		{s: `"\a\b\f\v"`, expected: "\a\b\f\v"},
+		{s: `"\033[0m \x1b \x7e1 \0"`, expected: "\033[0m \x1b \x7e1 \x00"},
+		{s: `"привет"`, expected: "привет"},
+		{s: `hello`, err: true},
+		{s: `"a"b"`, err: true},
+		{s: `"\"`, err: true},
+		{s: `"\u00e9"`, err: true},
+		{s: `"\U000000e9"`, err: true},
+		{s: `"\xg"`, err: true},
+		{s: `"\400"`, err: true},
+		{s: `"\e"`, err: true},
+	}
+	for _, test := range tests {
+		actual, err := unquote(test.s)
+		if test.err {
+			if err == nil {
+				t.Errorf("unquote(%s) returned %q; expected error", test.s, actual)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unquote(%s) unexpected error: %s", test.s, err)
+		} else if actual != test.expected {
+			t.Errorf("unquote(%s) returned %q; expected %q", test.s, actual, test.expected)
+		}
+	}
+}
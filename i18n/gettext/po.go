@@ -0,0 +1,410 @@
+package gettext
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// File is a gettext PO file, or a POT file if it has no language.
+type File struct {
+	// Language is the language of the translations, or language.Und for POT files.
+	Language language.Tag
+
+	// Entries are the entries of the file other than the header.
+	Entries []*Entry
+}
+
+// Entry is a message in a PO file.
+type Entry struct {
+	// TranslatorComments are the comments of translators (# comment).
+	TranslatorComments []string
+
+	// ExtractedComments are the comments for translators (#. comment).
+	ExtractedComments []string
+
+	// References are the source locations of the message (#: file:line).
+	References []string
+
+	// Flags are the flags of the entry (#, fuzzy).
+	Flags []string
+
+	// PreviousID is the msgid that the fuzzy translation was translated from (#| msgid).
+	PreviousID string
+
+	// Context is the msgctxt of the entry.
+	Context string
+
+	// ID is the msgid of the entry.
+	ID string
+
+	// IDPlural is the msgid_plural of the entry, which is empty if the entry has no plural forms.
+	IDPlural string
+
+	// Str are the msgstr[n] of an entry with plural forms, or the msgstr of an entry without.
+	Str []string
+}
+
+// Fuzzy returns true if the entry has the fuzzy flag, which means that the translation needs to be reviewed.
+func (e *Entry) Fuzzy() bool {
+	for _, flag := range e.Flags {
+		if flag == "fuzzy" {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalText parses the PO file data.
+// Obsolete entries (#~) are ignored.
+func (f *File) UnmarshalText(data []byte) error {
+	p := &parser{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		p.line++
+		if err := p.parseLine(strings.TrimSpace(scanner.Text())); err != nil {
+			return fmt.Errorf("line %d: %w", p.line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	p.endEntry()
+
+	f.Language = language.Und
+	f.Entries = nil
+	for _, e := range p.entries {
+		if e.ID == "" && e.Context == "" {
+			// The header is the translation of the empty msgid.
+			if len(e.Str) > 0 {
+				f.Language = headerLanguage(e.Str[0])
+			}
+			continue
+		}
+		f.Entries = append(f.Entries, e)
+	}
+	return nil
+}
+
+// headerLanguage returns the value of the Language field of the PO file header.
+func headerLanguage(header string) language.Tag {
+	for _, line := range strings.Split(header, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(name) == "Language" {
+			if tag, err := language.Parse(strings.TrimSpace(value)); err == nil {
+				return tag
+			}
+		}
+	}
+	return language.Und
+}
+
+// parser parses the lines of a PO file.
+type parser struct {
+	line    int
+	entries []*Entry
+	entry   *Entry
+
+	// str points to the string that continuation lines are appended to.
+	str *string
+
+	// hasID is true if the current entry has a msgid.
+	hasID bool
+}
+
+func (p *parser) parseLine(line string) error {
+	switch {
+	case line == "":
+		p.endEntry()
+		p.str = nil
+		return nil
+	case strings.HasPrefix(line, "#~"):
+		// Obsolete entries are not used.
+		p.str = nil
+		return nil
+	case strings.HasPrefix(line, "#|"):
+		e := p.comment()
+		keyword, rest := cutKeyword(strings.TrimSpace(line[2:]))
+		switch keyword {
+		case "msgid":
+			s, err := unquote(rest)
+			if err != nil {
+				return err
+			}
+			e.PreviousID = s
+			p.str = &e.PreviousID
+		case "":
+			if p.str != &e.PreviousID {
+				p.str = nil
+				return nil
+			}
+			s, err := unquote(rest)
+			if err != nil {
+				return err
+			}
+			e.PreviousID += s
+		default:
+			p.str = nil
+		}
+		return nil
+	case strings.HasPrefix(line, "#."):
+		e := p.comment()
+		e.ExtractedComments = append(e.ExtractedComments, strings.TrimSpace(line[2:]))
+		return nil
+	case strings.HasPrefix(line, "#:"):
+		e := p.comment()
+		e.References = append(e.References, strings.Fields(line[2:])...)
+		return nil
+	case strings.HasPrefix(line, "#,"):
+		e := p.comment()
+		for _, flag := range strings.Split(line[2:], ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				e.Flags = append(e.Flags, flag)
+			}
+		}
+		return nil
+	case strings.HasPrefix(line, "#"):
+		e := p.comment()
+		e.TranslatorComments = append(e.TranslatorComments, strings.TrimSpace(line[1:]))
+		return nil
+	case strings.HasPrefix(line, `"`):
+		if p.str == nil {
+			return fmt.Errorf("unexpected string %s", line)
+		}
+		s, err := unquote(line)
+		if err != nil {
+			return err
+		}
+		*p.str += s
+		return nil
+	}
+
+	keyword, rest := cutKeyword(line)
+	s, err := unquote(rest)
+	if err != nil {
+		return err
+	}
+	switch {
+	case keyword == "msgctxt":
+		e := p.comment()
+		e.Context = s
+		p.str = &e.Context
+	case keyword == "msgid":
+		if p.hasID {
+			p.endEntry()
+		}
+		e := p.comment()
+		e.ID = s
+		p.str = &e.ID
+		p.hasID = true
+	case keyword == "msgid_plural" && p.hasID:
+		p.entry.IDPlural = s
+		p.str = &p.entry.IDPlural
+	case keyword == "msgstr" && p.hasID:
+		p.entry.Str = []string{s}
+		p.str = &p.entry.Str[0]
+	case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]") && p.hasID:
+		i, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+		if err != nil || i != len(p.entry.Str) {
+			return fmt.Errorf("unexpected %s", keyword)
+		}
+		p.entry.Str = append(p.entry.Str, s)
+		p.str = &p.entry.Str[i]
+	default:
+		return fmt.Errorf("unexpected %s", keyword)
+	}
+	return nil
+}
+
+// comment returns the current entry, starting a new entry if the current entry is complete.
+func (p *parser) comment() *Entry {
+	if p.hasID && len(p.entry.Str) > 0 {
+		p.endEntry()
+	}
+	if p.entry == nil {
+		p.entry = &Entry{}
+	}
+	return p.entry
+}
+
+func (p *parser) endEntry() {
+	if p.entry != nil && p.hasID {
+		p.entries = append(p.entries, p.entry)
+	}
+	p.entry = nil
+	p.hasID = false
+}
+
+// cutKeyword returns the keyword at the start of line and the rest of the line.
+func cutKeyword(line string) (keyword, rest string) {
+	if strings.HasPrefix(line, `"`) {
+		return "", line
+	}
+	keyword, rest, _ = strings.Cut(line, " ")
+	return keyword, strings.TrimSpace(rest)
+}
+
+// unquote returns the value of the C string literal s.
+// It accepts the escape sequences of C, which are \a, \b, \f, \n, \r, \t, \v, \\, \", \', \?,
+// octal escapes of up to three digits (e.g. \033) and hexadecimal escapes of up to two digits (e.g. \x1b).
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("expected quoted string but got %s", s)
+	}
+	content := s[1 : len(s)-1]
+	var b strings.Builder
+	b.Grow(len(content))
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if c == '"' {
+			return "", fmt.Errorf("invalid string %s: unescaped quote", s)
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i == len(content) {
+			return "", fmt.Errorf("invalid string %s: unterminated escape sequence", s)
+		}
+		switch c = content[i]; c {
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case '\\', '"', '\'', '?':
+			b.WriteByte(c)
+		case 'x':
+			j := i + 1
+			for j < len(content) && j < i+3 && isHexDigit(content[j]) {
+				j++
+			}
+			if j == i+1 {
+				return "", fmt.Errorf("invalid string %s: \\x without hexadecimal digits", s)
+			}
+			n, _ := strconv.ParseUint(content[i+1:j], 16, 8)
+			b.WriteByte(byte(n))
+			i = j - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			j := i
+			for j < len(content) && j < i+3 && '0' <= content[j] && content[j] <= '7' {
+				j++
+			}
+			n, _ := strconv.ParseUint(content[i:j], 8, 16)
+			if n > 0xff {
+				return "", fmt.Errorf("invalid string %s: octal escape \\%s is out of range", s, content[i:j])
+			}
+			b.WriteByte(byte(n))
+			i = j - 1
+		default:
+			return "", fmt.Errorf("invalid string %s: unknown escape sequence \\%c", s, c)
+		}
+	}
+	return b.String(), nil
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// MarshalText returns the content of the PO file.
+// The header contains the language and the Plural-Forms of PO files.
+func (f *File) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	var header []string
+	if f.Language != language.Und {
+		header = append(header, "Language: "+f.Language.String())
+	}
+	header = append(header,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+	)
+	if pluralForms := gettextPluralForms(f.Language); pluralForms != "" {
+		header = append(header, "Plural-Forms: "+pluralForms)
+	}
+	writeString(&buf, "msgid", "")
+	writeString(&buf, "msgstr", strings.Join(header, "\n")+"\n")
+	for _, e := range f.Entries {
+		buf.WriteString("\n")
+		for _, c := range e.TranslatorComments {
+			writeComment(&buf, "#", c)
+		}
+		for _, c := range e.ExtractedComments {
+			writeComment(&buf, "#.", c)
+		}
+		for _, r := range e.References {
+			writeComment(&buf, "#:", r)
+		}
+		if len(e.Flags) > 0 {
+			writeComment(&buf, "#,", strings.Join(e.Flags, ", "))
+		}
+		if e.PreviousID != "" {
+			writeString(&buf, "#| msgid", e.PreviousID)
+		}
+		if e.Context != "" {
+			writeString(&buf, "msgctxt", e.Context)
+		}
+		writeString(&buf, "msgid", e.ID)
+		if e.IDPlural != "" {
+			writeString(&buf, "msgid_plural", e.IDPlural)
+			for i, s := range e.Str {
+				writeString(&buf, fmt.Sprintf("msgstr[%d]", i), s)
+			}
+			continue
+		}
+		str := ""
+		if len(e.Str) > 0 {
+			str = e.Str[0]
+		}
+		writeString(&buf, "msgstr", str)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeComment(buf *bytes.Buffer, prefix, comment string) {
+	buf.WriteString(prefix)
+	if comment != "" {
+		buf.WriteString(" ")
+		buf.WriteString(comment)
+	}
+	buf.WriteString("\n")
+}
+
+// writeString writes the keyword and the string s, which is split after newlines if it has multiple lines.
+func writeString(buf *bytes.Buffer, keyword, s string) {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= 1 {
+		fmt.Fprintf(buf, "%s \"%s\"\n", keyword, quoteReplacer.Replace(s))
+		return
+	}
+	// Continuation lines of previous strings are comments too.
+	prefix := ""
+	if strings.HasPrefix(keyword, "#|") {
+		prefix = "#| "
+	}
+	fmt.Fprintf(buf, "%s \"\"\n", keyword)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s\"%s\"\n", prefix, quoteReplacer.Replace(line))
+	}
+}
@@ -0,0 +1,255 @@
+// Package i18next converts messages to and from the JSON files of i18next (JSON format v4).
+//
+// Nested keys are joined with dots to message ids ({"home": {"title": "Home"}} is home.title)
+// and messages with plural forms are keys with the suffix of each plural form (cats_one, cats_other).
+// Interpolations are template fields ({{name}} is {{.name}}) with the same name.
+// i18next selects plural forms with the count option, so messages that are shared with i18next
+// usually print the plural count with {{.count}}.
+//
+// Other template actions, formats ({{value, number}}) and nesting ($t(key)) are not converted.
+//
+// Load i18next files into a bundle with Unmarshal.
+//
+//	bundle.RegisterUnmarshalFunc("i18next", i18next.Unmarshal)
+//	bundle.MustLoadMessageFile("active.es.i18next")
+package i18next
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal"
+	"github.com/nicksnyder/go-i18n/v2/internal/message"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+)
+
+// File is an i18next JSON file.
+type File struct {
+	// Messages are the messages of the file, whose content is Go template syntax.
+	Messages []*i18n.Message
+
+	// plurals contains the ids of messages that are plurals even if they only have the other form.
+	plurals map[string]bool
+}
+
+// keySeparator separates nested keys.
+const keySeparator = "."
+
+// AddMessage adds the translation of src to f, or src if translation is nil.
+// Translations of messages with plural forms have plural keys (cats_other),
+// even if their language only has the other form.
+// It returns an error if the translation can not be converted to i18next interpolations.
+func (f *File) AddMessage(src, translation *i18n.Message) error {
+	m := src
+	if translation != nil {
+		m = &i18n.Message{
+			ID:         src.ID,
+			LeftDelim:  translation.LeftDelim,
+			RightDelim: translation.RightDelim,
+			Zero:       translation.Zero,
+			One:        translation.One,
+			Two:        translation.Two,
+			Few:        translation.Few,
+			Many:       translation.Many,
+			Other:      translation.Other,
+		}
+	}
+	pluralMessage := message.IsPlural(message.Forms(src))
+	if _, err := values(m, pluralMessage); err != nil {
+		return fmt.Errorf("message %q: %w", m.ID, err)
+	}
+	if pluralMessage {
+		if f.plurals == nil {
+			f.plurals = make(map[string]bool)
+		}
+		f.plurals[m.ID] = true
+	}
+	f.Messages = append(f.Messages, m)
+	return nil
+}
+
+// values returns the i18next values of m by key, relative to the id of m.
+// The keys of plural messages have the suffix of their plural form.
+func values(m *i18n.Message, pluralMessage bool) (map[string]string, error) {
+	forms := message.Forms(m)
+	v := make(map[string]string, len(forms))
+	for form, content := range forms {
+		parts, err := internal.TemplateParts(content, m.LeftDelim, m.RightDelim)
+		if err != nil {
+			return nil, err
+		}
+		var b strings.Builder
+		for _, part := range parts {
+			if part.Field != "" {
+				b.WriteString("{{" + part.Field + "}}")
+				continue
+			}
+			if strings.Contains(part.Text, "{{") {
+				return nil, fmt.Errorf("{{ can not be converted")
+			}
+			b.WriteString(part.Text)
+		}
+		key := ""
+		if pluralMessage {
+			key = "_" + string(form)
+		}
+		v[key] = b.String()
+	}
+	return v, nil
+}
+
+// MarshalJSON returns the i18next file with the messages of f, where message ids are nested keys.
+func (f *File) MarshalJSON() ([]byte, error) {
+	root := map[string]interface{}{}
+	for _, m := range f.Messages {
+		v, err := values(m, f.plurals[m.ID] || message.IsPlural(message.Forms(m)))
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %w", m.ID, err)
+		}
+		path := strings.Split(m.ID, keySeparator)
+		parent := root
+		for _, key := range path[:len(path)-1] {
+			child, ok := parent[key].(map[string]interface{})
+			if !ok {
+				if _, exists := parent[key]; exists {
+					return nil, fmt.Errorf("message %q: key %q is a message", m.ID, key)
+				}
+				child = map[string]interface{}{}
+				parent[key] = child
+			}
+			parent = child
+		}
+		for suffix, content := range v {
+			key := path[len(path)-1] + suffix
+			if _, exists := parent[key]; exists {
+				return nil, fmt.Errorf("message %q: duplicate key %q", m.ID, key)
+			}
+			parent[key] = content
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var interpolationRegexp = regexp.MustCompile(`\{\{-?\s*([^{}]*?)\s*\}\}`)
+
+var fieldRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// template returns the Go template of the i18next value s.
+func template(s string) (string, error) {
+	var err error
+	t := interpolationRegexp.ReplaceAllStringFunc(s, func(interpolation string) string {
+		name := interpolationRegexp.FindStringSubmatch(interpolation)[1]
+		if !fieldRegexp.MatchString(name) {
+			err = fmt.Errorf("interpolation %s can not be converted", interpolation)
+		}
+		return "{{." + name + "}}"
+	})
+	return t, err
+}
+
+// UnmarshalJSON parses the i18next file data.
+// Messages are sorted by id.
+func (f *File) UnmarshalJSON(data []byte) error {
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	messages := map[string]map[string]interface{}{}
+	f.plurals = map[string]bool{}
+	if err := addValues(messages, f.plurals, "", root); err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(messages))
+	for id := range messages {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	f.Messages = make([]*i18n.Message, 0, len(ids))
+	for _, id := range ids {
+		v := messages[id]
+		v["id"] = id
+		m, err := i18n.NewMessage(v)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", id, err)
+		}
+		f.Messages = append(f.Messages, m)
+	}
+	return nil
+}
+
+// addValues adds the values of the nested object with the key prefix to messages
+// and the ids of messages with plural keys to plurals.
+func addValues(messages map[string]map[string]interface{}, plurals map[string]bool, prefix string, object map[string]interface{}) error {
+	for key, value := range object {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := addValues(messages, plurals, prefix+key+keySeparator, v); err != nil {
+				return err
+			}
+		case string:
+			id, form := prefix+key, plural.Other
+			for _, f := range plural.Forms {
+				if base, ok := strings.CutSuffix(key, "_"+string(f)); ok {
+					id, form = prefix+base, f
+					plurals[id] = true
+					break
+				}
+			}
+			if messages[id] == nil {
+				messages[id] = map[string]interface{}{}
+			}
+			if _, ok := messages[id][string(form)]; ok {
+				return fmt.Errorf("key %q: duplicate %s plural form of %q", prefix+key, form, id)
+			}
+			t, err := template(v)
+			if err != nil {
+				return fmt.Errorf("key %q: %w", prefix+key, err)
+			}
+			if t != "" {
+				messages[id][string(form)] = t
+			}
+		default:
+			return fmt.Errorf("key %q: unsupported value %v", prefix+key, value)
+		}
+	}
+	return nil
+}
+
+// Unmarshal parses the i18next file data and stores its messages in the value pointed to by v,
+// which must be a *interface{} or a *map[string]interface{}.
+// It is an i18n.UnmarshalFunc.
+func Unmarshal(data []byte, v interface{}) error {
+	f := &File{}
+	if err := f.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	messages := make(map[string]interface{}, len(f.Messages))
+	for _, m := range f.Messages {
+		value := map[string]interface{}{}
+		for form, content := range message.Forms(m) {
+			value[string(form)] = content
+		}
+		messages[m.ID] = value
+	}
+	switch p := v.(type) {
+	case *interface{}:
+		*p = messages
+	case *map[string]interface{}:
+		*p = messages
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+	return nil
+}
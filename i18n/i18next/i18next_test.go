@@ -0,0 +1,181 @@
+package i18next
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	f := &File{}
+	for _, m := range []*i18n.Message{
+		{ID: "home.title", Description: "Not exported", Other: "Welcome {{.name}}"},
+		{ID: "home.cats", One: "{{.count}} cat", Other: "{{.count}} cats"},
+		{ID: "delims", LeftDelim: "<<", RightDelim: ">>", Other: "Hi <<.name>>"},
+		{ID: "greeting", Other: "Hello"},
+	} {
+		if err := f.AddMessage(m, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, m := range []*i18n.Message{
+		{ID: "function", Other: "{{upper .Name}}"},
+		{ID: "interpolation", LeftDelim: "<<", RightDelim: ">>", Other: "{{name}}"},
+	} {
+		if err := f.AddMessage(m, nil); err == nil {
+			t.Errorf("%s: expected error", m.ID)
+		}
+	}
+	actual, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "delims": "Hi {{name}}",
+  "greeting": "Hello",
+  "home": {
+    "cats_one": "{{count}} cat",
+    "cats_other": "{{count}} cats",
+    "title": "Welcome {{name}}"
+  }
+}
+`
+	if string(actual) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+
+	parsed := &File{}
+	if err := parsed.UnmarshalJSON(actual); err != nil {
+		t.Fatal(err)
+	}
+	expectedMessages := []*i18n.Message{
+		{ID: "delims", Other: "Hi {{.name}}"},
+		{ID: "greeting", Other: "Hello"},
+		{ID: "home.cats", One: "{{.count}} cat", Other: "{{.count}} cats"},
+		{ID: "home.title", Other: "Welcome {{.name}}"},
+	}
+	if !reflect.DeepEqual(parsed.Messages, expectedMessages) {
+		t.Errorf("expected %#v; got %#v", expectedMessages, parsed.Messages)
+	}
+}
+
+func TestMarshalJSONCollision(t *testing.T) {
+	f := &File{Messages: []*i18n.Message{
+		{ID: "home", Other: "Home"},
+		{ID: "home.title", Other: "Welcome"},
+	}}
+	if _, err := f.MarshalJSON(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]interface{}
+		err      bool
+	}{
+		{
+			name: "messages",
+			data: `{
+  "hello": "Hola {{- name }}",
+  "user": {
+    "items_zero": "Ningún elemento",
+    "items_one": "{{count}} elemento",
+    "items_many": "{{count}} de elementos",
+    "items_other": "{{count}} elementos",
+    "profile": "Perfil de {{user.name}}"
+  }
+}`,
+			expected: map[string]interface{}{
+				"hello": map[string]interface{}{
+					"other": "Hola {{.name}}",
+				},
+				"user.items": map[string]interface{}{
+					"zero":  "Ningún elemento",
+					"one":   "{{.count}} elemento",
+					"many":  "{{.count}} de elementos",
+					"other": "{{.count}} elementos",
+				},
+				"user.profile": map[string]interface{}{
+					"other": "Perfil de {{.user.name}}",
+				},
+			},
+		},
+		{
+			name: "format",
+			data: `{"price": "{{value, currency}}"}`,
+			err:  true,
+		},
+		{
+			name: "duplicate plural form",
+			data: `{"cats": "cats", "cats_other": "cats"}`,
+			err:  true,
+		},
+		{
+			name: "array",
+			data: `{"list": ["a", "b"]}`,
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual interface{}
+			err := Unmarshal([]byte(test.data), &actual)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected error; got %#v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %#v; got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBundle(t *testing.T) {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("i18next", Unmarshal)
+	bundle.MustParseMessageFileBytes([]byte(`{
+  "cats_one": "{{count}} gato",
+  "cats_many": "{{count}} de gatos",
+  "cats_other": "{{count}} gatos",
+  "home": {"title": "Hola {{name}}"}
+}`), "active.es.i18next")
+	localizer := i18n.NewLocalizer(bundle, "es")
+	actual := localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "cats", PluralCount: 2, TemplateData: map[string]int{"count": 2}})
+	if expected := "2 gatos"; actual != expected {
+		t.Errorf("expected %q; got %q", expected, actual)
+	}
+	actual = localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "home.title", TemplateData: map[string]string{"name": "Ana"}})
+	if expected := "Hola Ana"; actual != expected {
+		t.Errorf("expected %q; got %q", expected, actual)
+	}
+}
+
+func TestAddMessageTranslation(t *testing.T) {
+	f := &File{}
+	src := &i18n.Message{ID: "cats", One: "{{.count}} cat", Other: "{{.count}} cats"}
+	if err := f.AddMessage(src, &i18n.Message{ID: "cats", Other: "猫{{.count}}匹"}); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "cats_other": "猫{{count}}匹"
+}
+`
+	if string(actual) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+}
@@ -2,9 +2,12 @@ package i18n
 
 import (
 	"fmt"
+	"io"
+	"sync/atomic"
 	texttemplate "text/template"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n/template"
+	"github.com/nicksnyder/go-i18n/v2/internal/buffer"
 	"github.com/nicksnyder/go-i18n/v2/internal/plural"
 	"golang.org/x/text/language"
 )
@@ -26,6 +29,21 @@ type Localizer struct {
 	// tags is the list of language tags that the Localizer checks
 	// in order when localizing a message.
 	tags []language.Tag
+
+	// match caches the language of the bundle that matches tags.
+	match atomic.Pointer[localizerMatch]
+}
+
+// localizerMatch is the language of a bundle that matches the tags of a Localizer.
+// It is only valid for the matcher of the bundle that it was matched with,
+// which the bundle replaces whenever a language is added.
+type localizerMatch struct {
+	matcher language.Matcher
+	tag     language.Tag
+
+	// fallbacks are the languages whose messages are looked up in order,
+	// which are tag and the default language of the bundle if it is different.
+	fallbacks []language.Tag
 }
 
 // NewLocalizer returns a new Localizer that looks up messages
@@ -58,7 +76,8 @@ type LocalizeConfig struct {
 
 	// TemplateData is the data passed when executing the message's template.
 	// If TemplateData is nil and PluralCount is not nil, then the message template
-	// will be executed with data that contains the plural count.
+	// will be executed with a PluralCountData that contains the plural count.
+	// Templates that use other fields fail in that case, so pass TemplateData if they need other data.
 	TemplateData interface{}
 
 	// PluralCount determines which plural form of the message is used.
@@ -68,6 +87,7 @@ type LocalizeConfig struct {
 	DefaultMessage *Message
 
 	// Funcs is used to configure a template.TextParser if TemplateParser is not set.
+	// Templates are parsed once for the names of the functions and executed with the functions in Funcs.
 	Funcs texttemplate.FuncMap
 
 	// The TemplateParser to use for parsing templates.
@@ -131,21 +151,70 @@ func (l *Localizer) LocalizeMessage(msg *Message) (string, error) {
 	})
 }
 
-// TODO: uncomment this (and the test) when extract has been updated to extract these call sites too.
-// Localize returns a localized message.
-// func (l *Localizer) LocalizeMessageID(messageID string) (string, error) {
-// 	return l.Localize(&LocalizeConfig{
-// 		MessageID: messageID,
-// 	})
-// }
+// LocalizeMessageID returns a localized message.
+func (l *Localizer) LocalizeMessageID(messageID string) (string, error) {
+	return l.Localize(&LocalizeConfig{
+		MessageID: messageID,
+	})
+}
 
 // LocalizeWithTag returns a localized message and the language tag.
 // It may return a best effort localized message even if an error happens.
 func (l *Localizer) LocalizeWithTag(lc *LocalizeConfig) (string, language.Tag, error) {
+	var msg string
+	tag, err := l.localize(lc, func(mt *MessageTemplate, pluralForm plural.Form, data interface{}, parser template.Parser) error {
+		s, err := mt.execute(pluralForm, data, parser)
+		if err == nil {
+			msg = s
+		}
+		return err
+	})
+	return msg, tag, err
+}
+
+// LocalizeTo writes a localized message to w.
+// It may write a best effort localized message even if an error happens.
+// The message is executed into a pooled buffer before it is written,
+// so w does not receive the partial output of templates that fail.
+func (l *Localizer) LocalizeTo(w io.Writer, lc *LocalizeConfig) error {
+	buf := buffer.Get()
+	defer buffer.Put(buf)
+	_, err := l.localize(lc, func(mt *MessageTemplate, pluralForm plural.Form, data interface{}, parser template.Parser) error {
+		buf.Reset()
+		err := mt.executeTo(buf, pluralForm, data, parser)
+		if err != nil {
+			buf.Reset()
+		}
+		return err
+	})
+	if buf.Len() > 0 {
+		if _, werr := w.Write(buf.Bytes()); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// executeFunc executes the template of a message for a plural form.
+type executeFunc func(mt *MessageTemplate, pluralForm plural.Form, data interface{}, parser template.Parser) error
+
+// PluralCountData is the template data of messages that are localized with a PluralCount
+// and without TemplateData. Templates print the plural count with {{.PluralCount}}.
+//
+// Earlier versions passed map[string]interface{}{"PluralCount": count} instead,
+// so custom template parsers that expect a map must also handle PluralCountData.
+// Templates are executed faster with a struct than with a map.
+type PluralCountData struct {
+	PluralCount interface{}
+}
+
+// localize calls execute with the message template and plural form for lc and returns the language of the message template.
+// If execute fails, it is called again with the "Other" plural form.
+func (l *Localizer) localize(lc *LocalizeConfig, execute executeFunc) (language.Tag, error) {
 	messageID := lc.MessageID
 	if lc.DefaultMessage != nil {
 		if messageID != "" && messageID != lc.DefaultMessage.ID {
-			return "", language.Und, &messageIDMismatchErr{messageID: messageID, defaultMessageID: lc.DefaultMessage.ID}
+			return language.Und, &messageIDMismatchErr{messageID: messageID, defaultMessageID: lc.DefaultMessage.ID}
 		}
 		messageID = lc.DefaultMessage.ID
 	}
@@ -153,26 +222,24 @@ func (l *Localizer) LocalizeWithTag(lc *LocalizeConfig) (string, language.Tag, e
 	var operands *plural.Operands
 	templateData := lc.TemplateData
 	if lc.PluralCount != nil {
-		var err error
-		operands, err = plural.NewOperands(lc.PluralCount)
+		ops, err := plural.NewOperands(lc.PluralCount)
 		if err != nil {
-			return "", language.Und, &invalidPluralCountErr{messageID: messageID, pluralCount: lc.PluralCount, err: err}
+			return language.Und, &invalidPluralCountErr{messageID: messageID, pluralCount: lc.PluralCount, err: err}
 		}
+		operands = &ops
 		if templateData == nil {
-			templateData = map[string]interface{}{
-				"PluralCount": lc.PluralCount,
-			}
+			templateData = PluralCountData{PluralCount: lc.PluralCount}
 		}
 	}
 
-	tag, template, err := l.getMessageTemplate(messageID, lc.DefaultMessage)
-	if template == nil {
-		return "", language.Und, err
+	tag, mt, err := l.getMessageTemplate(messageID, lc.DefaultMessage)
+	if mt == nil {
+		return language.Und, err
 	}
 
 	pluralForm := l.pluralForm(tag, operands)
 	templateParser := lc.getTemplateParser()
-	msg, err2 := template.execute(pluralForm, templateData, templateParser)
+	err2 := execute(mt, pluralForm, templateData, templateParser)
 	if err2 != nil {
 		if err == nil {
 			err = err2
@@ -180,52 +247,65 @@ func (l *Localizer) LocalizeWithTag(lc *LocalizeConfig) (string, language.Tag, e
 
 		// Attempt to fallback to "Other" pluralization in case translations are incomplete.
 		if pluralForm != plural.Other {
-			msg2, err3 := template.execute(plural.Other, templateData, templateParser)
-			if err3 == nil {
-				msg = msg2
-			}
+			_ = execute(mt, plural.Other, templateData, templateParser)
 		}
 	}
-	return msg, tag, err
+	return tag, err
 }
 
-func (l *Localizer) getMessageTemplate(id string, defaultMessage *Message) (language.Tag, *MessageTemplate, error) {
-	_, i, _ := l.bundle.matcher.Match(l.tags...)
-	tag := l.bundle.tags[i]
-	mt := l.bundle.getMessageTemplate(tag, id)
-	if mt != nil {
-		return tag, mt, nil
-	}
+// LanguageTag returns the language of the bundle that best matches the language preferences of l,
+// which is the language that l localizes messages in.
+// Messages that are not translated in that language are localized in the default language of the bundle.
+func (l *Localizer) LanguageTag() language.Tag {
+	return l.matchLanguage().tag
+}
 
-	if tag == l.bundle.defaultLanguage {
-		if defaultMessage == nil {
-			return language.Und, nil, &MessageNotFoundErr{Tag: tag, MessageID: id}
-		}
-		mt := NewMessageTemplate(defaultMessage)
-		if mt == nil {
-			return language.Und, nil, &MessageNotFoundErr{Tag: tag, MessageID: id}
-		}
-		return tag, mt, nil
+// matchLanguage returns the language of the bundle that best matches the tags of l and its fallbacks.
+func (l *Localizer) matchLanguage() *localizerMatch {
+	matcher := l.bundle.matcher
+	if m := l.match.Load(); m != nil && m.matcher == matcher {
+		return m
+	}
+	_, i, _ := matcher.Match(l.tags...)
+	m := &localizerMatch{matcher: matcher, tag: l.bundle.tags[i]}
+	m.fallbacks = []language.Tag{m.tag}
+	if m.tag != l.bundle.defaultLanguage {
+		m.fallbacks = append(m.fallbacks, l.bundle.defaultLanguage)
 	}
+	l.match.Store(m)
+	return m
+}
 
-	// Fallback to default language in bundle.
-	mt = l.bundle.getMessageTemplate(l.bundle.defaultLanguage, id)
-	if mt != nil {
-		return l.bundle.defaultLanguage, mt, &MessageNotFoundErr{Tag: tag, MessageID: id}
+func (l *Localizer) getMessageTemplate(id string, defaultMessage *Message) (language.Tag, *MessageTemplate, error) {
+	m := l.matchLanguage()
+	for i, tag := range m.fallbacks {
+		if mt := l.bundle.getMessageTemplate(tag, id); mt != nil {
+			if i == 0 {
+				return tag, mt, nil
+			}
+			return tag, mt, &MessageNotFoundErr{Tag: m.tag, MessageID: id}
+		}
 	}
 
 	// Fallback to default message.
 	if defaultMessage == nil {
-		return language.Und, nil, &MessageNotFoundErr{Tag: tag, MessageID: id}
+		return language.Und, nil, &MessageNotFoundErr{Tag: m.tag, MessageID: id}
 	}
-	return l.bundle.defaultLanguage, NewMessageTemplate(defaultMessage), &MessageNotFoundErr{Tag: tag, MessageID: id}
+	mt := NewMessageTemplate(defaultMessage)
+	if mt == nil {
+		return language.Und, nil, &MessageNotFoundErr{Tag: m.tag, MessageID: id}
+	}
+	if m.tag == l.bundle.defaultLanguage {
+		return m.tag, mt, nil
+	}
+	return l.bundle.defaultLanguage, mt, &MessageNotFoundErr{Tag: m.tag, MessageID: id}
 }
 
 func (l *Localizer) pluralForm(tag language.Tag, operands *plural.Operands) plural.Form {
 	if operands == nil {
 		return plural.Other
 	}
-	return l.bundle.pluralRules.Rule(tag).PluralFormFunc(operands)
+	return l.bundle.pluralRules.Rule(tag).PluralFormFunc(*operands)
 }
 
 // MustLocalize is similar to Localize, except it panics if an error happens.
@@ -245,3 +325,12 @@ func (l *Localizer) MustLocalizeMessage(msg *Message) string {
 	}
 	return localized
 }
+
+// MustLocalizeMessageID is similar to LocalizeMessageID, except it panics if an error happens.
+func (l *Localizer) MustLocalizeMessageID(messageID string) string {
+	localized, err := l.LocalizeMessageID(messageID)
+	if err != nil {
+		panic(err)
+	}
+	return localized
+}
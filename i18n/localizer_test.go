@@ -1,8 +1,10 @@
 package i18n
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"testing"
 	gotmpl "text/template"
@@ -702,13 +704,17 @@ func TestLocalizer_Localize(t *testing.T) {
 			localizer := NewLocalizer(bundle, test.acceptLangs...)
 			check(localizer.Localize(test.conf))
 
+			var buf bytes.Buffer
+			err := localizer.LocalizeTo(&buf, test.conf)
+			check(buf.String(), err)
+
 			if test.conf.DefaultMessage != nil && reflect.DeepEqual(test.conf, &LocalizeConfig{DefaultMessage: test.conf.DefaultMessage}) {
 				check(localizer.LocalizeMessage(test.conf.DefaultMessage))
 			}
 
-			// if test.conf.MessageID != "" && reflect.DeepEqual(test.conf, &LocalizeConfig{MessageID: test.conf.MessageID}) {
-			// 	check(localizer.LocalizeMessageID(test.conf.MessageID))
-			// }
+			if test.conf.MessageID != "" && reflect.DeepEqual(test.conf, &LocalizeConfig{MessageID: test.conf.MessageID}) {
+				check(localizer.LocalizeMessageID(test.conf.MessageID))
+			}
 		})
 	}
 }
@@ -724,6 +730,7 @@ func BenchmarkLocalizer_Localize(b *testing.B) {
 			}
 
 			localizer := NewLocalizer(bundle, test.acceptLangs...)
+			b.ReportAllocs()
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
 				_, _ = localizer.Localize(test.conf)
@@ -732,6 +739,218 @@ func BenchmarkLocalizer_Localize(b *testing.B) {
 	}
 }
 
+// TestLocalizer_Allocs keeps allocations out of localizing messages.
+// The allocations that remain are made by text/template and by returning the message as a string.
+func TestLocalizer_Allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	bundle := NewBundle(language.English)
+	bundle.MustAddMessages(language.English, &Message{
+		ID:    "Cats",
+		One:   "{{.PluralCount}} cat",
+		Other: "{{.PluralCount}} cats",
+	}, &Message{
+		ID:    "Hello",
+		Other: "Hello {{.Name}}",
+	}, &Message{
+		ID:    "Simple",
+		Other: "Simple message",
+	})
+	localizer := NewLocalizer(bundle, "en")
+	tests := []struct {
+		name                string
+		conf                *LocalizeConfig
+		maxLocalizeAllocs   float64
+		maxLocalizeToAllocs float64
+	}{
+		{
+			name:                "simple",
+			conf:                &LocalizeConfig{MessageID: "Simple"},
+			maxLocalizeAllocs:   0,
+			maxLocalizeToAllocs: 0,
+		},
+		{
+			name:                "template data",
+			conf:                &LocalizeConfig{MessageID: "Hello", TemplateData: map[string]string{"Name": "Nick"}},
+			maxLocalizeAllocs:   5,
+			maxLocalizeToAllocs: 4,
+		},
+		{
+			name:                "plural count",
+			conf:                &LocalizeConfig{MessageID: "Cats", PluralCount: 2},
+			maxLocalizeAllocs:   4,
+			maxLocalizeToAllocs: 3,
+		},
+	}
+	// The pool of buffers may drop its buffers at any garbage collection,
+	// so allocating a new buffer is tolerated.
+	const poolAllocs = 1
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, func() {
+				if _, err := localizer.Localize(test.conf); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs > test.maxLocalizeAllocs+poolAllocs {
+				t.Errorf("expected at most %v allocations from Localize; got %v", test.maxLocalizeAllocs, allocs)
+			}
+			allocs = testing.AllocsPerRun(100, func() {
+				if err := localizer.LocalizeTo(io.Discard, test.conf); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs > test.maxLocalizeToAllocs+poolAllocs {
+				t.Errorf("expected at most %v allocations from LocalizeTo; got %v", test.maxLocalizeToAllocs, allocs)
+			}
+		})
+	}
+}
+
+func TestLocalizer_PluralCountData(t *testing.T) {
+	bundle := NewBundle(language.English)
+	bundle.MustAddMessages(language.English, &Message{
+		ID:    "Cats",
+		One:   "{{.Name}} has {{.PluralCount}} cat",
+		Other: "{{.Name}} has {{.PluralCount}} cats",
+	})
+	localizer := NewLocalizer(bundle, "en")
+	// Templates can only use the plural count if there is no TemplateData.
+	if localized, err := localizer.Localize(&LocalizeConfig{MessageID: "Cats", PluralCount: 2}); err == nil {
+		t.Errorf("expected error; got %q", localized)
+	}
+	localized, err := localizer.Localize(&LocalizeConfig{
+		MessageID:    "Cats",
+		TemplateData: map[string]interface{}{"Name": "Nick", "PluralCount": 2},
+		PluralCount:  2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Nick has 2 cats"; localized != expected {
+		t.Errorf("expected %q; got %q", expected, localized)
+	}
+
+	// Failing templates are executed once.
+	calls := 0
+	lc := &LocalizeConfig{
+		DefaultMessage: &Message{ID: "Dogs", Other: "{{count}} {{.Name}} dogs"},
+		PluralCount:    2,
+		Funcs:          map[string]any{"count": func() int { calls++; return calls }},
+	}
+	if localized, err := localizer.Localize(lc); err == nil {
+		t.Errorf("expected error; got %q", localized)
+	}
+	if calls != 1 {
+		t.Errorf("expected the template to be executed once; got %d", calls)
+	}
+}
+
+// dataParser is a template parser whose templates record the data that they are executed with.
+type dataParser struct {
+	data any
+}
+
+func (p *dataParser) Parse(src, leftDelim, rightDelim string) (template.ParsedTemplate, error) {
+	return p, nil
+}
+
+func (p *dataParser) Cacheable() bool {
+	return false
+}
+
+func (p *dataParser) Execute(data any) (string, error) {
+	p.data = data
+	return "", nil
+}
+
+func TestLocalizer_PluralCountDataParser(t *testing.T) {
+	localizer := NewLocalizer(NewBundle(language.English), "en")
+	parser := &dataParser{}
+	if _, err := localizer.Localize(&LocalizeConfig{
+		DefaultMessage: &Message{ID: "Cats", One: "{{.PluralCount}} cat", Other: "{{.PluralCount}} cats"},
+		PluralCount:    2,
+		TemplateParser: parser,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (PluralCountData{PluralCount: 2}); parser.data != expected {
+		t.Errorf("expected data %#v; got %#v", expected, parser.data)
+	}
+}
+
+func BenchmarkLocalizer_LocalizeTo(b *testing.B) {
+	bundle := NewBundle(language.English)
+	bundle.MustAddMessages(language.English, &Message{
+		ID:    "Cats",
+		One:   "{{.PluralCount}} cat",
+		Other: "{{.PluralCount}} cats",
+	})
+	localizer := NewLocalizer(bundle, "en")
+	lc := &LocalizeConfig{MessageID: "Cats", PluralCount: 2}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := localizer.LocalizeTo(io.Discard, lc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestLocalizer_MatchAfterAddMessages(t *testing.T) {
+	bundle := NewBundle(language.English)
+	bundle.MustAddMessages(language.English, &Message{ID: "Hello", Other: "Hello"})
+	localizer := NewLocalizer(bundle, "es")
+	if localized := localizer.MustLocalize(&LocalizeConfig{MessageID: "Hello"}); localized != "Hello" {
+		t.Fatalf("expected %q; got %q", "Hello", localized)
+	}
+
+	// The matched language changes when the bundle has a better match.
+	bundle.MustAddMessages(language.Spanish, &Message{ID: "Hello", Other: "Hola"})
+	if localized := localizer.MustLocalize(&LocalizeConfig{MessageID: "Hello"}); localized != "Hola" {
+		t.Fatalf("expected %q; got %q", "Hola", localized)
+	}
+}
+
+// BenchmarkLocalizer_PageRender localizes the messages of a page with one Localizer per request.
+func BenchmarkLocalizer_PageRender(b *testing.B) {
+	const messageCount = 100
+	bundle := NewBundle(language.English)
+	for _, tag := range []language.Tag{language.English, language.German, language.Spanish, language.French, language.BrazilianPortuguese, language.Japanese} {
+		messages := make([]*Message, messageCount)
+		for i := range messages {
+			messages[i] = &Message{ID: fmt.Sprintf("Message%d", i), Other: fmt.Sprintf("%s message %d", tag, i)}
+		}
+		bundle.MustAddMessages(tag, messages...)
+	}
+	configs := make([]*LocalizeConfig, messageCount)
+	for i := range configs {
+		configs[i] = &LocalizeConfig{MessageID: fmt.Sprintf("Message%d", i)}
+	}
+	render := func(b *testing.B, uncached bool) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			localizer := NewLocalizer(bundle, "pt-BR,pt;q=0.9,en-US;q=0.8,en;q=0.7")
+			for _, lc := range configs {
+				if uncached {
+					// Match the languages for every message like Localizers did before matches were cached.
+					localizer.match.Store(nil)
+				}
+				if _, err := localizer.Localize(lc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+	b.Run("cached", func(b *testing.B) {
+		render(b, false)
+	})
+	b.Run("uncached", func(b *testing.B) {
+		render(b, true)
+	})
+}
+
 func TestMessageNotFoundError(t *testing.T) {
 	actual := (&MessageNotFoundErr{Tag: language.AmericanEnglish, MessageID: "hello"}).Error()
 	expected := `message "hello" not found in language "en-US"`
@@ -779,3 +998,14 @@ func TestMustLocalizeMessage(t *testing.T) {
 	localizer := NewLocalizer(bundle)
 	localizer.MustLocalizeMessage(&Message{})
 }
+
+func TestMustLocalizeMessageID(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("MustLocalizeMessageID did not panic")
+		}
+	}()
+	bundle := NewBundle(language.English)
+	localizer := NewLocalizer(bundle)
+	localizer.MustLocalizeMessageID("hello")
+}
@@ -42,6 +42,10 @@ type Message struct {
 
 	// Other is the content of the message for the CLDR plural form "other".
 	Other string
+
+	// References are the source locations (e.g. "main.go:12") where the message is defined.
+	// They give translators additional context and are not used for localization.
+	References []string
 }
 
 // NewMessage parses data and returns a new message.
@@ -92,6 +96,8 @@ func (m *Message) unmarshalInterface(v interface{}) error {
 			m.Many = v
 		case "other":
 			m.Other = v
+		case "references":
+			m.References = strings.Split(v, "\n")
 		}
 	}
 	return nil
@@ -171,6 +177,21 @@ func stringSubmap(k string, v interface{}, strdata map[string]string) error {
 		return nil
 	case nil:
 		return nil
+	case []interface{}:
+		if strings.ToLower(k) == "references" {
+			// References are joined because messages are parsed from string maps.
+			refs := make([]string, 0, len(vt))
+			for _, ref := range vt {
+				s, ok := ref.(string)
+				if !ok {
+					return fmt.Errorf("expected references to be strings but got %#v", ref)
+				}
+				refs = append(refs, s)
+			}
+			strdata[k] = strings.Join(refs, "\n")
+			return nil
+		}
+		return fmt.Errorf("expected value for key %q be a string but got %#v", k, v)
 	default:
 		return fmt.Errorf("expected value for key %q be a string but got %#v", k, v)
 	}
@@ -188,6 +209,7 @@ var reservedKeys = map[string]struct{}{
 	"few":         {},
 	"many":        {},
 	"other":       {},
+	"references":  {},
 	"translation": {},
 }
 
@@ -197,6 +219,11 @@ func isReserved(key string, val any) bool {
 		if key == "translation" {
 			return true
 		}
+		if lk == "references" {
+			// References are only reserved as a list so that "references" can still be a message id.
+			_, ok := val.([]interface{})
+			return ok
+		}
 		if _, ok := val.(string); ok {
 			return true
 		}
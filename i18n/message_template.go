@@ -2,6 +2,7 @@ package i18n
 
 import (
 	"fmt"
+	"io"
 	texttemplate "text/template"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n/template"
@@ -80,3 +81,14 @@ func (mt *MessageTemplate) execute(pluralForm plural.Form, data interface{}, par
 	}
 	return t.Execute(parser, data)
 }
+
+func (mt *MessageTemplate) executeTo(w io.Writer, pluralForm plural.Form, data interface{}, parser template.Parser) error {
+	t := mt.PluralTemplates[pluralForm]
+	if t == nil {
+		return pluralFormNotFoundError{
+			pluralForm: pluralForm,
+			messageID:  mt.ID,
+		}
+	}
+	return t.ExecuteTo(parser, w, data)
+}
@@ -47,6 +47,38 @@ func TestParseMessageFileBytes(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name: "references",
+			file: `{"hello": {"other": "world", "references": ["main.go:12", "ui/hello.go:3"]}}`,
+			path: "en.json",
+			messageFile: &MessageFile{
+				Path:   "en.json",
+				Tag:    language.English,
+				Format: "json",
+				Messages: []*Message{{
+					ID:         "hello",
+					Other:      "world",
+					References: []string{"main.go:12", "ui/hello.go:3"},
+				}},
+			},
+		},
+		{
+			name: "references message id",
+			file: `{"Welcome": "Hi", "References": "Sources"}`,
+			path: "en.json",
+			messageFile: &MessageFile{
+				Path:   "en.json",
+				Tag:    language.English,
+				Format: "json",
+				Messages: []*Message{{
+					ID:    "References",
+					Other: "Sources",
+				}, {
+					ID:    "Welcome",
+					Other: "Hi",
+				}},
+			},
+		},
 		{
 			name: "basic test reserved key top level",
 			file: `{"other": "world", "foo": "bar"}`,
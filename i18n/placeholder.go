@@ -0,0 +1,165 @@
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/internal"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+// PlaceholderDiff describes how the placeholders referenced by a plural form of a translation
+// differ from the placeholders referenced by the source message that it was translated from.
+//
+// Placeholders are the top level fields of the template data (e.g. "Name" in "{{.Name}}")
+// and the template functions (e.g. "upper" in "{{upper .Name}}").
+// A plural form of a translation may omit a placeholder that is only referenced by some
+// plural forms of the source message (e.g. "{{.PluralCount}}" in "other" but not in "one")
+// because languages use plural forms for different numbers.
+type PlaceholderDiff struct {
+	// MessageID is the id of the message.
+	MessageID string
+
+	// PluralForm is the plural form of the translation.
+	PluralForm plural.Form
+
+	// MissingFields are referenced by every plural form of the source message but not by the translation.
+	MissingFields []string
+
+	// ExtraFields are referenced by the translation but not by any plural form of the source message.
+	ExtraFields []string
+
+	// MissingFuncs are referenced by every plural form of the source message but not by the translation.
+	MissingFuncs []string
+
+	// ExtraFuncs are referenced by the translation but not by any plural form of the source message.
+	ExtraFuncs []string
+}
+
+func (d *PlaceholderDiff) String() string {
+	var diffs []string
+	for _, field := range d.MissingFields {
+		diffs = append(diffs, "missing field "+field)
+	}
+	for _, field := range d.ExtraFields {
+		diffs = append(diffs, "extra field "+field)
+	}
+	for _, fn := range d.MissingFuncs {
+		diffs = append(diffs, "missing func "+fn)
+	}
+	for _, fn := range d.ExtraFuncs {
+		diffs = append(diffs, "extra func "+fn)
+	}
+	return fmt.Sprintf("message %q plural form %q: %s", d.MessageID, d.PluralForm, strings.Join(diffs, ", "))
+}
+
+// ComparePlaceholders returns the differences between the placeholders referenced by
+// each plural form of translation and the placeholders referenced by src.
+// It returns an error if any template can not be parsed.
+func ComparePlaceholders(src, translation *Message) ([]*PlaceholderDiff, error) {
+	srcTemplate := NewMessageTemplate(src)
+	dstTemplate := NewMessageTemplate(translation)
+	if srcTemplate == nil || dstTemplate == nil {
+		return nil, nil
+	}
+
+	var allFields, allFuncs, everyFields, everyFuncs []string
+	first := true
+	for _, form := range plural.Forms {
+		t := srcTemplate.PluralTemplates[form]
+		if t == nil {
+			continue
+		}
+		fields, funcs, err := internal.TemplateReferences(t.Src, t.LeftDelim, t.RightDelim)
+		if err != nil {
+			return nil, fmt.Errorf("message %q has invalid %s template: %w", src.ID, form, err)
+		}
+		allFields, allFuncs = union(allFields, fields), union(allFuncs, funcs)
+		if first {
+			everyFields, everyFuncs = fields, funcs
+			first = false
+		} else {
+			everyFields, everyFuncs = intersect(everyFields, fields), intersect(everyFuncs, funcs)
+		}
+	}
+
+	var diffs []*PlaceholderDiff
+	for _, form := range plural.Forms {
+		t := dstTemplate.PluralTemplates[form]
+		if t == nil {
+			continue
+		}
+		fields, funcs, err := internal.TemplateReferences(t.Src, t.LeftDelim, t.RightDelim)
+		if err != nil {
+			return nil, fmt.Errorf("message %q has invalid %s template: %w", translation.ID, form, err)
+		}
+		diff := &PlaceholderDiff{
+			MessageID:     translation.ID,
+			PluralForm:    form,
+			MissingFields: difference(everyFields, fields),
+			ExtraFields:   difference(fields, allFields),
+			MissingFuncs:  difference(everyFuncs, funcs),
+			ExtraFuncs:    difference(funcs, allFuncs),
+		}
+		if len(diff.MissingFields)+len(diff.ExtraFields)+len(diff.MissingFuncs)+len(diff.ExtraFuncs) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+// union returns the sorted union of the sorted slices a and b.
+func union(a, b []string) []string {
+	u := append(slices.Clone(a), b...)
+	slices.Sort(u)
+	return slices.Compact(u)
+}
+
+// intersect returns the elements of a that are also in b.
+func intersect(a, b []string) []string {
+	var i []string
+	for _, s := range a {
+		if slices.Contains(b, s) {
+			i = append(i, s)
+		}
+	}
+	return i
+}
+
+// difference returns the elements of a that are not in b.
+func difference(a, b []string) []string {
+	var d []string
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			d = append(d, s)
+		}
+	}
+	return d
+}
+
+// PlaceholderMismatchFunc is called by AddMessages when the placeholders of translations
+// differ from the placeholders of the corresponding messages in the default language.
+// If it returns an error, AddMessages returns that error and does not add any messages.
+// If it returns nil, the messages are added (e.g. after logging a warning).
+type PlaceholderMismatchFunc func(tag language.Tag, diffs []*PlaceholderDiff) error
+
+// PlaceholderMismatchErr is returned by RejectPlaceholderMismatch.
+type PlaceholderMismatchErr struct {
+	Tag   language.Tag
+	Diffs []*PlaceholderDiff
+}
+
+func (e *PlaceholderMismatchErr) Error() string {
+	diffs := make([]string, 0, len(e.Diffs))
+	for _, d := range e.Diffs {
+		diffs = append(diffs, d.String())
+	}
+	return fmt.Sprintf("placeholders of %s translations do not match the default language: %s", e.Tag, strings.Join(diffs, "; "))
+}
+
+// RejectPlaceholderMismatch is a PlaceholderMismatchFunc that rejects all placeholder differences.
+func RejectPlaceholderMismatch(tag language.Tag, diffs []*PlaceholderDiff) error {
+	return &PlaceholderMismatchErr{Tag: tag, Diffs: diffs}
+}
@@ -0,0 +1,122 @@
+package i18n
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+	"golang.org/x/text/language"
+)
+
+func TestComparePlaceholders(t *testing.T) {
+	tests := []struct {
+		name        string
+		src         *Message
+		translation *Message
+		diffs       []*PlaceholderDiff
+		err         bool
+	}{
+		{
+			name:        "same placeholders",
+			src:         &Message{ID: "Hello", Other: "Hello {{.Name}}"},
+			translation: &Message{ID: "Hello", Other: "Hola {{.Name}}"},
+		},
+		{
+			name:        "missing and extra fields",
+			src:         &Message{ID: "Hello", Other: "Hello {{.Name}}"},
+			translation: &Message{ID: "Hello", Other: "Hola {{.Nombre}}"},
+			diffs: []*PlaceholderDiff{
+				{MessageID: "Hello", PluralForm: plural.Other, MissingFields: []string{"Name"}, ExtraFields: []string{"Nombre"}},
+			},
+		},
+		{
+			name:        "missing and extra funcs",
+			src:         &Message{ID: "Hello", Other: "Hello {{upper .Name}}"},
+			translation: &Message{ID: "Hello", Other: "Hola {{lower .Name}}"},
+			diffs: []*PlaceholderDiff{
+				{MessageID: "Hello", PluralForm: plural.Other, MissingFuncs: []string{"upper"}, ExtraFuncs: []string{"lower"}},
+			},
+		},
+		{
+			name: "placeholders that only some source plural forms use",
+			src: &Message{
+				ID:    "Cats",
+				One:   "{{.Name}} has a cat",
+				Other: "{{.Name}} has {{.PluralCount}} cats",
+			},
+			translation: &Message{
+				ID:    "Cats",
+				One:   "{{.Name}} имеет {{.PluralCount}} кошку",
+				Few:   "{{.Name}} имеет {{.PluralCount}} кошки",
+				Many:  "{{.Name}} имеет {{.PluralCount}} кошек",
+				Other: "{{.PluralCount}} кошки",
+			},
+			diffs: []*PlaceholderDiff{
+				{MessageID: "Cats", PluralForm: plural.Other, MissingFields: []string{"Name"}},
+			},
+		},
+		{
+			name:        "invalid template",
+			src:         &Message{ID: "Hello", Other: "Hello {{.Name}}"},
+			translation: &Message{ID: "Hello", Other: "Hola {{.Name"},
+			err:         true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diffs, err := ComparePlaceholders(test.src, test.translation)
+			if test.err != (err != nil) {
+				t.Fatalf("expected error %v; got %v", test.err, err)
+			}
+			if !reflect.DeepEqual(diffs, test.diffs) {
+				t.Fatalf("expected %v; got %v", test.diffs, diffs)
+			}
+		})
+	}
+}
+
+func TestPlaceholderDiffString(t *testing.T) {
+	d := &PlaceholderDiff{
+		MessageID:     "Hello",
+		PluralForm:    plural.Other,
+		MissingFields: []string{"Name"},
+		ExtraFuncs:    []string{"lower"},
+	}
+	expected := `message "Hello" plural form "other": missing field Name, extra func lower`
+	if actual := d.String(); actual != expected {
+		t.Fatalf("expected %q; got %q", expected, actual)
+	}
+}
+
+func TestBundlePlaceholderMismatch(t *testing.T) {
+	bundle := NewBundle(language.English)
+	bundle.RegisterPlaceholderMismatchFunc(RejectPlaceholderMismatch)
+	bundle.MustAddMessages(language.English, &Message{ID: "Hello", Other: "Hello {{.Name}}"})
+
+	err := bundle.AddMessages(language.Spanish, &Message{ID: "Hello", Other: "Hola {{.Nombre}}"})
+	var mismatchErr *PlaceholderMismatchErr
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("expected *PlaceholderMismatchErr; got %#v", err)
+	}
+	expected := `placeholders of es translations do not match the default language: message "Hello" plural form "other": missing field Name, extra field Nombre`
+	if err.Error() != expected {
+		t.Fatalf("expected %q; got %q", expected, err)
+	}
+	if mt := bundle.getMessageTemplate(language.Spanish, "Hello"); mt != nil {
+		t.Fatalf("expected rejected message to not be added; got %#v", mt)
+	}
+
+	var warned []*PlaceholderDiff
+	bundle.RegisterPlaceholderMismatchFunc(func(tag language.Tag, diffs []*PlaceholderDiff) error {
+		warned = diffs
+		return nil
+	})
+	bundle.MustAddMessages(language.Spanish, &Message{ID: "Hello", Other: "Hola {{.Nombre}}"})
+	if len(warned) != 1 {
+		t.Fatalf("expected 1 diff; got %v", warned)
+	}
+	if mt := bundle.getMessageTemplate(language.Spanish, "Hello"); mt == nil {
+		t.Fatal("expected message to be added")
+	}
+}
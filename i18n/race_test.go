@@ -0,0 +1,6 @@
+//go:build race
+
+package i18n
+
+// raceEnabled is true if the race detector is enabled, which makes sync.Pool drop items and allocate.
+const raceEnabled = true
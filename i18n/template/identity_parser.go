@@ -1,5 +1,7 @@
 package template
 
+import "io"
+
 // IdentityParser is an Parser that does no parsing and returns template string unchanged.
 type IdentityParser struct{}
 
@@ -19,3 +21,8 @@ type identityParsedTemplate struct {
 func (t *identityParsedTemplate) Execute(data any) (string, error) {
 	return t.src, nil
 }
+
+func (t *identityParsedTemplate) ExecuteTo(w io.Writer, data any) error {
+	_, err := io.WriteString(w, t.src)
+	return err
+}
@@ -1,6 +1,8 @@
 // Package template defines a generic interface for template parsers and implementations of that interface.
 package template
 
+import "io"
+
 // Parser parses strings into executable templates.
 type Parser interface {
 	// Parse parses src and returns a ParsedTemplate.
@@ -10,8 +12,32 @@ type Parser interface {
 	Cacheable() bool
 }
 
+// ReusableParser is a Parser whose ParsedTemplates can be reused by other parsers with the same ParseKey
+// even if they are not Cacheable, for example because they depend on functions that differ between parsers.
+type ReusableParser interface {
+	Parser
+
+	// ParseKey returns a comparable key of everything that Parse depends on
+	// except for the implementations of functions.
+	ParseKey() any
+
+	// Execute applies a ParsedTemplate that a parser with the same ParseKey returned to data
+	// with the functions of this parser.
+	Execute(pt ParsedTemplate, data any) (string, error)
+}
+
 // ParsedTemplate is an executable template.
 type ParsedTemplate interface {
 	// Execute applies a parsed template to the specified data.
 	Execute(data any) (string, error)
 }
+
+// WriterTemplate is a ParsedTemplate that can write its output to an io.Writer
+// instead of returning it as a string.
+type WriterTemplate interface {
+	ParsedTemplate
+
+	// ExecuteTo applies a parsed template to the specified data and writes the output to w.
+	// Part of the output may have been written to w if an error happens.
+	ExecuteTo(w io.Writer, data any) error
+}
@@ -1,9 +1,12 @@
 package template
 
 import (
-	"bytes"
+	"io"
+	"sort"
 	"strings"
 	"text/template"
+
+	"github.com/nicksnyder/go-i18n/v2/internal/buffer"
 )
 
 // TextParser is a Parser that uses text/template.
@@ -18,6 +21,46 @@ func (te *TextParser) Cacheable() bool {
 	return te.Funcs == nil
 }
 
+// textParseKey is the ParseKey of a TextParser.
+type textParseKey struct {
+	leftDelim  string
+	rightDelim string
+	option     string
+	funcNames  string
+}
+
+// ParseKey returns a key of the delimiters, the option and the names of the functions of te.
+// Templates only depend on the names of functions until they are executed.
+func (te *TextParser) ParseKey() any {
+	names := make([]string, 0, len(te.Funcs))
+	for name := range te.Funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return textParseKey{
+		leftDelim:  te.LeftDelim,
+		rightDelim: te.RightDelim,
+		option:     te.Option,
+		funcNames:  strings.Join(names, " "),
+	}
+}
+
+// Execute applies a template that a TextParser with the same ParseKey parsed to data with the functions of te.
+// The functions are bound to a clone of the template for every execution because they may differ from
+// the functions that the template was parsed with even if they are in the same map, which can be changed.
+// Executing a clone keeps the functions of the parsed template for concurrent executions.
+func (te *TextParser) Execute(pt ParsedTemplate, data any) (string, error) {
+	t, ok := pt.(*parsedTextTemplate)
+	if !ok {
+		return pt.Execute(data)
+	}
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	return (&parsedTextTemplate{tmpl: tmpl.Funcs(te.Funcs)}).Execute(data)
+}
+
 func (te *TextParser) Parse(src, leftDelim, rightDelim string) (ParsedTemplate, error) {
 	if leftDelim == "" {
 		leftDelim = te.LeftDelim
@@ -54,9 +97,14 @@ type parsedTextTemplate struct {
 }
 
 func (t *parsedTextTemplate) Execute(data any) (string, error) {
-	var buf bytes.Buffer
-	if err := t.tmpl.Execute(&buf, data); err != nil {
+	buf := buffer.Get()
+	defer buffer.Put(buf)
+	if err := t.tmpl.Execute(buf, data); err != nil {
 		return "", err
 	}
 	return buf.String(), nil
 }
+
+func (t *parsedTextTemplate) ExecuteTo(w io.Writer, data any) error {
+	return t.tmpl.Execute(w, data)
+}
@@ -0,0 +1,138 @@
+// Package i18nhttp provides net/http middleware that negotiates the language of a request
+// and makes an i18n.Localizer for that language available to handlers.
+//
+//	m := i18nhttp.NewMiddleware(bundle)
+//	m.Sources = []i18nhttp.Source{
+//	    i18nhttp.Query("lang"),
+//	    i18nhttp.Cookie("lang"),
+//	    i18nhttp.Header("Accept-Language"),
+//	}
+//	http.ListenAndServe(":8080", m.Handler(mux))
+//
+// Handlers retrieve the Localizer from the request context.
+//
+//	func(w http.ResponseWriter, r *http.Request) {
+//	    localizer := i18nhttp.Localizer(r.Context())
+//	}
+package i18nhttp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// Middleware negotiates the language of each request from an ordered list of sources,
+// stores a Localizer in the request context and sets the Content-Language and Vary response headers.
+type Middleware struct {
+	// Sources are consulted in order and all of the language preferences
+	// they return are passed to i18n.NewLocalizer, so earlier sources take priority.
+	// If Sources is nil, Query("lang") and Header("Accept-Language") are used.
+	Sources []Source
+
+	// Cookie is used as a template for a cookie that persists the negotiated language
+	// if a language was chosen explicitly by a Query, PathPrefix or User source.
+	// Languages that are only negotiated from other sources like Accept-Language are not persisted,
+	// so that later changes of those preferences are not overridden by the cookie.
+	// If Cookie is nil, the negotiated language is not persisted.
+	// Add a Cookie source with the same name to Sources to read the persisted language.
+	Cookie *http.Cookie
+
+	bundle *i18n.Bundle
+}
+
+// NewMiddleware returns a Middleware that localizes messages using bundle.
+func NewMiddleware(bundle *i18n.Bundle) *Middleware {
+	return &Middleware{bundle: bundle}
+}
+
+var defaultSources = []Source{
+	Query("lang"),
+	Header("Accept-Language"),
+}
+
+// Handler returns a handler that negotiates the language of each request and then calls next.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sources := m.Sources
+		if sources == nil {
+			sources = defaultSources
+		}
+		var langs []string
+		explicit := false
+		for _, source := range sources {
+			sourceLangs := source.Languages(r)
+			langs = append(langs, sourceLangs...)
+			switch s := source.(type) {
+			case explicitSource:
+				explicit = explicit || len(sourceLangs) > 0
+			case headerSource:
+				addVary(w.Header(), string(s))
+			case cookieSource:
+				addVary(w.Header(), "Cookie")
+			}
+		}
+
+		localizer := i18n.NewLocalizer(m.bundle, langs...)
+		tag := localizer.LanguageTag()
+		w.Header().Set("Content-Language", tag.String())
+		if m.Cookie != nil && explicit {
+			m.persist(w, r, tag)
+		}
+
+		ctx := NewContext(r.Context(), localizer, tag)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (m *Middleware) persist(w http.ResponseWriter, r *http.Request, tag language.Tag) {
+	value := tag.String()
+	if c, err := r.Cookie(m.Cookie.Name); err == nil && c.Value == value {
+		return
+	}
+	c := *m.Cookie
+	c.Value = value
+	http.SetCookie(w, &c)
+}
+
+func addVary(h http.Header, name string) {
+	for _, v := range h.Values("Vary") {
+		for _, field := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), name) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", name)
+}
+
+type contextKey struct{}
+
+type contextValue struct {
+	localizer *i18n.Localizer
+	tag       language.Tag
+}
+
+// NewContext returns a copy of ctx that carries localizer and the language tag it was negotiated for.
+func NewContext(ctx context.Context, localizer *i18n.Localizer, tag language.Tag) context.Context {
+	return context.WithValue(ctx, contextKey{}, &contextValue{localizer: localizer, tag: tag})
+}
+
+// Localizer returns the Localizer stored in ctx or nil if there is none.
+func Localizer(ctx context.Context) *i18n.Localizer {
+	if v, ok := ctx.Value(contextKey{}).(*contextValue); ok {
+		return v.localizer
+	}
+	return nil
+}
+
+// Language returns the negotiated language tag stored in ctx or language.Und if there is none.
+func Language(ctx context.Context) language.Tag {
+	if v, ok := ctx.Value(contextKey{}).(*contextValue); ok {
+		return v.tag
+	}
+	return language.Und
+}
@@ -0,0 +1,225 @@
+package i18nhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+func newTestBundle() *i18n.Bundle {
+	bundle := i18n.NewBundle(language.English)
+	bundle.MustAddMessages(language.English, &i18n.Message{ID: "Hello", Other: "Hello"})
+	bundle.MustAddMessages(language.Spanish, &i18n.Message{ID: "Hello", Other: "Hola"})
+	bundle.MustAddMessages(language.French, &i18n.Message{ID: "Hello", Other: "Bonjour"})
+	return bundle
+}
+
+func TestMiddleware(t *testing.T) {
+	bundle := newTestBundle()
+	tests := []struct {
+		name            string
+		sources         []Source
+		cookie          *http.Cookie
+		target          string
+		header          http.Header
+		expected        string
+		expectedLang    string
+		expectedVary    []string
+		expectedCookie  string
+		expectNoCookies bool
+	}{
+		{
+			name:         "default sources without preferences",
+			target:       "/",
+			expected:     "Hello",
+			expectedLang: "en",
+			expectedVary: []string{"Accept-Language"},
+		},
+		{
+			name:         "default sources query",
+			target:       "/?lang=es",
+			header:       http.Header{"Accept-Language": {"fr"}},
+			expected:     "Hola",
+			expectedLang: "es",
+			expectedVary: []string{"Accept-Language"},
+		},
+		{
+			name:         "default sources header",
+			target:       "/",
+			header:       http.Header{"Accept-Language": {"fr-CA,fr;q=0.9"}},
+			expected:     "Bonjour",
+			expectedLang: "fr",
+			expectedVary: []string{"Accept-Language"},
+		},
+		{
+			name:         "path prefix",
+			sources:      []Source{PathPrefix(bundle), Header("Accept-Language")},
+			target:       "/fr/about",
+			header:       http.Header{"Accept-Language": {"es"}},
+			expected:     "Bonjour",
+			expectedLang: "fr",
+			expectedVary: []string{"Accept-Language"},
+		},
+		{
+			name:         "path prefix that is not a language",
+			sources:      []Source{PathPrefix(bundle), Header("Accept-Language")},
+			target:       "/about",
+			header:       http.Header{"Accept-Language": {"es"}},
+			expected:     "Hola",
+			expectedLang: "es",
+			expectedVary: []string{"Accept-Language"},
+		},
+		{
+			name:         "path prefix that is a language of the bundle",
+			sources:      []Source{PathPrefix(bundle), Header("Accept-Language")},
+			target:       "/es-MX/about",
+			header:       http.Header{"Accept-Language": {"fr"}},
+			expected:     "Hola",
+			expectedLang: "es",
+			expectedVary: []string{"Accept-Language"},
+		},
+		{
+			name:         "path prefix that is a language tag but not a language of the bundle",
+			sources:      []Source{PathPrefix(bundle), Header("Accept-Language")},
+			target:       "/api/users",
+			header:       http.Header{"Accept-Language": {"es"}},
+			expected:     "Hola",
+			expectedLang: "es",
+			expectedVary: []string{"Accept-Language"},
+		},
+		{
+			name:         "cookie",
+			sources:      []Source{Cookie("lang"), Header("Accept-Language")},
+			target:       "/",
+			header:       http.Header{"Cookie": {"lang=es"}, "Accept-Language": {"fr"}},
+			expected:     "Hola",
+			expectedLang: "es",
+			expectedVary: []string{"Cookie", "Accept-Language"},
+		},
+		{
+			name: "user",
+			sources: []Source{
+				User(func(r *http.Request) string { return "fr" }),
+				Header("Accept-Language"),
+			},
+			target:       "/",
+			header:       http.Header{"Accept-Language": {"es"}},
+			expected:     "Bonjour",
+			expectedLang: "fr",
+			expectedVary: []string{"Accept-Language"},
+		},
+		{
+			name:           "persist cookie",
+			sources:        []Source{Query("lang"), Cookie("lang")},
+			cookie:         &http.Cookie{Name: "lang", Path: "/"},
+			target:         "/?lang=es",
+			header:         http.Header{"Cookie": {"lang=fr"}},
+			expected:       "Hola",
+			expectedLang:   "es",
+			expectedVary:   []string{"Cookie"},
+			expectedCookie: "lang=es; Path=/",
+		},
+		{
+			name:            "persist cookie unchanged",
+			sources:         []Source{Query("lang"), Cookie("lang")},
+			cookie:          &http.Cookie{Name: "lang", Path: "/"},
+			target:          "/",
+			header:          http.Header{"Cookie": {"lang=es"}},
+			expected:        "Hola",
+			expectedLang:    "es",
+			expectedVary:    []string{"Cookie"},
+			expectNoCookies: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewMiddleware(bundle)
+			m.Sources = test.sources
+			m.Cookie = test.cookie
+
+			var localized string
+			var tag language.Tag
+			handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				localized = Localizer(r.Context()).MustLocalize(&i18n.LocalizeConfig{MessageID: "Hello"})
+				tag = Language(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, test.target, nil)
+			for k, v := range test.header {
+				r.Header[k] = v
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if localized != test.expected {
+				t.Errorf("expected %q; got %q", test.expected, localized)
+			}
+			if tag.String() != test.expectedLang {
+				t.Errorf("expected language %q; got %q", test.expectedLang, tag)
+			}
+			if actual := w.Header().Get("Content-Language"); actual != test.expectedLang {
+				t.Errorf("expected Content-Language %q; got %q", test.expectedLang, actual)
+			}
+			vary := w.Header().Values("Vary")
+			if len(vary) != len(test.expectedVary) {
+				t.Fatalf("expected Vary %v; got %v", test.expectedVary, vary)
+			}
+			for i := range vary {
+				if vary[i] != test.expectedVary[i] {
+					t.Errorf("expected Vary %v; got %v", test.expectedVary, vary)
+				}
+			}
+			cookie := w.Header().Get("Set-Cookie")
+			if test.expectNoCookies && cookie != "" {
+				t.Errorf("expected no cookie; got %q", cookie)
+			}
+			if test.expectedCookie != "" && cookie != test.expectedCookie {
+				t.Errorf("expected cookie %q; got %q", test.expectedCookie, cookie)
+			}
+		})
+	}
+}
+
+func TestMiddlewareCookieAcceptLanguageChanged(t *testing.T) {
+	m := NewMiddleware(newTestBundle())
+	m.Sources = []Source{Query("lang"), Cookie("lang"), Header("Accept-Language")}
+	m.Cookie = &http.Cookie{Name: "lang", Path: "/"}
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	var cookies []*http.Cookie
+	for _, test := range []struct {
+		acceptLanguage string
+		expectedLang   string
+	}{
+		{"fr", "fr"},
+		{"es", "es"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Language", test.acceptLanguage)
+		for _, c := range cookies {
+			r.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if actual := w.Header().Get("Content-Language"); actual != test.expectedLang {
+			t.Errorf("expected Content-Language %q; got %q", test.expectedLang, actual)
+		}
+		// Languages that are only negotiated from Accept-Language are not persisted.
+		if cookie := w.Header().Get("Set-Cookie"); cookie != "" {
+			t.Errorf("expected no cookie; got %q", cookie)
+		}
+		cookies = append(cookies, w.Result().Cookies()...)
+	}
+}
+
+func TestLocalizerWithoutMiddleware(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if l := Localizer(r.Context()); l != nil {
+		t.Fatalf("expected nil localizer; got %#v", l)
+	}
+	if tag := Language(r.Context()); tag != language.Und {
+		t.Fatalf("expected %s; got %s", language.Und, tag)
+	}
+}
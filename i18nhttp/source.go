@@ -0,0 +1,115 @@
+package i18nhttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// Source resolves language preferences from a request.
+type Source interface {
+	// Languages returns the language preferences found in r.
+	// Each value may be a single language tag or an Accept-Language header value.
+	Languages(r *http.Request) []string
+}
+
+// SourceFunc is an adapter to allow the use of ordinary functions as a Source.
+type SourceFunc func(r *http.Request) []string
+
+// Languages calls f(r).
+func (f SourceFunc) Languages(r *http.Request) []string {
+	return f(r)
+}
+
+// explicitSource is a Source of languages that users choose explicitly,
+// which the Cookie of a Middleware persists.
+type explicitSource struct {
+	SourceFunc
+}
+
+// Query returns a Source that reads the language from the query parameter name.
+func Query(name string) Source {
+	return explicitSource{func(r *http.Request) []string {
+		return nonEmpty(r.URL.Query().Get(name))
+	}}
+}
+
+// Cookie returns a Source that reads the language from the cookie name.
+func Cookie(name string) Source {
+	return cookieSource(name)
+}
+
+type cookieSource string
+
+func (name cookieSource) Languages(r *http.Request) []string {
+	c, err := r.Cookie(string(name))
+	if err != nil {
+		return nil
+	}
+	return nonEmpty(c.Value)
+}
+
+// Header returns a Source that reads language preferences from the header name
+// (e.g. "Accept-Language").
+func Header(name string) Source {
+	return headerSource(http.CanonicalHeaderKey(name))
+}
+
+type headerSource string
+
+func (name headerSource) Languages(r *http.Request) []string {
+	return r.Header.Values(string(name))
+}
+
+// PathPrefix returns a Source that reads the language from the first segment of the request path
+// (e.g. "es" in "/es/about"). The segment is ignored unless it is a language tag whose language
+// is one of the languages of bundle (e.g. es or es-MX for a bundle with es),
+// so that paths like "/api/users" are not taken for a language.
+//
+// The request path is not modified, so handlers that do not expect the prefix
+// should be wrapped with http.StripPrefix or an equivalent.
+func PathPrefix(bundle *i18n.Bundle) Source {
+	return explicitSource{func(r *http.Request) []string {
+		segment := strings.TrimPrefix(r.URL.Path, "/")
+		if i := strings.IndexByte(segment, '/'); i >= 0 {
+			segment = segment[:i]
+		}
+		if segment == "" {
+			return nil
+		}
+		tag, err := language.Parse(segment)
+		if err != nil || !hasLanguage(bundle, tag) {
+			return nil
+		}
+		return []string{segment}
+	}}
+}
+
+// hasLanguage returns true if the language of tag is the language of one of the tags of bundle.
+func hasLanguage(bundle *i18n.Bundle, tag language.Tag) bool {
+	base, _ := tag.Base()
+	for _, t := range bundle.LanguageTags() {
+		if b, _ := t.Base(); b == base {
+			return true
+		}
+	}
+	return false
+}
+
+// User returns a Source that reads the language from a user profile.
+// lang is called for every request and should return an empty string if the
+// language is unknown (e.g. the user is not signed in).
+func User(lang func(r *http.Request) string) Source {
+	return explicitSource{func(r *http.Request) []string {
+		return nonEmpty(lang(r))
+	}}
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
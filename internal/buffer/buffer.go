@@ -0,0 +1,33 @@
+// Package buffer pools the buffers that messages are executed into.
+package buffer
+
+import (
+	"bytes"
+	"sync"
+)
+
+var pool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// maxPooledSize is the capacity of the largest buffer that is put back into the pool,
+// so that a few large messages do not keep their memory alive.
+const maxPooledSize = 64 << 10
+
+// Get returns an empty buffer from the pool.
+func Get() *bytes.Buffer {
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// Put returns buf to the pool. buf must not be used afterwards.
+func Put(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledSize {
+		return
+	}
+	buf.Reset()
+	pool.Put(buf)
+}
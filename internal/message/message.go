@@ -0,0 +1,34 @@
+// Package message contains helpers for the plural forms of messages
+// that are shared by the message formats.
+package message
+
+import (
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
+)
+
+// CountFields are the template fields that usually contain the plural count of a message.
+var CountFields = []string{"PluralCount", "Count"}
+
+// Forms returns the content of each plural form of m that is not empty.
+func Forms(m *i18n.Message) map[plural.Form]string {
+	forms := make(map[plural.Form]string)
+	for form, content := range map[plural.Form]string{
+		plural.Zero:  m.Zero,
+		plural.One:   m.One,
+		plural.Two:   m.Two,
+		plural.Few:   m.Few,
+		plural.Many:  m.Many,
+		plural.Other: m.Other,
+	} {
+		if content != "" {
+			forms[form] = content
+		}
+	}
+	return forms
+}
+
+// IsPlural returns true if a message with forms has plural forms other than the other form.
+func IsPlural(forms map[plural.Form]string) bool {
+	return len(forms) > 1 || len(forms) == 1 && forms[plural.Other] == ""
+}
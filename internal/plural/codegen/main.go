@@ -86,7 +86,8 @@ func DefaultRules() Rules {
 {{range .PluralGroups}}
 	addPluralRules(rules, {{printf "%#v" .SplitLocales}}, &Rule{
 		PluralForms: newPluralFormSet({{range $i, $e := .PluralRules}}{{if $i}}, {{end}}{{$e.CountTitle}}{{end}}),
-		PluralFormFunc: func(ops *Operands) Form { {{range .PluralRules}}{{if .GoCondition}}
+		GettextPluralForms: {{printf "%q" .GettextPluralForms}},
+		PluralFormFunc: func(ops Operands) Form { {{range .PluralRules}}{{if .GoCondition}}
 			// {{.Condition}}
 			if {{.GoCondition}} {
 				return {{.CountTitle}}
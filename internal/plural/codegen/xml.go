@@ -3,10 +3,10 @@ package main
 import (
 	"encoding/xml"
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/nicksnyder/go-i18n/v2/internal/plural"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -34,6 +34,16 @@ func (pg *PluralGroup) SplitLocales() []string {
 	return strings.Split(pg.Locales, " ")
 }
 
+// GettextPluralForms returns the value of the Plural-Forms header of gettext PO files for the plural group.
+// Plural forms are numbered in the order of their rules, which is the CLDR order.
+func (pg *PluralGroup) GettextPluralForms() string {
+	conditions := make([]plural.Condition, len(pg.PluralRules))
+	for i := range pg.PluralRules {
+		conditions[i] = pg.PluralRules[i].ParsedCondition()
+	}
+	return plural.GettextPluralForms(conditions)
+}
+
 // PluralRule is the rule for a single plural form.
 type PluralRule struct {
 	Count string `xml:"count,attr"`
@@ -91,60 +101,54 @@ func (pr *PluralRule) DecimalExamples() []string {
 	return decimal
 }
 
-var relationRegexp = regexp.MustCompile(`([niftvwce])(?:\s*%\s*([0-9]+))?\s*(!=|=)(.*)`)
+// ParsedCondition returns the parsed condition where the PluralRule applies.
+func (pr *PluralRule) ParsedCondition() plural.Condition {
+	c, err := plural.ParseCondition(pr.Condition())
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
 
 // GoCondition converts the XML condition to valid Go code.
 func (pr *PluralRule) GoCondition() string {
 	var ors []string
-	for _, and := range strings.Split(pr.Condition(), "or") {
+	for _, and := range pr.ParsedCondition() {
 		var ands []string
-		for _, relation := range strings.Split(and, "and") {
-			parts := relationRegexp.FindStringSubmatch(relation)
-			if parts == nil {
-				continue
-			}
-			lvar := cases.Title(language.AmericanEnglish).String(parts[1])
-			lmod, op, rhs := parts[2], parts[3], strings.TrimSpace(parts[4])
-			if op == "=" {
-				op = "=="
-			}
-			if lvar == "E" {
-				// E is a deprecated symbol for C
-				// https://unicode.org/reports/tr35/tr35-numbers.html#Plural_Operand_Meanings
-				lvar = "C"
-			}
-			lvar = "ops." + lvar
+		for _, relation := range and {
+			lvar := "ops." + strings.ToUpper(string(relation.Operand))
+			lmod := relation.Mod
 			var rhor []string
 			var rany []string
-			for _, rh := range strings.Split(rhs, ",") {
-				if parts := strings.Split(rh, ".."); len(parts) == 2 {
-					from, to := parts[0], parts[1]
+			for _, rng := range relation.Ranges {
+				if rng.From != rng.To {
+					from, to := rng.From, rng.To
 					if lvar == "ops.N" {
-						if lmod != "" {
-							rhor = append(rhor, fmt.Sprintf("ops.NModInRange(%s, %s, %s)", lmod, from, to))
+						if lmod != 0 {
+							rhor = append(rhor, fmt.Sprintf("ops.NModInRange(%d, %d, %d)", lmod, from, to))
 						} else {
-							rhor = append(rhor, fmt.Sprintf("ops.NInRange(%s, %s)", from, to))
+							rhor = append(rhor, fmt.Sprintf("ops.NInRange(%d, %d)", from, to))
 						}
-					} else if lmod != "" {
-						rhor = append(rhor, fmt.Sprintf("intInRange(%s %% %s, %s, %s)", lvar, lmod, from, to))
+					} else if lmod != 0 {
+						rhor = append(rhor, fmt.Sprintf("intInRange(%s %% %d, %d, %d)", lvar, lmod, from, to))
 					} else {
-						rhor = append(rhor, fmt.Sprintf("intInRange(%s, %s, %s)", lvar, from, to))
+						rhor = append(rhor, fmt.Sprintf("intInRange(%s, %d, %d)", lvar, from, to))
 					}
 				} else {
-					rany = append(rany, rh)
+					rany = append(rany, strconv.FormatInt(rng.From, 10))
 				}
 			}
 
 			if len(rany) > 0 {
 				rh := strings.Join(rany, ",")
 				if lvar == "ops.N" {
-					if lmod != "" {
-						rhor = append(rhor, fmt.Sprintf("ops.NModEqualsAny(%s, %s)", lmod, rh))
+					if lmod != 0 {
+						rhor = append(rhor, fmt.Sprintf("ops.NModEqualsAny(%d, %s)", lmod, rh))
 					} else {
 						rhor = append(rhor, fmt.Sprintf("ops.NEqualsAny(%s)", rh))
 					}
-				} else if lmod != "" {
-					rhor = append(rhor, fmt.Sprintf("intEqualsAny(%s %% %s, %s)", lvar, lmod, rh))
+				} else if lmod != 0 {
+					rhor = append(rhor, fmt.Sprintf("intEqualsAny(%s %% %d, %s)", lvar, lmod, rh))
 				} else {
 					rhor = append(rhor, fmt.Sprintf("intEqualsAny(%s, %s)", lvar, rh))
 				}
@@ -153,7 +157,7 @@ func (pr *PluralRule) GoCondition() string {
 			if len(rhor) > 1 {
 				r = "(" + r + ")"
 			}
-			if op == "!=" {
+			if relation.Negated {
 				r = "!" + r
 			}
 			ands = append(ands, r)
@@ -0,0 +1,346 @@
+package plural
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Condition is a parsed CLDR plural rule condition (e.g. "n % 10 = 1 and n % 100 != 11").
+// It is true if all relations of any of its AND conditions are true.
+// An empty condition is always true.
+// http://unicode.org/reports/tr35/tr35-numbers.html#Plural_rules_syntax
+type Condition [][]Relation
+
+// Relation compares an operand, optionally modulo Mod, with a list of ranges.
+type Relation struct {
+	// Operand is one of the operands n, i, v, w, f, t or c.
+	// The deprecated operand e is parsed as c.
+	Operand byte
+
+	// Mod is the value of the modulus, or 0 if there is none.
+	Mod int64
+
+	// Negated is true if the relation is != instead of =.
+	Negated bool
+
+	// Ranges are the values and ranges that the operand is compared with.
+	Ranges []Range
+}
+
+// Range is a closed interval of integers. A single value is a range where From equals To.
+type Range struct {
+	From, To int64
+}
+
+// ParseCondition parses a CLDR plural rule condition.
+// Samples (e.g. "@integer 1, 21, 31") are ignored.
+func ParseCondition(s string) (Condition, error) {
+	if i := strings.IndexByte(s, '@'); i >= 0 {
+		s = s[:i]
+	}
+	p := &conditionParser{src: s}
+	var c Condition
+	if p.done() {
+		return c, nil
+	}
+	for {
+		var and []Relation
+		for {
+			r, err := p.relation()
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, r)
+			if !p.keyword("and") {
+				break
+			}
+		}
+		c = append(c, and)
+		if !p.keyword("or") {
+			break
+		}
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %q", strings.TrimSpace(p.src[p.pos:]))
+	}
+	return c, nil
+}
+
+// conditionParser parses the relations of a condition.
+type conditionParser struct {
+	src string
+	pos int
+}
+
+func (p *conditionParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid condition %q: %s", strings.TrimSpace(p.src), fmt.Sprintf(format, args...))
+}
+
+func (p *conditionParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n' || p.src[p.pos] == '\r') {
+		p.pos++
+	}
+}
+
+func (p *conditionParser) done() bool {
+	p.skipSpace()
+	return p.pos == len(p.src)
+}
+
+// consume returns true and skips s if the source continues with s.
+func (p *conditionParser) consume(s string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// word returns the letters at the current position.
+func (p *conditionParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && 'a' <= p.src[p.pos] && p.src[p.pos] <= 'z' {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// keyword returns true and skips the keyword if it is the next word.
+func (p *conditionParser) keyword(keyword string) bool {
+	pos := p.pos
+	if p.word() == keyword {
+		return true
+	}
+	p.pos = pos
+	return false
+}
+
+func (p *conditionParser) number() (int64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && '0' <= p.src[p.pos] && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, p.errorf("expected number at %q", p.src[start:])
+	}
+	n, err := strconv.ParseInt(p.src[start:p.pos], 10, 64)
+	if err != nil {
+		return 0, p.errorf("%s", err)
+	}
+	return n, nil
+}
+
+func (p *conditionParser) relation() (Relation, error) {
+	var r Relation
+	switch operand := p.word(); operand {
+	case "n", "i", "v", "w", "f", "t", "c":
+		r.Operand = operand[0]
+	case "e":
+		// e is a deprecated symbol for c.
+		r.Operand = 'c'
+	default:
+		return r, p.errorf("expected operand at %q", p.src[p.pos-len(operand):])
+	}
+	if p.consume("%") {
+		mod, err := p.number()
+		if err != nil {
+			return r, err
+		}
+		if mod == 0 {
+			return r, p.errorf("modulus must not be 0")
+		}
+		r.Mod = mod
+	}
+	switch {
+	case p.consume("!="):
+		r.Negated = true
+	case p.consume("="):
+	default:
+		return r, p.errorf("expected = or != at %q", p.src[p.pos:])
+	}
+	for {
+		from, err := p.number()
+		if err != nil {
+			return r, err
+		}
+		to := from
+		if p.consume("..") {
+			if to, err = p.number(); err != nil {
+				return r, err
+			}
+			if to < from {
+				return r, p.errorf("invalid range %d..%d", from, to)
+			}
+		}
+		r.Ranges = append(r.Ranges, Range{From: from, To: to})
+		if !p.consume(",") {
+			return r, nil
+		}
+	}
+}
+
+// Matches returns true if the condition is true for ops.
+func (c Condition) Matches(ops Operands) bool {
+	if len(c) == 0 {
+		return true
+	}
+	for _, and := range c {
+		matches := true
+		for i := range and {
+			if !and[i].matches(&ops) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Relation) matches(ops *Operands) bool {
+	var v int64
+	switch r.Operand {
+	case 'n':
+		// n only equals the integers in ranges if it has no fraction digits.
+		if ops.T != 0 {
+			return r.Negated
+		}
+		v = ops.I
+	case 'i':
+		v = ops.I
+	case 'v':
+		v = ops.V
+	case 'w':
+		v = ops.W
+	case 'f':
+		v = ops.F
+	case 't':
+		v = ops.T
+	case 'c':
+		v = ops.C
+	}
+	if r.Mod != 0 {
+		v %= r.Mod
+	}
+	for _, rng := range r.Ranges {
+		if rng.From <= v && v <= rng.To {
+			return !r.Negated
+		}
+	}
+	return r.Negated
+}
+
+// containsZero returns true if the ranges of r contain 0.
+func (r *Relation) containsZero() bool {
+	for _, rng := range r.Ranges {
+		if rng.From <= 0 && 0 <= rng.To {
+			return true
+		}
+	}
+	return false
+}
+
+// CExpression returns the condition as a C expression of n that is evaluated by gettext.
+// Gettext only evaluates plural rules for integers, which have no visible fraction digits
+// or exponent, so all operands other than n and i are 0.
+// It returns "1" or "0" if the condition is always or never true for integers.
+func (c Condition) CExpression() string {
+	if len(c) == 0 {
+		return "1"
+	}
+	var ors []string
+	for _, and := range c {
+		var ands []string
+		never := false
+		for _, r := range and {
+			if r.Operand != 'n' && r.Operand != 'i' {
+				if r.containsZero() == r.Negated {
+					never = true
+					break
+				}
+				continue
+			}
+			lhs := "n"
+			if r.Mod != 0 {
+				lhs = fmt.Sprintf("n %% %d", r.Mod)
+			}
+			var terms []string
+			for _, rng := range r.Ranges {
+				switch {
+				case rng.From != rng.To && !r.Negated:
+					terms = append(terms, fmt.Sprintf("(%s >= %d && %s <= %d)", lhs, rng.From, lhs, rng.To))
+				case rng.From != rng.To:
+					terms = append(terms, fmt.Sprintf("(%s < %d || %s > %d)", lhs, rng.From, lhs, rng.To))
+				case !r.Negated:
+					terms = append(terms, fmt.Sprintf("%s == %d", lhs, rng.From))
+				default:
+					terms = append(terms, fmt.Sprintf("%s != %d", lhs, rng.From))
+				}
+			}
+			if !r.Negated && len(terms) > 1 {
+				ands = append(ands, "("+strings.Join(terms, " || ")+")")
+			} else {
+				ands = append(ands, strings.Join(terms, " && "))
+			}
+		}
+		if never {
+			continue
+		}
+		if len(ands) == 0 {
+			return "1"
+		}
+		ors = append(ors, strings.Join(ands, " && "))
+	}
+	if len(ors) == 0 {
+		return "0"
+	}
+	return strings.Join(ors, " || ")
+}
+
+// GettextPluralForms returns the value of the Plural-Forms header of gettext PO files
+// for the conditions of plural forms in CLDR order. The last condition is not used
+// because the last plural form is used if no other condition is true.
+func GettextPluralForms(conditions []Condition) string {
+	n := len(conditions)
+	expr := strconv.Itoa(n - 1)
+	for i := n - 2; i >= 0; i-- {
+		switch c := conditions[i].CExpression(); c {
+		case "0":
+		case "1":
+			expr = strconv.Itoa(i)
+		default:
+			if hasTopLevelOr(c) {
+				c = "(" + c + ")"
+			}
+			expr = fmt.Sprintf("%s ? %d : %s", c, i, expr)
+		}
+	}
+	if strings.Contains(expr, "?") {
+		expr = "(" + expr + ")"
+	}
+	return fmt.Sprintf("nplurals=%d; plural=%s;", n, expr)
+}
+
+// hasTopLevelOr returns true if the C expression c contains || outside of parentheses.
+func hasTopLevelOr(c string) bool {
+	depth := 0
+	for i := 0; i < len(c); i++ {
+		switch c[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '|':
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package plural
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		src       string
+		condition Condition
+	}{
+		{"", nil},
+		{" @integer 0~15, 100, 1000 ", nil},
+		{"n = 1", Condition{{{Operand: 'n', Ranges: []Range{{1, 1}}}}}},
+		{"i = 0,1 @integer 0, 1 @decimal 0.0~1.5", Condition{{{Operand: 'i', Ranges: []Range{{0, 0}, {1, 1}}}}}},
+		{"n % 10 = 1 and n % 100 != 11", Condition{{
+			{Operand: 'n', Mod: 10, Ranges: []Range{{1, 1}}},
+			{Operand: 'n', Mod: 100, Negated: true, Ranges: []Range{{11, 11}}},
+		}}},
+		{"v = 0 and i % 10 = 2..4 and i % 100 != 12..14 or f % 10 = 2..4", Condition{
+			{
+				{Operand: 'v', Ranges: []Range{{0, 0}}},
+				{Operand: 'i', Mod: 10, Ranges: []Range{{2, 4}}},
+				{Operand: 'i', Mod: 100, Negated: true, Ranges: []Range{{12, 14}}},
+			},
+			{
+				{Operand: 'f', Mod: 10, Ranges: []Range{{2, 4}}},
+			},
+		}},
+		{"e = 0 and i != 0 and i % 1000000 = 0 and v = 0 or e != 0..5", Condition{
+			{
+				{Operand: 'c', Ranges: []Range{{0, 0}}},
+				{Operand: 'i', Negated: true, Ranges: []Range{{0, 0}}},
+				{Operand: 'i', Mod: 1000000, Ranges: []Range{{0, 0}}},
+				{Operand: 'v', Ranges: []Range{{0, 0}}},
+			},
+			{
+				{Operand: 'c', Negated: true, Ranges: []Range{{0, 5}}},
+			},
+		}},
+		{"n%10=3..4,9 and n%100!=10..19,70..79,90..99", Condition{{
+			{Operand: 'n', Mod: 10, Ranges: []Range{{3, 4}, {9, 9}}},
+			{Operand: 'n', Mod: 100, Negated: true, Ranges: []Range{{10, 19}, {70, 79}, {90, 99}}},
+		}}},
+	}
+	for _, test := range tests {
+		condition, err := ParseCondition(test.src)
+		if err != nil {
+			t.Errorf("ParseCondition(%q) unexpected error: %s", test.src, err)
+		} else if !reflect.DeepEqual(condition, test.condition) {
+			t.Errorf("ParseCondition(%q) returned %#v; expected %#v", test.src, condition, test.condition)
+		}
+	}
+}
+
+func TestParseConditionInvalid(t *testing.T) {
+	for _, src := range []string{
+		"n",
+		"n = ",
+		"x = 1",
+		"n == 1",
+		"n < 1",
+		"n % 0 = 1",
+		"n % = 1",
+		"n = 1..",
+		"n = 4..2",
+		"n = 1,",
+		"n = 1 and",
+		"n = 1 or or n = 2",
+		"n = 1 n = 2",
+		"n = 99999999999999999999",
+	} {
+		if condition, err := ParseCondition(src); err == nil {
+			t.Errorf("ParseCondition(%q) returned %#v; expected error", src, condition)
+		}
+	}
+}
+
+func TestNewRuleInvalid(t *testing.T) {
+	for _, conditions := range []map[Form]string{
+		{"single": "n = 1"},
+		{One: "n ="},
+		{One: ""},
+		{One: "@integer 1"},
+		{One: "n = 1", Other: "n ="},
+	} {
+		if _, err := NewRule(conditions); err == nil {
+			t.Errorf("NewRule(%v) expected error", conditions)
+		}
+	}
+}
+
+// TestNewRuleCLDR checks that rules created from the CLDR conditions that the default rules are generated from
+// return the same plural forms as the default rules.
+func TestNewRuleCLDR(t *testing.T) {
+	buf, err := os.ReadFile("codegen/plurals.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		PluralGroups []struct {
+			Locales     string `xml:"locales,attr"`
+			PluralRules []struct {
+				Count string `xml:"count,attr"`
+				Rule  string `xml:",innerxml"`
+			} `xml:"pluralRule"`
+		} `xml:"plurals>pluralRules"`
+	}
+	if err := xml.Unmarshal(buf, &data); err != nil {
+		t.Fatal(err)
+	}
+	var numbers []interface{}
+	for i := 0; i <= 1200; i++ {
+		numbers = append(numbers, i)
+	}
+	for i := 0; i <= 300; i++ {
+		numbers = append(numbers, fmt.Sprintf("%d.%d", i/10, i%10), fmt.Sprintf("%d.%02d", i/100, i%100))
+	}
+	numbers = append(numbers, 1000000, "1000000.0", "1e6", "1.2e6", "1.5e3", "2e3", "0.0001")
+
+	defaultRules := DefaultRules()
+	for _, pg := range data.PluralGroups {
+		conditions := make(map[Form]string)
+		for _, pr := range pg.PluralRules {
+			conditions[Form(pr.Count)] = pr.Rule
+		}
+		rule, err := NewRule(conditions)
+		if err != nil {
+			t.Errorf("%s: %s", pg.Locales, err)
+			continue
+		}
+		id := strings.Fields(pg.Locales)[0]
+		if id == "root" {
+			continue
+		}
+		defaultRule := defaultRules.Rule(language.MustParse(id))
+		if !reflect.DeepEqual(rule.PluralForms, defaultRule.PluralForms) {
+			t.Errorf("%s: expected plural forms %v; got %v", id, defaultRule.PluralForms, rule.PluralForms)
+		}
+		if rule.GettextPluralForms != defaultRule.GettextPluralForms {
+			t.Errorf("%s: expected %q; got %q", id, defaultRule.GettextPluralForms, rule.GettextPluralForms)
+		}
+		for _, number := range numbers {
+			ops, err := NewOperands(number)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if form, expected := rule.PluralFormFunc(ops), defaultRule.PluralFormFunc(ops); form != expected {
+				t.Errorf("%s: %v returned %s; expected %s", id, number, form, expected)
+				break
+			}
+		}
+	}
+}
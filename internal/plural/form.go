@@ -14,3 +14,6 @@ const (
 	Many    Form = "many"
 	Other   Form = "other"
 )
+
+// Forms are all plural forms in CLDR order.
+var Forms = []Form{Zero, One, Two, Few, Many, Other}
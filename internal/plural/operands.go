@@ -53,7 +53,7 @@ func (o *Operands) NModInRange(mod, from, to int64) bool {
 }
 
 // NewOperands returns the operands for number.
-func NewOperands(number interface{}) (*Operands, error) {
+func NewOperands(number interface{}) (Operands, error) {
 	switch number := number.(type) {
 	case int:
 		return newOperandsInt64(int64(number)), nil
@@ -68,17 +68,17 @@ func NewOperands(number interface{}) (*Operands, error) {
 	case string:
 		return newOperandsString(number)
 	case float32, float64:
-		return nil, fmt.Errorf("floats should be formatted into a string")
+		return Operands{}, fmt.Errorf("floats should be formatted into a string")
 	default:
-		return nil, fmt.Errorf("invalid type %T; expected integer or string", number)
+		return Operands{}, fmt.Errorf("invalid type %T; expected integer or string", number)
 	}
 }
 
-func newOperandsInt64(i int64) *Operands {
+func newOperandsInt64(i int64) Operands {
 	if i < 0 {
 		i = -i
 	}
-	return &Operands{float64(i), i, 0, 0, 0, 0, 0}
+	return Operands{float64(i), i, 0, 0, 0, 0, 0}
 }
 
 func splitSignificandExponent(s string) (significand, exponent string) {
@@ -137,15 +137,15 @@ func applyExponent(s string, exponent int) string {
 	return s
 }
 
-func newOperandsString(s string) (*Operands, error) {
+func newOperandsString(s string) (Operands, error) {
 	if s[0] == '-' {
 		s = s[1:]
 	}
-	ops := &Operands{}
+	ops := Operands{}
 	var err error
 	ops.N, err = strconv.ParseFloat(s, 64)
 	if err != nil {
-		return nil, err
+		return Operands{}, err
 	}
 	significand, exponent := splitSignificandExponent(s)
 	if exponent != "" {
@@ -154,14 +154,14 @@ func newOperandsString(s string) (*Operands, error) {
 		// so C is safe to cast as a int later.
 		ops.C, err = strconv.ParseInt(exponent, 10, 0)
 		if err != nil {
-			return nil, err
+			return Operands{}, err
 		}
 	}
 	value := applyExponent(significand, int(ops.C))
 	parts := strings.SplitN(value, ".", 2)
 	ops.I, err = strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
-		return nil, err
+		return Operands{}, err
 	}
 	if len(parts) == 1 {
 		return ops, nil
@@ -177,14 +177,14 @@ func newOperandsString(s string) (*Operands, error) {
 	if ops.V > 0 {
 		f, err := strconv.ParseInt(fraction, 10, 0)
 		if err != nil {
-			return nil, err
+			return Operands{}, err
 		}
 		ops.F = f
 	}
 	if ops.W > 0 {
 		t, err := strconv.ParseInt(fraction[:ops.W], 10, 0)
 		if err != nil {
-			return nil, err
+			return Operands{}, err
 		}
 		ops.T = t
 	}
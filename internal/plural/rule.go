@@ -1,6 +1,8 @@
 package plural
 
 import (
+	"fmt"
+
 	"golang.org/x/text/language"
 )
 
@@ -9,7 +11,73 @@ import (
 // http://unicode.org/reports/tr35/tr35-numbers.html#Operands
 type Rule struct {
 	PluralForms    map[Form]struct{}
-	PluralFormFunc func(*Operands) Form
+	PluralFormFunc func(Operands) Form
+
+	// GettextPluralForms is the Plural-Forms header of gettext PO files (e.g. "nplurals=2; plural=(n != 1);").
+	// Gettext numbers plural forms in CLDR order.
+	GettextPluralForms string
+}
+
+// formOrder is the CLDR order of plural forms.
+var formOrder = []Form{Zero, One, Two, Few, Many, Other}
+
+// SortedPluralForms returns the plural forms of the rule in CLDR order.
+func (r *Rule) SortedPluralForms() []Form {
+	forms := make([]Form, 0, len(r.PluralForms))
+	for _, form := range formOrder {
+		if _, ok := r.PluralForms[form]; ok {
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
+
+// NewRule returns a rule from the CLDR conditions of its plural forms (e.g. "i = 1 and v = 0" for One).
+// The Other form is used if no other condition is true, so its condition is optional and not evaluated.
+func NewRule(conditions map[Form]string) (*Rule, error) {
+	for form := range conditions {
+		if !containsForm(formOrder, form) {
+			return nil, fmt.Errorf("invalid plural form %q", form)
+		}
+	}
+	var forms []Form
+	var conds []Condition
+	for _, form := range formOrder {
+		src, ok := conditions[form]
+		if !ok && form != Other {
+			continue
+		}
+		cond, err := ParseCondition(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", form, err)
+		}
+		if len(cond) == 0 && form != Other {
+			return nil, fmt.Errorf("%s: empty condition", form)
+		}
+		forms = append(forms, form)
+		conds = append(conds, cond)
+	}
+	return &Rule{
+		PluralForms: newPluralFormSet(forms...),
+		PluralFormFunc: func(ops Operands) Form {
+			for i, cond := range conds[:len(conds)-1] {
+				if cond.Matches(ops) {
+					return forms[i]
+				}
+			}
+			return Other
+		},
+		GettextPluralForms: GettextPluralForms(conds),
+	}, nil
+}
+
+func containsForm(forms []Form, form Form) bool {
+	for _, f := range forms {
+		if f == form {
+			return true
+		}
+	}
+	return false
 }
 
 func addPluralRules(rules Rules, ids []string, ps *Rule) {
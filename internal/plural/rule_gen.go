@@ -7,14 +7,16 @@ func DefaultRules() Rules {
 	rules := Rules{}
 
 	addPluralRules(rules, []string{"bm", "bo", "dz", "hnj", "id", "ig", "ii", "in", "ja", "jbo", "jv", "jw", "kde", "kea", "km", "ko", "lkt", "lo", "ms", "my", "nqo", "osa", "root", "sah", "ses", "sg", "su", "th", "to", "tpi", "vi", "wo", "yo", "yue", "zh"}, &Rule{
-		PluralForms: newPluralFormSet(Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(Other),
+		GettextPluralForms: "nplurals=1; plural=0;",
+		PluralFormFunc: func(ops Operands) Form {
 			return Other
 		},
 	})
 	addPluralRules(rules, []string{"am", "as", "bn", "doi", "fa", "gu", "hi", "kn", "kok", "kok_Latn", "pcm", "zu"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=((n == 0 || n == 1) ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 0 or n = 1
 			if intEqualsAny(ops.I, 0) ||
 				ops.NEqualsAny(1) {
@@ -24,8 +26,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"ff", "hy", "kab"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=((n == 0 || n == 1) ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 0,1
 			if intEqualsAny(ops.I, 0, 1) {
 				return One
@@ -34,8 +37,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"ast", "de", "en", "et", "fi", "fy", "gl", "ia", "ie", "io", "ji", "lij", "nl", "sc", "sv", "sw", "ur", "yi"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=(n == 1 ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 1 and v = 0
 			if intEqualsAny(ops.I, 1) && intEqualsAny(ops.V, 0) {
 				return One
@@ -44,8 +48,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"si"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=((n == 0 || n == 1) ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 0,1 or i = 0 and f = 1
 			if ops.NEqualsAny(0, 1) ||
 				intEqualsAny(ops.I, 0) && intEqualsAny(ops.F, 1) {
@@ -55,8 +60,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"ak", "bho", "csw", "guw", "ln", "mg", "nso", "pa", "ti", "wa"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=((n >= 0 && n <= 1) ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 0..1
 			if ops.NInRange(0, 1) {
 				return One
@@ -65,8 +71,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"tzm"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=(((n >= 0 && n <= 1) || (n >= 11 && n <= 99)) ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 0..1 or n = 11..99
 			if ops.NInRange(0, 1) ||
 				ops.NInRange(11, 99) {
@@ -76,8 +83,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"af", "an", "asa", "az", "bal", "bem", "bez", "bg", "brx", "ce", "cgg", "chr", "ckb", "dv", "ee", "el", "eo", "eu", "fo", "fur", "gsw", "ha", "haw", "hu", "jgo", "jmc", "ka", "kaj", "kcg", "kk", "kkj", "kl", "ks", "ksb", "ku", "ky", "lb", "lg", "mas", "mgo", "ml", "mn", "mr", "nah", "nb", "nd", "ne", "nn", "nnh", "no", "nr", "ny", "nyn", "om", "or", "os", "pap", "ps", "rm", "rof", "rwk", "saq", "sd", "sdh", "seh", "sn", "so", "sq", "ss", "ssy", "st", "syr", "ta", "te", "teo", "tig", "tk", "tn", "tr", "ts", "ug", "uz", "ve", "vo", "vun", "wae", "xh", "xog"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=(n == 1 ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 1
 			if ops.NEqualsAny(1) {
 				return One
@@ -86,8 +94,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"da"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=(n == 1 ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 1 or t != 0 and i = 0,1
 			if ops.NEqualsAny(1) ||
 				!intEqualsAny(ops.T, 0) && intEqualsAny(ops.I, 0, 1) {
@@ -97,8 +106,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"is"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// t = 0 and i % 10 = 1 and i % 100 != 11 or t % 10 = 1 and t % 100 != 11
 			if intEqualsAny(ops.T, 0) && intEqualsAny(ops.I%10, 1) && !intEqualsAny(ops.I%100, 11) ||
 				intEqualsAny(ops.T%10, 1) && !intEqualsAny(ops.T%100, 11) {
@@ -108,8 +118,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"mk"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// v = 0 and i % 10 = 1 and i % 100 != 11 or f % 10 = 1 and f % 100 != 11
 			if intEqualsAny(ops.V, 0) && intEqualsAny(ops.I%10, 1) && !intEqualsAny(ops.I%100, 11) ||
 				intEqualsAny(ops.F%10, 1) && !intEqualsAny(ops.F%100, 11) {
@@ -119,8 +130,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"ceb", "fil", "tl"}, &Rule{
-		PluralForms: newPluralFormSet(One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Other),
+		GettextPluralForms: "nplurals=2; plural=(((n == 1 || n == 2 || n == 3) || n % 10 != 4 && n % 10 != 6 && n % 10 != 9) ? 0 : 1);",
+		PluralFormFunc: func(ops Operands) Form {
 			// v = 0 and i = 1,2,3 or v = 0 and i % 10 != 4,6,9 or v != 0 and f % 10 != 4,6,9
 			if intEqualsAny(ops.V, 0) && intEqualsAny(ops.I, 1, 2, 3) ||
 				intEqualsAny(ops.V, 0) && !intEqualsAny(ops.I%10, 4, 6, 9) ||
@@ -131,8 +143,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"lv", "prg"}, &Rule{
-		PluralForms: newPluralFormSet(Zero, One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(Zero, One, Other),
+		GettextPluralForms: "nplurals=3; plural=((n % 10 == 0 || (n % 100 >= 11 && n % 100 <= 19)) ? 0 : n % 10 == 1 && n % 100 != 11 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n % 10 = 0 or n % 100 = 11..19 or v = 2 and f % 100 = 11..19
 			if ops.NModEqualsAny(10, 0) ||
 				ops.NModInRange(100, 11, 19) ||
@@ -149,8 +162,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"lag"}, &Rule{
-		PluralForms: newPluralFormSet(Zero, One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(Zero, One, Other),
+		GettextPluralForms: "nplurals=3; plural=(n == 0 ? 0 : (n == 0 || n == 1) && n != 0 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 0
 			if ops.NEqualsAny(0) {
 				return Zero
@@ -163,8 +177,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"blo", "cv", "ksh"}, &Rule{
-		PluralForms: newPluralFormSet(Zero, One, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(Zero, One, Other),
+		GettextPluralForms: "nplurals=3; plural=(n == 0 ? 0 : n == 1 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 0
 			if ops.NEqualsAny(0) {
 				return Zero
@@ -177,8 +192,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"he", "iw"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Other),
+		GettextPluralForms: "nplurals=3; plural=(n == 1 ? 0 : n == 2 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 1 and v = 0 or i = 0 and v != 0
 			if intEqualsAny(ops.I, 1) && intEqualsAny(ops.V, 0) ||
 				intEqualsAny(ops.I, 0) && !intEqualsAny(ops.V, 0) {
@@ -192,8 +208,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"iu", "naq", "sat", "se", "sma", "smi", "smj", "smn", "sms"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Other),
+		GettextPluralForms: "nplurals=3; plural=(n == 1 ? 0 : n == 2 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 1
 			if ops.NEqualsAny(1) {
 				return One
@@ -206,8 +223,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"shi"}, &Rule{
-		PluralForms: newPluralFormSet(One, Few, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Few, Other),
+		GettextPluralForms: "nplurals=3; plural=((n == 0 || n == 1) ? 0 : (n >= 2 && n <= 10) ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 0 or n = 1
 			if intEqualsAny(ops.I, 0) ||
 				ops.NEqualsAny(1) {
@@ -221,8 +239,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"mo", "ro"}, &Rule{
-		PluralForms: newPluralFormSet(One, Few, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Few, Other),
+		GettextPluralForms: "nplurals=3; plural=(n == 1 ? 0 : (n == 0 || n != 1 && (n % 100 >= 1 && n % 100 <= 19)) ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 1 and v = 0
 			if intEqualsAny(ops.I, 1) && intEqualsAny(ops.V, 0) {
 				return One
@@ -237,8 +256,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"bs", "hr", "sh", "sr"}, &Rule{
-		PluralForms: newPluralFormSet(One, Few, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Few, Other),
+		GettextPluralForms: "nplurals=3; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : (n % 10 >= 2 && n % 10 <= 4) && (n % 100 < 12 || n % 100 > 14) ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// v = 0 and i % 10 = 1 and i % 100 != 11 or f % 10 = 1 and f % 100 != 11
 			if intEqualsAny(ops.V, 0) && intEqualsAny(ops.I%10, 1) && !intEqualsAny(ops.I%100, 11) ||
 				intEqualsAny(ops.F%10, 1) && !intEqualsAny(ops.F%100, 11) {
@@ -253,8 +273,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"fr"}, &Rule{
-		PluralForms: newPluralFormSet(One, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Many, Other),
+		GettextPluralForms: "nplurals=3; plural=((n == 0 || n == 1) ? 0 : n != 0 && n % 1000000 == 0 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 0,1
 			if intEqualsAny(ops.I, 0, 1) {
 				return One
@@ -268,8 +289,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"pt"}, &Rule{
-		PluralForms: newPluralFormSet(One, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Many, Other),
+		GettextPluralForms: "nplurals=3; plural=((n >= 0 && n <= 1) ? 0 : n != 0 && n % 1000000 == 0 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 0..1
 			if intInRange(ops.I, 0, 1) {
 				return One
@@ -283,8 +305,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"ca", "it", "lld", "pt_PT", "scn", "vec"}, &Rule{
-		PluralForms: newPluralFormSet(One, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Many, Other),
+		GettextPluralForms: "nplurals=3; plural=(n == 1 ? 0 : n != 0 && n % 1000000 == 0 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 1 and v = 0
 			if intEqualsAny(ops.I, 1) && intEqualsAny(ops.V, 0) {
 				return One
@@ -298,8 +321,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"es"}, &Rule{
-		PluralForms: newPluralFormSet(One, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Many, Other),
+		GettextPluralForms: "nplurals=3; plural=(n == 1 ? 0 : n != 0 && n % 1000000 == 0 ? 1 : 2);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 1
 			if ops.NEqualsAny(1) {
 				return One
@@ -313,8 +337,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"gd"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Few, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Few, Other),
+		GettextPluralForms: "nplurals=4; plural=((n == 1 || n == 11) ? 0 : (n == 2 || n == 12) ? 1 : ((n >= 3 && n <= 10) || (n >= 13 && n <= 19)) ? 2 : 3);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 1,11
 			if ops.NEqualsAny(1, 11) {
 				return One
@@ -331,8 +356,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"sl"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Few, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Few, Other),
+		GettextPluralForms: "nplurals=4; plural=(n % 100 == 1 ? 0 : n % 100 == 2 ? 1 : (n % 100 >= 3 && n % 100 <= 4) ? 2 : 3);",
+		PluralFormFunc: func(ops Operands) Form {
 			// v = 0 and i % 100 = 1
 			if intEqualsAny(ops.V, 0) && intEqualsAny(ops.I%100, 1) {
 				return One
@@ -350,8 +376,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"dsb", "hsb"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Few, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Few, Other),
+		GettextPluralForms: "nplurals=4; plural=(n % 100 == 1 ? 0 : n % 100 == 2 ? 1 : (n % 100 >= 3 && n % 100 <= 4) ? 2 : 3);",
+		PluralFormFunc: func(ops Operands) Form {
 			// v = 0 and i % 100 = 1 or f % 100 = 1
 			if intEqualsAny(ops.V, 0) && intEqualsAny(ops.I%100, 1) ||
 				intEqualsAny(ops.F%100, 1) {
@@ -371,8 +398,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"cs", "sk"}, &Rule{
-		PluralForms: newPluralFormSet(One, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Few, Many, Other),
+		GettextPluralForms: "nplurals=4; plural=(n == 1 ? 0 : (n >= 2 && n <= 4) ? 1 : 3);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 1 and v = 0
 			if intEqualsAny(ops.I, 1) && intEqualsAny(ops.V, 0) {
 				return One
@@ -389,8 +417,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"pl"}, &Rule{
-		PluralForms: newPluralFormSet(One, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Few, Many, Other),
+		GettextPluralForms: "nplurals=4; plural=(n == 1 ? 0 : (n % 10 >= 2 && n % 10 <= 4) && (n % 100 < 12 || n % 100 > 14) ? 1 : (n != 1 && (n % 10 >= 0 && n % 10 <= 1) || (n % 10 >= 5 && n % 10 <= 9) || (n % 100 >= 12 && n % 100 <= 14)) ? 2 : 3);",
+		PluralFormFunc: func(ops Operands) Form {
 			// i = 1 and v = 0
 			if intEqualsAny(ops.I, 1) && intEqualsAny(ops.V, 0) {
 				return One
@@ -409,8 +438,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"be"}, &Rule{
-		PluralForms: newPluralFormSet(One, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Few, Many, Other),
+		GettextPluralForms: "nplurals=4; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : (n % 10 >= 2 && n % 10 <= 4) && (n % 100 < 12 || n % 100 > 14) ? 1 : (n % 10 == 0 || (n % 10 >= 5 && n % 10 <= 9) || (n % 100 >= 11 && n % 100 <= 14)) ? 2 : 3);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n % 10 = 1 and n % 100 != 11
 			if ops.NModEqualsAny(10, 1) && !ops.NModEqualsAny(100, 11) {
 				return One
@@ -429,8 +459,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"lt"}, &Rule{
-		PluralForms: newPluralFormSet(One, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Few, Many, Other),
+		GettextPluralForms: "nplurals=4; plural=(n % 10 == 1 && (n % 100 < 11 || n % 100 > 19) ? 0 : (n % 10 >= 2 && n % 10 <= 9) && (n % 100 < 11 || n % 100 > 19) ? 1 : 3);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n % 10 = 1 and n % 100 != 11..19
 			if ops.NModEqualsAny(10, 1) && !ops.NModInRange(100, 11, 19) {
 				return One
@@ -447,8 +478,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"ru", "uk"}, &Rule{
-		PluralForms: newPluralFormSet(One, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Few, Many, Other),
+		GettextPluralForms: "nplurals=4; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : (n % 10 >= 2 && n % 10 <= 4) && (n % 100 < 12 || n % 100 > 14) ? 1 : (n % 10 == 0 || (n % 10 >= 5 && n % 10 <= 9) || (n % 100 >= 11 && n % 100 <= 14)) ? 2 : 3);",
+		PluralFormFunc: func(ops Operands) Form {
 			// v = 0 and i % 10 = 1 and i % 100 != 11
 			if intEqualsAny(ops.V, 0) && intEqualsAny(ops.I%10, 1) && !intEqualsAny(ops.I%100, 11) {
 				return One
@@ -467,8 +499,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"sgs"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Few, Many, Other),
+		GettextPluralForms: "nplurals=5; plural=(n % 10 == 1 && n % 100 != 11 ? 0 : n == 2 ? 1 : n != 2 && (n % 10 >= 2 && n % 10 <= 9) && (n % 100 < 11 || n % 100 > 19) ? 2 : 4);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n % 10 = 1 and n % 100 != 11
 			if ops.NModEqualsAny(10, 1) && !ops.NModEqualsAny(100, 11) {
 				return One
@@ -489,8 +522,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"br"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Few, Many, Other),
+		GettextPluralForms: "nplurals=5; plural=(n % 10 == 1 && n % 100 != 11 && n % 100 != 71 && n % 100 != 91 ? 0 : n % 10 == 2 && n % 100 != 12 && n % 100 != 72 && n % 100 != 92 ? 1 : ((n % 10 >= 3 && n % 10 <= 4) || n % 10 == 9) && (n % 100 < 10 || n % 100 > 19) && (n % 100 < 70 || n % 100 > 79) && (n % 100 < 90 || n % 100 > 99) ? 2 : n != 0 && n % 1000000 == 0 ? 3 : 4);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n % 10 = 1 and n % 100 != 11,71,91
 			if ops.NModEqualsAny(10, 1) && !ops.NModEqualsAny(100, 11, 71, 91) {
 				return One
@@ -511,8 +545,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"mt"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Few, Many, Other),
+		GettextPluralForms: "nplurals=5; plural=(n == 1 ? 0 : n == 2 ? 1 : (n == 0 || (n % 100 >= 3 && n % 100 <= 10)) ? 2 : (n % 100 >= 11 && n % 100 <= 19) ? 3 : 4);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 1
 			if ops.NEqualsAny(1) {
 				return One
@@ -534,8 +569,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"ga"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Few, Many, Other),
+		GettextPluralForms: "nplurals=5; plural=(n == 1 ? 0 : n == 2 ? 1 : (n >= 3 && n <= 6) ? 2 : (n >= 7 && n <= 10) ? 3 : 4);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 1
 			if ops.NEqualsAny(1) {
 				return One
@@ -556,8 +592,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"gv"}, &Rule{
-		PluralForms: newPluralFormSet(One, Two, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(One, Two, Few, Many, Other),
+		GettextPluralForms: "nplurals=5; plural=(n % 10 == 1 ? 0 : n % 10 == 2 ? 1 : (n % 100 == 0 || n % 100 == 20 || n % 100 == 40 || n % 100 == 60 || n % 100 == 80) ? 2 : 4);",
+		PluralFormFunc: func(ops Operands) Form {
 			// v = 0 and i % 10 = 1
 			if intEqualsAny(ops.V, 0) && intEqualsAny(ops.I%10, 1) {
 				return One
@@ -578,8 +615,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"kw"}, &Rule{
-		PluralForms: newPluralFormSet(Zero, One, Two, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(Zero, One, Two, Few, Many, Other),
+		GettextPluralForms: "nplurals=6; plural=(n == 0 ? 0 : n == 1 ? 1 : ((n % 100 == 2 || n % 100 == 22 || n % 100 == 42 || n % 100 == 62 || n % 100 == 82) || n % 1000 == 0 && ((n % 100000 >= 1000 && n % 100000 <= 20000) || n % 100000 == 40000 || n % 100000 == 60000 || n % 100000 == 80000) || n != 0 && n % 1000000 == 100000) ? 2 : (n % 100 == 3 || n % 100 == 23 || n % 100 == 43 || n % 100 == 63 || n % 100 == 83) ? 3 : n != 1 && (n % 100 == 1 || n % 100 == 21 || n % 100 == 41 || n % 100 == 61 || n % 100 == 81) ? 4 : 5);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 0
 			if ops.NEqualsAny(0) {
 				return Zero
@@ -606,8 +644,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"ar", "ars"}, &Rule{
-		PluralForms: newPluralFormSet(Zero, One, Two, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(Zero, One, Two, Few, Many, Other),
+		GettextPluralForms: "nplurals=6; plural=(n == 0 ? 0 : n == 1 ? 1 : n == 2 ? 2 : (n % 100 >= 3 && n % 100 <= 10) ? 3 : (n % 100 >= 11 && n % 100 <= 99) ? 4 : 5);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 0
 			if ops.NEqualsAny(0) {
 				return Zero
@@ -632,8 +671,9 @@ func DefaultRules() Rules {
 		},
 	})
 	addPluralRules(rules, []string{"cy"}, &Rule{
-		PluralForms: newPluralFormSet(Zero, One, Two, Few, Many, Other),
-		PluralFormFunc: func(ops *Operands) Form {
+		PluralForms:        newPluralFormSet(Zero, One, Two, Few, Many, Other),
+		GettextPluralForms: "nplurals=6; plural=(n == 0 ? 0 : n == 1 ? 1 : n == 2 ? 2 : n == 3 ? 3 : n == 6 ? 4 : 5);",
+		PluralFormFunc: func(ops Operands) Form {
 			// n = 0
 			if ops.NEqualsAny(0) {
 				return Zero
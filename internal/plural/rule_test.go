@@ -90,3 +90,151 @@ func increment(dec string) string {
 	}
 	return string(runes)
 }
+
+func TestGettextPluralForms(t *testing.T) {
+	tested := make(map[*Rule]bool)
+	for tag, rule := range DefaultRules() {
+		if tested[rule] {
+			continue
+		}
+		tested[rule] = true
+		forms := rule.SortedPluralForms()
+		nplurals, expr, ok := strings.Cut(strings.TrimSuffix(rule.GettextPluralForms, ";"), "; plural=")
+		if !ok || nplurals != "nplurals="+strconv.Itoa(len(forms)) {
+			t.Errorf("%s: unexpected Plural-Forms %q for %d plural forms", tag, rule.GettextPluralForms, len(forms))
+			continue
+		}
+		nums := []int64{1000000, 2000000, 1100000}
+		for n := int64(0); n <= 2000; n++ {
+			nums = append(nums, n)
+		}
+		for _, n := range nums {
+			ops, err := NewOperands(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			i := evalC(t, expr, n)
+			if i < 0 || i >= int64(len(forms)) {
+				t.Errorf("%s: %q returned %d for %d", tag, expr, i, n)
+				break
+			}
+			if expected := rule.PluralFormFunc(ops); forms[i] != expected {
+				t.Errorf("%s: %q returned %s for %d; expected %s", tag, expr, forms[i], n, expected)
+				break
+			}
+		}
+	}
+}
+
+// evalC evaluates a gettext plural expression for n.
+func evalC(t *testing.T, expr string, n int64) int64 {
+	p := &cParser{s: strings.ReplaceAll(expr, " ", ""), n: n}
+	v := p.ternary()
+	if p.s != "" {
+		t.Fatalf("unexpected %q in %q", p.s, expr)
+	}
+	return v
+}
+
+// cParser evaluates the subset of C that is used by gettext plural expressions.
+type cParser struct {
+	s string
+	n int64
+}
+
+func (p *cParser) consume(token string) bool {
+	if strings.HasPrefix(p.s, token) {
+		p.s = p.s[len(token):]
+		return true
+	}
+	return false
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *cParser) ternary() int64 {
+	cond := p.or()
+	if !p.consume("?") {
+		return cond
+	}
+	a := p.ternary()
+	p.consume(":")
+	b := p.ternary()
+	if cond != 0 {
+		return a
+	}
+	return b
+}
+
+func (p *cParser) or() int64 {
+	v := p.and()
+	for p.consume("||") {
+		w := p.and()
+		v = boolInt(v != 0 || w != 0)
+	}
+	return v
+}
+
+func (p *cParser) and() int64 {
+	v := p.comparison()
+	for p.consume("&&") {
+		w := p.comparison()
+		v = boolInt(v != 0 && w != 0)
+	}
+	return v
+}
+
+func (p *cParser) comparison() int64 {
+	v := p.mod()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			w := p.mod()
+			switch op {
+			case "==":
+				return boolInt(v == w)
+			case "!=":
+				return boolInt(v != w)
+			case "<=":
+				return boolInt(v <= w)
+			case ">=":
+				return boolInt(v >= w)
+			case "<":
+				return boolInt(v < w)
+			default:
+				return boolInt(v > w)
+			}
+		}
+	}
+	return v
+}
+
+func (p *cParser) mod() int64 {
+	v := p.operand()
+	for p.consume("%") {
+		v %= p.operand()
+	}
+	return v
+}
+
+func (p *cParser) operand() int64 {
+	if p.consume("(") {
+		v := p.ternary()
+		p.consume(")")
+		return v
+	}
+	if p.consume("n") {
+		return p.n
+	}
+	i := 0
+	for i < len(p.s) && '0' <= p.s[i] && p.s[i] <= '9' {
+		i++
+	}
+	v, _ := strconv.ParseInt(p.s[:i], 10, 64)
+	p.s = p.s[i:]
+	return v
+}
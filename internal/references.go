@@ -0,0 +1,272 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"text/template/parse"
+)
+
+// TemplateFields returns the sorted names of the top level fields of the template data
+// that src references (e.g. "Name" for "{{.Name}}" and "User" for "{{.User.Name}}").
+// Fields referenced inside range and with actions are relative to a different value
+// and are not returned.
+func TemplateFields(src, leftDelim, rightDelim string) ([]string, error) {
+	fields, _, err := TemplateReferences(src, leftDelim, rightDelim)
+	return fields, err
+}
+
+// TemplateReferences returns the sorted names of the top level fields of the template data
+// and the sorted names of the functions (e.g. "printf" for "{{printf "%d" .Count}}") that src references.
+func TemplateReferences(src, leftDelim, rightDelim string) (fields, funcs []string, err error) {
+	trees, err := parseTrees(src, leftDelim, rightDelim)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs := &references{
+		fields: map[string]struct{}{},
+		funcs:  map[string]struct{}{},
+	}
+	for _, tree := range trees {
+		if tree.Root != nil {
+			refs.walk(tree.Root, true)
+		}
+	}
+	return sortedKeys(refs.fields), sortedKeys(refs.funcs), nil
+}
+
+// TemplatePlainFields returns the sorted names of the top level fields of the template data
+// that src only prints as they are (e.g. "Name" for "{{.Name}}" but not for "{{.Name | upper}}" or "{{if .Name}}").
+func TemplatePlainFields(src, leftDelim, rightDelim string) ([]string, error) {
+	trees, err := parseTrees(src, leftDelim, rightDelim)
+	if err != nil {
+		return nil, err
+	}
+	refs := &references{
+		fields:  map[string]struct{}{},
+		funcs:   map[string]struct{}{},
+		printed: map[string]struct{}{},
+	}
+	for _, tree := range trees {
+		if tree.Root != nil {
+			refs.walk(tree.Root, true)
+		}
+	}
+	for field := range refs.fields {
+		delete(refs.printed, field)
+	}
+	return sortedKeys(refs.printed), nil
+}
+
+// TemplatePart is text or a top level field of the template data that a template prints.
+type TemplatePart struct {
+	Text string
+
+	// Field is the name of the printed field (e.g. "Name" for "{{.Name}}"), or "" if the part is text.
+	Field string
+
+	// Action is the source of template actions (e.g. "{{if .Admin}}") that are returned
+	// by TemplateActions, or "" if the part is text.
+	Action string
+}
+
+// TemplateParts splits src into text and the top level fields of the template data that it prints,
+// so that it can be converted to the interpolation syntax of other libraries.
+// It returns an error if an action of src does more than print a top level field.
+func TemplateParts(src, leftDelim, rightDelim string) ([]TemplatePart, error) {
+	trees, err := parseTrees(src, leftDelim, rightDelim)
+	if err != nil {
+		return nil, err
+	}
+	if len(trees) != 1 {
+		return nil, fmt.Errorf("template definitions can not be converted")
+	}
+	var parts []TemplatePart
+	for _, tree := range trees {
+		if tree.Root == nil {
+			break
+		}
+		for _, node := range tree.Root.Nodes {
+			switch n := node.(type) {
+			case *parse.TextNode:
+				parts = append(parts, TemplatePart{Text: string(n.Text)})
+			case *parse.ActionNode:
+				field := dataField(n, true)
+				if field == "" {
+					return nil, fmt.Errorf("template action %s can not be converted", n)
+				}
+				parts = append(parts, TemplatePart{Field: field})
+			default:
+				return nil, fmt.Errorf("template action %s can not be converted", n)
+			}
+		}
+	}
+	return parts, nil
+}
+
+// TemplateActions splits src into text and the source of the template actions between the text,
+// so that formats that only translate text can keep the actions as they are.
+// Adjacent actions (e.g. "{{.Count}}{{.Unit}}") are one part.
+func TemplateActions(src, leftDelim, rightDelim string) ([]TemplatePart, error) {
+	trees, err := parseTrees(src, leftDelim, rightDelim)
+	if err != nil {
+		return nil, err
+	}
+	var texts []*parse.TextNode
+	for _, tree := range trees {
+		if tree.Root != nil {
+			texts = appendTextNodes(texts, tree.Root)
+		}
+	}
+	sort.Slice(texts, func(i, j int) bool {
+		return texts[i].Pos < texts[j].Pos
+	})
+	var parts []TemplatePart
+	end := 0
+	for _, text := range texts {
+		// The text of a node is the source at its position, so the source in between is actions.
+		if start := int(text.Pos); start > end {
+			parts = append(parts, TemplatePart{Action: src[end:start]})
+		}
+		parts = append(parts, TemplatePart{Text: string(text.Text)})
+		end = int(text.Pos) + len(text.Text)
+	}
+	if end < len(src) {
+		parts = append(parts, TemplatePart{Action: src[end:]})
+	}
+	return parts, nil
+}
+
+// appendTextNodes appends the text nodes of node and its branches to texts.
+func appendTextNodes(texts []*parse.TextNode, node parse.Node) []*parse.TextNode {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return texts
+		}
+		for _, child := range n.Nodes {
+			texts = appendTextNodes(texts, child)
+		}
+	case *parse.TextNode:
+		texts = append(texts, n)
+	case *parse.IfNode:
+		texts = appendTextNodes(appendTextNodes(texts, n.List), n.ElseList)
+	case *parse.RangeNode:
+		texts = appendTextNodes(appendTextNodes(texts, n.List), n.ElseList)
+	case *parse.WithNode:
+		texts = appendTextNodes(appendTextNodes(texts, n.List), n.ElseList)
+	}
+	return texts
+}
+
+// dataField returns the name of the top level field of the template data that n prints,
+// or "" if it does anything else.
+func dataField(n *parse.ActionNode, dot bool) string {
+	if len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) != 1 || len(n.Pipe.Cmds[0].Args) != 1 {
+		return ""
+	}
+	switch arg := n.Pipe.Cmds[0].Args[0].(type) {
+	case *parse.FieldNode:
+		if dot && len(arg.Ident) == 1 {
+			return arg.Ident[0]
+		}
+	case *parse.VariableNode:
+		if len(arg.Ident) == 2 && arg.Ident[0] == "$" {
+			return arg.Ident[1]
+		}
+	}
+	return ""
+}
+
+func parseTrees(src, leftDelim, rightDelim string) (map[string]*parse.Tree, error) {
+	if leftDelim == "" {
+		leftDelim = "{{"
+	}
+	if rightDelim == "" {
+		rightDelim = "}}"
+	}
+	tree := parse.New("")
+	// Functions are provided at execution time so they are unknown here.
+	tree.Mode = parse.SkipFuncCheck
+	trees := map[string]*parse.Tree{}
+	if _, err := tree.Parse(src, leftDelim, rightDelim, trees); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+type references struct {
+	fields map[string]struct{}
+	funcs  map[string]struct{}
+
+	// printed are the fields that are printed as they are by actions.
+	// If printed is nil, these fields are recorded in fields.
+	printed map[string]struct{}
+}
+
+// walk records the references of node.
+// dot is true if dot refers to the template data.
+func (r *references) walk(node parse.Node, dot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			r.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		if field := dataField(n, dot); field != "" && r.printed != nil {
+			r.printed[field] = struct{}{}
+			return
+		}
+		r.walk(n.Pipe, dot)
+	case *parse.IfNode:
+		r.walk(n.Pipe, dot)
+		r.walk(n.List, dot)
+		r.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		// Dot is reassigned inside of the range so only the pipeline and else branch refer to the template data.
+		r.walk(n.Pipe, dot)
+		r.walk(n.List, false)
+		r.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		r.walk(n.Pipe, dot)
+		r.walk(n.List, false)
+		r.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		r.walk(n.Pipe, dot)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			r.walk(cmd, dot)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			r.walk(arg, dot)
+		}
+	case *parse.IdentifierNode:
+		r.funcs[n.Ident] = struct{}{}
+	case *parse.FieldNode:
+		if dot {
+			r.fields[n.Ident[0]] = struct{}{}
+		}
+	case *parse.VariableNode:
+		// $ is the template data even where dot is reassigned.
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			r.fields[n.Ident[1]] = struct{}{}
+		}
+	case *parse.ChainNode:
+		r.walk(n.Node, dot)
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
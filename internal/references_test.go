@@ -0,0 +1,241 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTemplateFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		leftDelim  string
+		rightDelim string
+		fields     []string
+		err        bool
+	}{
+		{
+			name:   "no actions",
+			src:    "hello",
+			fields: []string{},
+		},
+		{
+			name:   "fields",
+			src:    "{{.Name}} has {{.Count}} {{.Name}} {{.User.Email}}",
+			fields: []string{"Count", "Name", "User"},
+		},
+		{
+			name:   "functions and pipelines",
+			src:    "{{.Name | upper}} {{printf \"%d\" .Count}} {{(.User).Email}}",
+			fields: []string{"Count", "Name", "User"},
+		},
+		{
+			name:   "branches",
+			src:    "{{if .A}}{{.B}}{{else}}{{.C}}{{end}}{{range .D}}{{.E}}{{else}}{{.F}}{{end}}{{with .G}}{{.H}}{{end}}",
+			fields: []string{"A", "B", "C", "D", "F", "G"},
+		},
+		{
+			name:   "functions inside of branches",
+			src:    "{{range .Items}}{{upper .Name}}{{end}}",
+			fields: []string{"Items"},
+		},
+		{
+			name:   "variables",
+			src:    "{{range .Items}}{{$.Name}} {{$x := .}}{{$x.Y}}{{end}}{{with $.User.Email}}{{.}}{{end}}",
+			fields: []string{"Items", "Name", "User"},
+		},
+		{
+			name:       "custom delimiters",
+			src:        "<<.Name>> {{.Other}}",
+			leftDelim:  "<<",
+			rightDelim: ">>",
+			fields:     []string{"Name"},
+		},
+		{
+			name: "invalid template",
+			src:  "{{.Name",
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields, err := TemplateFields(test.src, test.leftDelim, test.rightDelim)
+			if test.err != (err != nil) {
+				t.Fatalf("expected error %v; got %v", test.err, err)
+			}
+			if !test.err && !reflect.DeepEqual(fields, test.fields) {
+				t.Fatalf("expected %#v; got %#v", test.fields, fields)
+			}
+		})
+	}
+}
+
+func TestTemplatePlainFields(t *testing.T) {
+	fields, err := TemplatePlainFields(`{{.Name}} {{$.Email}} {{.Count | printf "%d"}} {{if .Admin}}{{.Role}}{{end}} {{.User.Name}} {{.Title}} {{upper .Title}}`, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Email", "Name", "Role"}; !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %#v; got %#v", expected, fields)
+	}
+}
+
+func TestTemplateReferences(t *testing.T) {
+	fields, funcs, err := TemplateReferences(`{{.Name | upper}} {{printf "%d" .Count}} {{range .Items}}{{lower .}}{{end}}`, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Count", "Items", "Name"}; !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected fields %#v; got %#v", expected, fields)
+	}
+	if expected := []string{"lower", "printf", "upper"}; !reflect.DeepEqual(funcs, expected) {
+		t.Errorf("expected funcs %#v; got %#v", expected, funcs)
+	}
+}
+
+func TestTemplateParts(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		leftDelim  string
+		rightDelim string
+		parts      []TemplatePart
+		err        bool
+	}{
+		{
+			name: "empty",
+			src:  "",
+		},
+		{
+			name: "fields",
+			src:  "{{.Name}} has {{ .Count }} cats",
+			parts: []TemplatePart{
+				{Field: "Name"},
+				{Text: " has "},
+				{Field: "Count"},
+				{Text: " cats"},
+			},
+		},
+		{
+			name:       "delims",
+			src:        "Hello <<.Name>>",
+			leftDelim:  "<<",
+			rightDelim: ">>",
+			parts: []TemplatePart{
+				{Text: "Hello "},
+				{Field: "Name"},
+			},
+		},
+		{
+			name: "nested field",
+			src:  "{{.User.Name}}",
+			err:  true,
+		},
+		{
+			name: "function",
+			src:  "{{upper .Name}}",
+			err:  true,
+		},
+		{
+			name: "branch",
+			src:  "{{if .Name}}Hello{{end}}",
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts, err := TemplateParts(test.src, test.leftDelim, test.rightDelim)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected error; got %#v", parts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(parts, test.parts) {
+				t.Errorf("expected %#v; got %#v", test.parts, parts)
+			}
+		})
+	}
+}
+
+func TestTemplateActions(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		leftDelim  string
+		rightDelim string
+		parts      []TemplatePart
+		err        bool
+	}{
+		{
+			name:  "text",
+			src:   "Hello",
+			parts: []TemplatePart{{Text: "Hello"}},
+		},
+		{
+			name: "actions",
+			src:  "Hello {{.Name}}, you have {{.Count}} emails",
+			parts: []TemplatePart{
+				{Text: "Hello "},
+				{Action: "{{.Name}}"},
+				{Text: ", you have "},
+				{Action: "{{.Count}}"},
+				{Text: " emails"},
+			},
+		},
+		{
+			name: "adjacent actions",
+			src:  `{{printf "}}" .Name}}{{.Count}}`,
+			parts: []TemplatePart{
+				{Action: `{{printf "}}" .Name}}{{.Count}}`},
+			},
+		},
+		{
+			name: "branches and trim markers",
+			src:  "{{if .Admin}}Admin {{- else}} User{{end}}",
+			parts: []TemplatePart{
+				{Action: "{{if .Admin}}"},
+				{Text: "Admin"},
+				{Action: " {{- else}}"},
+				{Text: " User"},
+				{Action: "{{end}}"},
+			},
+		},
+		{
+			name:       "delims",
+			src:        "Hello <<.Name>> {{.Name}}",
+			leftDelim:  "<<",
+			rightDelim: ">>",
+			parts: []TemplatePart{
+				{Text: "Hello "},
+				{Action: "<<.Name>>"},
+				{Text: " {{.Name}}"},
+			},
+		},
+		{
+			name: "invalid",
+			src:  "Hello {{.Name",
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts, err := TemplateActions(test.src, test.leftDelim, test.rightDelim)
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected error; got %#v", parts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(parts, test.parts) {
+				t.Errorf("expected %#v; got %#v", test.parts, parts)
+			}
+		})
+	}
+}
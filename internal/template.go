@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"io"
 	"sync"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n/template"
@@ -15,22 +16,69 @@ type Template struct {
 	parseOnce      sync.Once
 	parsedTemplate template.ParsedTemplate
 	parseError     error
+
+	// reusableTemplates caches the parse results of reusable parsers by their parse key.
+	reusableTemplates sync.Map
+}
+
+type parseResult struct {
+	parsedTemplate template.ParsedTemplate
+	parseError     error
 }
 
 func (t *Template) Execute(parser template.Parser, data interface{}) (string, error) {
-	var pt template.ParsedTemplate
-	var err error
+	pt, rp, err := t.parse(parser)
+	if err != nil {
+		return "", err
+	}
+	if rp != nil {
+		return rp.Execute(pt, data)
+	}
+	return pt.Execute(data)
+}
+
+// ExecuteTo is similar to Execute except it writes the output to w.
+// Part of the output may have been written to w if an error happens.
+func (t *Template) ExecuteTo(parser template.Parser, w io.Writer, data interface{}) error {
+	pt, rp, err := t.parse(parser)
+	if err != nil {
+		return err
+	}
+	if wt, ok := pt.(template.WriterTemplate); ok && rp == nil {
+		return wt.ExecuteTo(w, data)
+	}
+	var s string
+	if rp != nil {
+		s, err = rp.Execute(pt, data)
+	} else {
+		s, err = pt.Execute(data)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, s)
+	return err
+}
+
+// parse returns the parsed template of parser.
+// The parsed template must be executed by the returned ReusableParser if it is not nil.
+func (t *Template) parse(parser template.Parser) (template.ParsedTemplate, template.ReusableParser, error) {
 	if parser.Cacheable() {
 		t.parseOnce.Do(func() {
 			t.parsedTemplate, t.parseError = parser.Parse(t.Src, t.LeftDelim, t.RightDelim)
 		})
-		pt, err = t.parsedTemplate, t.parseError
-	} else {
-		pt, err = parser.Parse(t.Src, t.LeftDelim, t.RightDelim)
+		return t.parsedTemplate, nil, t.parseError
 	}
-
-	if err != nil {
-		return "", err
+	if rp, ok := parser.(template.ReusableParser); ok {
+		key := rp.ParseKey()
+		v, ok := t.reusableTemplates.Load(key)
+		if !ok {
+			pt, err := parser.Parse(t.Src, t.LeftDelim, t.RightDelim)
+			v, _ = t.reusableTemplates.LoadOrStore(key, &parseResult{parsedTemplate: pt, parseError: err})
+		}
+		r := v.(*parseResult)
+		return r.parsedTemplate, rp, r.parseError
 	}
-	return pt.Execute(data)
+	pt, err := parser.Parse(t.Src, t.LeftDelim, t.RightDelim)
+	return pt, nil, err
 }
@@ -83,3 +83,73 @@ func str(err error) string {
 	}
 	return err.Error()
 }
+
+func TestExecuteFuncsReused(t *testing.T) {
+	tmpl := &Template{Src: "hello {{name}}"}
+	funcs := func(name string) texttemplate.FuncMap {
+		return texttemplate.FuncMap{"name": func() string { return name }}
+	}
+	for _, name := range []string{"world", "gopher", "world"} {
+		result, err := tmpl.Execute(&template.TextParser{Funcs: funcs(name)}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "hello " + name; result != expected {
+			t.Errorf("expected result %q; got %q", expected, result)
+		}
+	}
+	parsed := 0
+	tmpl.reusableTemplates.Range(func(key, value any) bool {
+		parsed++
+		return true
+	})
+	if parsed != 1 {
+		t.Errorf("expected the template to be parsed once; got %d", parsed)
+	}
+
+	// Templates are parsed again for functions with other names.
+	if _, err := tmpl.Execute(&template.TextParser{Funcs: texttemplate.FuncMap{"other": strings.ToUpper}}, nil); err == nil {
+		t.Errorf("expected error for undefined function")
+	}
+}
+
+func TestExecuteFuncsChanged(t *testing.T) {
+	tmpl := &Template{Src: "hello {{name}}"}
+	funcs := texttemplate.FuncMap{"name": func() string { return "world" }}
+	parser := &template.TextParser{Funcs: funcs}
+	for _, name := range []string{"world", "gopher"} {
+		// Functions that are changed in the same map are used by later executions.
+		funcs["name"] = func() string { return name }
+		result, err := tmpl.Execute(parser, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "hello " + name; result != expected {
+			t.Errorf("expected result %q; got %q", expected, result)
+		}
+	}
+}
+
+func BenchmarkExecuteFuncs(b *testing.B) {
+	tmpl := &Template{Src: "Hello {{upper .Name}}, you have {{.Count}} new messages"}
+	data := map[string]interface{}{"Name": "gopher", "Count": 3}
+	funcs := texttemplate.FuncMap{"upper": strings.ToUpper}
+	b.Run("same FuncMap", func(b *testing.B) {
+		parser := &template.TextParser{Funcs: funcs}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := tmpl.Execute(parser, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("new FuncMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			parser := &template.TextParser{Funcs: texttemplate.FuncMap{"upper": strings.ToUpper}}
+			if _, err := tmpl.Execute(parser, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}